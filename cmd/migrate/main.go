@@ -0,0 +1,127 @@
+// Command migrate applies, rolls back and creates the versioned schema migrations in
+// ./migrations, run it from the project root.
+//
+//	go run ./cmd/migrate up                   apply every pending migration
+//	go run ./cmd/migrate up 1                 apply the next pending migration
+//	go run ./cmd/migrate down                 roll back the last applied migration
+//	go run ./cmd/migrate down 3               roll back the last three
+//	go run ./cmd/migrate status
+//	go run ./cmd/migrate create add_student_address
+//
+// The baseline that adopted the tables from before migrations is never rolled back, down
+// stops above it.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go_starter/database"
+	"go_starter/logs"
+	"go_starter/migrations"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+func main() {
+	dir := flag.String("dir", "migrations", "directory new migrations are created in")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: migrate [-dir migrations] up [n] | down [n] | status | create <name>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	command := flag.Arg(0)
+	if command == "create" {
+		if flag.NArg() != 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		created, err := migrations.Create(*dir, flag.Arg(1), time.Now())
+		if err != nil {
+			fail(err)
+		}
+		for _, file := range created {
+			fmt.Println("created", file)
+		}
+		return
+	}
+
+	dbConnection, err := database.Connection()
+	if err != nil {
+		fail(err)
+	}
+	migrator, err := migrations.NewMigrator(dbConnection)
+	if err != nil {
+		fail(err)
+	}
+
+	switch command {
+	case "up":
+		done, err := migrator.Up(steps(0))
+		for _, migration := range done {
+			fmt.Println("applied", migration.Version, migration.Name)
+		}
+		if err != nil {
+			fail(err)
+		}
+		if len(done) == 0 {
+			fmt.Println("schema is up to date")
+		}
+	case "down":
+		done, err := migrator.Down(steps(1))
+		for _, migration := range done {
+			fmt.Println("rolled back", migration.Version, migration.Name)
+		}
+		if err != nil {
+			fail(err)
+		}
+		if len(done) == 0 {
+			fmt.Println("nothing to roll back")
+		}
+	case "status":
+		status, err := migrator.Status()
+		if err != nil {
+			fail(err)
+		}
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(writer, "VERSION\tNAME\tSTATUS\tAPPLIED AT\n")
+		for _, row := range status {
+			state, appliedAt := "pending", ""
+			if row.Applied {
+				state = "applied"
+				appliedAt = row.AppliedAt.Format("02-01-2006 15:04:05")
+			}
+			if row.Unknown {
+				state = "applied, no file"
+			}
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", row.Version, row.Name, state, appliedAt)
+		}
+		writer.Flush()
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+// steps reads the optional step count after the command
+func steps(fallback int) int {
+	if flag.NArg() < 2 {
+		return fallback
+	}
+	n, err := strconv.Atoi(flag.Arg(1))
+	if err != nil || n < 1 {
+		fail(fmt.Errorf("invalid step count %q", flag.Arg(1)))
+	}
+	return n
+}
+
+func fail(err error) {
+	logs.Error(err)
+	os.Exit(1)
+}
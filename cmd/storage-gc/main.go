@@ -0,0 +1,77 @@
+// Command storage-gc discards expired resumable uploads and reconciles stored student
+// images and documents against the database.
+//
+//	go run ./cmd/storage-gc -dry-run
+//	go run ./cmd/storage-gc -grace 24h
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go_starter/database"
+	"go_starter/logs"
+	"go_starter/migrations"
+	"go_starter/repositories"
+	"go_starter/requests"
+	"go_starter/services"
+	"go_starter/storage"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report what would be deleted without deleting")
+	grace := flag.Duration("grace", time.Hour, "keep files younger than this, their upload may still be committing")
+	flag.Parse()
+
+	dbConnection, err := database.Connection()
+	if err != nil {
+		logs.Error(err)
+		os.Exit(1)
+	}
+	if err = migrations.Check(dbConnection); err != nil {
+		logs.Error(err)
+		os.Exit(1)
+	}
+	fileStorage, err := storage.NewStorage()
+	if err != nil {
+		logs.Error(err)
+		os.Exit(1)
+	}
+
+	fileRepository := repositories.NewFileRepository(dbConnection)
+	studentRepository := repositories.NewStudentRepository(dbConnection)
+	userRepository := repositories.NewUserRepository(dbConnection)
+	fileService := services.NewFileService(fileRepository, studentRepository, userRepository, fileStorage)
+	uploadService := services.NewUploadService(repositories.NewUploadRepository(dbConnection), fileStorage)
+
+	// Interrupting stops the running query, a lagging replica could miss a fresh reference
+	ctx, stop := signal.NotifyContext(database.WithPrimary(context.Background()), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	output := map[string]interface{}{}
+	if !*dryRun {
+		expired, err := uploadService.ExpireUploadSessionsService(ctx)
+		if err != nil {
+			logs.Error(err)
+			os.Exit(1)
+		}
+		output["uploads"] = expired
+	}
+
+	response, err := fileService.CollectStorageGarbageService(ctx, requests.StorageGCRequest{
+		DryRun:      *dryRun,
+		GracePeriod: *grace,
+	})
+	if err != nil {
+		logs.Error(err)
+		os.Exit(1)
+	}
+	output["storage"] = response
+	result, _ := json.MarshalIndent(output, "", "  ")
+	fmt.Println(string(result))
+}
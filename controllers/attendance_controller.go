@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go_starter/logs"
+	"go_starter/requests"
+	"go_starter/services"
+	"go_starter/validation"
+	"strings"
+)
+
+type AttendanceController interface {
+	CreateClassroomSessionController(ctx *fiber.Ctx) error
+	GetClassroomSessionsController(ctx *fiber.Ctx) error
+
+	MarkAttendanceController(ctx *fiber.Ctx) error
+	GetSessionAttendanceController(ctx *fiber.Ctx) error
+
+	GetStudentAttendanceSummaryController(ctx *fiber.Ctx) error
+	GetClassroomAttendanceSummaryController(ctx *fiber.Ctx) error
+	GetAttendanceAlertsController(ctx *fiber.Ctx) error
+
+	//check-in
+	GenerateCheckInCodeController(ctx *fiber.Ctx) error
+	CheckInController(ctx *fiber.Ctx) error
+}
+
+type attendanceController struct {
+	serviceAttendance services.AttendanceService
+}
+
+func (a *attendanceController) CreateClassroomSessionController(ctx *fiber.Ctx) error {
+	request := new(requests.ClassroomSessionRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		logs.Error(err)
+		return NewErrorResponses(ctx, err)
+	}
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := a.serviceAttendance.CreateClassroomSessionService(ctx.UserContext(), *request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewSuccessResponse(ctx, response)
+}
+
+func (a *attendanceController) GetClassroomSessionsController(ctx *fiber.Ctx) error {
+	request := new(requests.ClassroomIDRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		logs.Error(err)
+		return NewErrorResponses(ctx, err)
+	}
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := a.serviceAttendance.GetClassroomSessionsService(ctx.UserContext(), *request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewSuccessResponse(ctx, response)
+}
+
+func (a *attendanceController) MarkAttendanceController(ctx *fiber.Ctx) error {
+	request := new(requests.BulkAttendanceRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		logs.Error(err)
+		return NewErrorResponses(ctx, err)
+	}
+	request.AccessToken = strings.TrimPrefix(ctx.Get(fiber.HeaderAuthorization), "Bearer ")
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := a.serviceAttendance.MarkAttendanceService(ctx.UserContext(), *request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewSuccessResponse(ctx, response)
+}
+
+func (a *attendanceController) GetSessionAttendanceController(ctx *fiber.Ctx) error {
+	request := new(requests.ClassroomSessionIDRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		logs.Error(err)
+		return NewErrorResponses(ctx, err)
+	}
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := a.serviceAttendance.GetSessionAttendanceService(ctx.UserContext(), *request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewSuccessResponse(ctx, response)
+}
+
+func (a *attendanceController) GetStudentAttendanceSummaryController(ctx *fiber.Ctx) error {
+	request := new(requests.StudentAttendanceRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		logs.Error(err)
+		return NewErrorResponses(ctx, err)
+	}
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := a.serviceAttendance.GetStudentAttendanceSummaryService(ctx.UserContext(), *request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewSuccessResponse(ctx, response)
+}
+
+func (a *attendanceController) GetClassroomAttendanceSummaryController(ctx *fiber.Ctx) error {
+	request := new(requests.ClassroomIDRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		logs.Error(err)
+		return NewErrorResponses(ctx, err)
+	}
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := a.serviceAttendance.GetClassroomAttendanceSummaryService(ctx.UserContext(), *request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewSuccessResponse(ctx, response)
+}
+
+func (a *attendanceController) GetAttendanceAlertsController(ctx *fiber.Ctx) error {
+	request := new(requests.AttendanceAlertRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		logs.Error(err)
+		return NewErrorResponses(ctx, err)
+	}
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := a.serviceAttendance.GetAttendanceAlertsService(ctx.UserContext(), *request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewSuccessResponse(ctx, response)
+}
+
+func (a *attendanceController) GenerateCheckInCodeController(ctx *fiber.Ctx) error {
+	request := new(requests.CheckInCodeRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		logs.Error(err)
+		return NewErrorResponses(ctx, err)
+	}
+	request.AccessToken = strings.TrimPrefix(ctx.Get(fiber.HeaderAuthorization), "Bearer ")
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := a.serviceAttendance.GenerateCheckInCodeService(ctx.UserContext(), *request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewSuccessResponse(ctx, response)
+}
+
+func (a *attendanceController) CheckInController(ctx *fiber.Ctx) error {
+	request := new(requests.CheckInRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		logs.Error(err)
+		return NewErrorResponses(ctx, err)
+	}
+	request.AccessToken = strings.TrimPrefix(ctx.Get(fiber.HeaderAuthorization), "Bearer ")
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := a.serviceAttendance.CheckInService(ctx.UserContext(), *request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewSuccessResponse(ctx, response)
+}
+
+func NewAttendanceController(serviceAttendance services.AttendanceService) AttendanceController {
+	return &attendanceController{serviceAttendance: serviceAttendance}
+}
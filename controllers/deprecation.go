@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
+	"go_starter/config"
+	"go_starter/logs"
+	"net/http"
+	"time"
+)
+
+// Deprecated marks the responses of the routes after it as deprecated in favour of
+// successor, with the Sunset date configured under app.legacy_sunset if any
+func Deprecated(successor string) fiber.Handler {
+	var sunset string
+	if date := config.GetEnv("app.legacy_sunset", ""); date != "" {
+		if parsed, err := time.Parse(time.DateOnly, date); err != nil {
+			logs.Error(errors.Wrap(err, "invalid app.legacy_sunset"))
+		} else {
+			sunset = parsed.UTC().Format(http.TimeFormat)
+		}
+	}
+	link := fmt.Sprintf(`<%s>; rel="successor-version"`, successor)
+
+	return func(ctx *fiber.Ctx) error {
+		ctx.Set("Deprecation", "true")
+		if sunset != "" {
+			ctx.Set("Sunset", sunset)
+		}
+		ctx.Append(fiber.HeaderLink, link)
+		return ctx.Next()
+	}
+}
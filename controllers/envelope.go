@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"go_starter/config"
+	"go_starter/responses"
+	"sort"
+	"strings"
+)
+
+const (
+	problemJSON = "application/problem+json"
+
+	// responseFormatHeader lets a client pick the body shape over app.response_format
+	responseFormatHeader = "X-Response-Format"
+	legacyFormat         = "legacy"
+	envelopeFormat       = "envelope"
+
+	// retrieveFailedMessage is the legacy message of the handlers that answered a failed
+	// read with success, message and error
+	retrieveFailedMessage = "Failed to retrieve customer data"
+
+	requestIDKey = "requestid"
+)
+
+// RequestID tags every request with the X-Request-ID its client sent, or a new one, and
+// answers it in the header and the request_id of the envelope
+func RequestID() fiber.Handler {
+	return requestid.New(requestid.Config{ContextKey: requestIDKey})
+}
+
+func requestID(ctx *fiber.Ctx) string {
+	id, _ := ctx.Locals(requestIDKey).(string)
+	return id
+}
+
+// legacyResponses reports whether the request is answered in the shapes the handlers
+// had before the envelope, for mobile clients not migrated yet
+func legacyResponses(ctx *fiber.Ctx) bool {
+	switch ctx.Get(responseFormatHeader) {
+	case legacyFormat:
+		return true
+	case envelopeFormat:
+		return false
+	}
+	return config.GetEnv("app.response_format", envelopeFormat) == legacyFormat
+}
+
+// respond answers envelope, or legacy in the legacy format
+func respond(ctx *fiber.Ctx, envelope responses.Envelope, legacy fiber.Map) error {
+	if legacy != nil && legacyResponses(ctx) {
+		return ctx.JSON(legacy)
+	}
+	envelope.RequestID = requestID(ctx)
+	return ctx.JSON(envelope)
+}
+
+// messageData is the data of a response that is only a message
+func messageData(message interface{}) interface{} {
+	return fiber.Map{"message": message}
+}
+
+// legacyErrorMessage is the single error string of the legacy shape, the first
+// field message of a validation problem as before the envelope
+func legacyErrorMessage(problem *responses.Problem) string {
+	if len(problem.Errors) == 0 {
+		return problem.Detail
+	}
+	fields := make([]string, 0, len(problem.Errors))
+	for field := range problem.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return strings.TrimSpace(fields[0] + " " + strings.Join(problem.Errors[fields[0]], ", "))
+}
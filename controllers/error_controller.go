@@ -0,0 +1,26 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go_starter/errs"
+)
+
+type ErrorController interface {
+	// GetErrorCatalogController lists every error code with its status and translated
+	// title, the type of a problem response links to its entry here
+	GetErrorCatalogController(ctx *fiber.Ctx) error
+}
+
+type errorController struct{}
+
+func (e *errorController) GetErrorCatalogController(ctx *fiber.Ctx) error {
+	catalog := errs.Catalog()
+	for i := range catalog {
+		catalog[i].Title = translate(ctx, string(catalog[i].Code))
+	}
+	return NewSuccessResponse(ctx, catalog)
+}
+
+func NewErrorController() ErrorController {
+	return &errorController{}
+}
@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go_starter/errs"
+	"go_starter/logs"
+	"go_starter/requests"
+	"go_starter/services"
+	"strings"
+)
+
+type FileController interface {
+	GetPhotoController(ctx *fiber.Ctx) error
+}
+
+type fileController struct {
+	serviceFile services.FileService
+}
+
+// GetPhotoController serves student photos and avatars, the request path is the storage key
+func (f *fileController) GetPhotoController(ctx *fiber.Ctx) error {
+	// ?w=<width> resizes on the fly, the result is cached in storage
+	request := new(requests.ImageRequest)
+	err := ctx.QueryParser(request)
+	if err != nil {
+		logs.Error(err)
+		return NewErrorResponses(ctx, errs.Wrap(errs.CodeBadRequest, err))
+	}
+	request.Key = strings.TrimPrefix(ctx.Path(), "/")
+	request.AccessToken = strings.TrimPrefix(ctx.Get(fiber.HeaderAuthorization), "Bearer ")
+
+	response, err := f.serviceFile.GetImageService(ctx.UserContext(), *request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewFileResponse(ctx, response)
+}
+
+func NewFileController(serviceFile services.FileService) FileController {
+	return &fileController{serviceFile: serviceFile}
+}
@@ -0,0 +1,178 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go_starter/logs"
+	"go_starter/requests"
+	"go_starter/services"
+	"go_starter/validation"
+	"strings"
+)
+
+type GradeController interface {
+	CreateTermController(ctx *fiber.Ctx) error
+	FinalizeTermController(ctx *fiber.Ctx) error
+
+	CreateGradingScaleController(ctx *fiber.Ctx) error
+	GetGradingScalesController(ctx *fiber.Ctx) error
+
+	CreateAssessmentController(ctx *fiber.Ctx) error
+	GetClassroomAssessmentsController(ctx *fiber.Ctx) error
+	EnterScoresController(ctx *fiber.Ctx) error
+
+	GetClassroomGradesController(ctx *fiber.Ctx) error
+	GetStudentGradesController(ctx *fiber.Ctx) error
+}
+
+type gradeController struct {
+	serviceGrade services.GradeService
+}
+
+func (g *gradeController) CreateTermController(ctx *fiber.Ctx) error {
+	request := new(requests.TermRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		logs.Error(err)
+		return NewErrorResponses(ctx, err)
+	}
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := g.serviceGrade.CreateTermService(ctx.UserContext(), *request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewSuccessResponse(ctx, response)
+}
+
+func (g *gradeController) FinalizeTermController(ctx *fiber.Ctx) error {
+	request := new(requests.TermIDRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		logs.Error(err)
+		return NewErrorResponses(ctx, err)
+	}
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := g.serviceGrade.FinalizeTermService(ctx.UserContext(), *request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewSuccessResponse(ctx, response)
+}
+
+func (g *gradeController) CreateGradingScaleController(ctx *fiber.Ctx) error {
+	request := new(requests.GradingScaleRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		logs.Error(err)
+		return NewErrorResponses(ctx, err)
+	}
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := g.serviceGrade.CreateGradingScaleService(ctx.UserContext(), *request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewSuccessResponse(ctx, response)
+}
+
+func (g *gradeController) GetGradingScalesController(ctx *fiber.Ctx) error {
+	response, err := g.serviceGrade.GetGradingScalesService(ctx.UserContext())
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewSuccessResponse(ctx, response)
+}
+
+func (g *gradeController) CreateAssessmentController(ctx *fiber.Ctx) error {
+	request := new(requests.AssessmentRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		logs.Error(err)
+		return NewErrorResponses(ctx, err)
+	}
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := g.serviceGrade.CreateAssessmentService(ctx.UserContext(), *request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewSuccessResponse(ctx, response)
+}
+
+func (g *gradeController) GetClassroomAssessmentsController(ctx *fiber.Ctx) error {
+	request := new(requests.ClassroomTermRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		logs.Error(err)
+		return NewErrorResponses(ctx, err)
+	}
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := g.serviceGrade.GetClassroomAssessmentsService(ctx.UserContext(), *request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewSuccessResponse(ctx, response)
+}
+
+func (g *gradeController) EnterScoresController(ctx *fiber.Ctx) error {
+	request := new(requests.BulkScoreRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		logs.Error(err)
+		return NewErrorResponses(ctx, err)
+	}
+	request.AccessToken = strings.TrimPrefix(ctx.Get(fiber.HeaderAuthorization), "Bearer ")
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := g.serviceGrade.EnterScoresService(ctx.UserContext(), *request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewSuccessResponse(ctx, response)
+}
+
+func (g *gradeController) GetClassroomGradesController(ctx *fiber.Ctx) error {
+	request := new(requests.ClassroomTermRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		logs.Error(err)
+		return NewErrorResponses(ctx, err)
+	}
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := g.serviceGrade.GetClassroomGradesService(ctx.UserContext(), *request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewSuccessResponse(ctx, response)
+}
+
+func (g *gradeController) GetStudentGradesController(ctx *fiber.Ctx) error {
+	request := new(requests.StudentGradeRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		logs.Error(err)
+		return NewErrorResponses(ctx, err)
+	}
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := g.serviceGrade.GetStudentGradesService(ctx.UserContext(), *request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewSuccessResponse(ctx, response)
+}
+
+func NewGradeController(serviceGrade services.GradeService) GradeController {
+	return &gradeController{serviceGrade: serviceGrade}
+}
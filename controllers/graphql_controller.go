@@ -0,0 +1,37 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go_starter/graph"
+	"go_starter/logs"
+	"go_starter/requests"
+	"go_starter/validation"
+)
+
+type GraphQLController interface {
+	// ExecuteGraphQLController answers the GraphQL query of the body, its errors are in
+	// the errors of the GraphQL response rather than an envelope
+	ExecuteGraphQLController(ctx *fiber.Ctx) error
+}
+
+type graphQLController struct {
+	schema *graph.Schema
+}
+
+func (g *graphQLController) ExecuteGraphQLController(ctx *fiber.Ctx) error {
+	request := new(requests.GraphQLRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		logs.Error(err)
+		return NewErrorResponses(ctx, err)
+	}
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response := g.schema.Exec(ctx.UserContext(), language(ctx), requestID(ctx), *request)
+	return ctx.JSON(response)
+}
+
+func NewGraphQLController(schema *graph.Schema) GraphQLController {
+	return &graphQLController{schema: schema}
+}
@@ -1,45 +1,149 @@
 package controllers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+	"go_starter/config"
 	"go_starter/errs"
+	"go_starter/i18n"
+	"go_starter/logs"
+	"go_starter/responses"
+	"go_starter/validation"
 	"net/http"
+	"strings"
 )
 
-var (
-	code    int
-	message string
-)
+// NewErrorResponses answers err as the problem of an envelope, or as bare
+// application/problem+json to clients asking for it. Errors outside the errs catalog
+// are logged and answered as internal errors without their message.
+func NewErrorResponses(ctx *fiber.Ctx, err error) error {
+	return errorResponses(ctx, err, func(problem *responses.Problem) fiber.Map {
+		return fiber.Map{
+			"status": false,
+			"error":  legacyErrorMessage(problem),
+		}
+	})
+}
 
-type ErrorResponse struct {
-	Status bool   `json:"status"`
-	Error  string `json:"error"`
+// NewErrorMessageResponses answers err like NewErrorResponses, in the legacy format
+// with the success, message and error of the handlers that answered so before the
+// envelope
+func NewErrorMessageResponses(ctx *fiber.Ctx, err error, message string) error {
+	return errorResponses(ctx, err, func(problem *responses.Problem) fiber.Map {
+		return fiber.Map{
+			"success": false,
+			"message": message,
+			"error":   legacyErrorMessage(problem),
+		}
+	})
 }
 
-func NewErrorResponses(ctx *fiber.Ctx, err error) error {
-	switch e := err.(type) {
-	case errs.AppError:
-		code = e.Status
-		message = e.Message
-	case error:
-		code = http.StatusUnprocessableEntity
-		message = err.Error()
+// errorResponses answers err, legacy builds the body of the legacy format from the problem
+func errorResponses(ctx *fiber.Ctx, err error, legacy func(problem *responses.Problem) fiber.Map) error {
+	// A request past its deadline fails wherever its work stopped, often in an error
+	// that no longer carries the cause
+	if requestErr := ctx.UserContext().Err(); requestErr != nil {
+		err = requestErr
+	}
+	// A body the parser could not read is the client's error, not ours
+	var fiberError *fiber.Error
+	var syntaxError *json.SyntaxError
+	var typeError *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &fiberError):
+		err = errs.NewError(fiberError.Code, fiberError.Message)
+	case errors.As(err, &syntaxError), errors.As(err, &typeError):
+		err = errs.ErrorBadRequest(err.Error())
 	}
-	errorResponse := ErrorResponse{
-		Status: false,
-		Error:  message,
+	appError := errs.From(err)
+	if appError.Status >= http.StatusInternalServerError {
+		logs.Error(err, zap.String("request_id", requestID(ctx)))
 	}
-	return ctx.Status(code).JSON(errorResponse)
+	// The catalog title is translated with its params, a message of its own is detail
+	// only English has
+	title := i18n.Translate(language(ctx), string(appError.Code), appError.Params)
+	detail := appError.Message
+	if appError.Titled() {
+		detail = title
+	}
+	var fields map[string][]string
+	if len(appError.Fields) > 0 {
+		fields = make(map[string][]string, len(appError.Fields))
+		for field, messages := range appError.Fields {
+			for _, message := range messages {
+				fields[field] = append(fields[field], translate(ctx, message))
+			}
+		}
+	}
+	problem := &responses.Problem{
+		Type:     errorTypeURI(appError.Code),
+		Title:    title,
+		Status:   appError.Status,
+		Detail:   detail,
+		Instance: ctx.OriginalURL(),
+		Code:     appError.Code,
+		Errors:   fields,
+	}
+	ctx.Status(appError.Status)
+	if legacyResponses(ctx) {
+		return ctx.JSON(legacy(problem))
+	}
+	if ctx.Accepts(fiber.MIMEApplicationJSON, problemJSON) == problemJSON {
+		return ctx.JSON(problem, problemJSON)
+	}
+	return respond(ctx, responses.Envelope{Error: problem}, nil)
+}
+
+// ErrorHandler answers the errors fiber raises itself, an unknown route or a body it
+// cannot read, like the errors of the handlers
+func ErrorHandler(ctx *fiber.Ctx, err error) error {
+	return NewErrorResponses(ctx, err)
 }
+
+// errorTypeURI points at the entry of code in the catalog served on /errors
+func errorTypeURI(code errs.Code) string {
+	baseURL := config.GetEnv("app.public_url", "http://localhost:"+config.Env("app.port"))
+	return strings.TrimSuffix(baseURL, "/") + "/errors#" + string(code)
+}
+
 func NewSuccessResponse(ctx *fiber.Ctx, data interface{}) error {
-	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+	return respond(ctx, responses.Envelope{Status: true, Data: data}, fiber.Map{
 		"status": true,
 		"data":   data,
 	})
 }
 
+// Created answers the successes of the handlers after it with 201 Created, for the
+// routes that create a resource. Errors keep their status.
+func Created() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if err := ctx.Next(); err != nil {
+			return err
+		}
+		if ctx.Response().StatusCode() == http.StatusOK {
+			ctx.Status(http.StatusCreated)
+		}
+		return nil
+	}
+}
+
+// NewListResponse answers a list with its count in the meta
+func NewListResponse(ctx *fiber.Ctx, data interface{}, count int) error {
+	return respond(ctx, responses.Envelope{Status: true, Data: data, Meta: responses.Meta{"count": count}}, fiber.Map{
+		"success": true,
+		"data":    data,
+	})
+}
+
+// NewSuccessMsg answers msg, translated when it is a key of the i18n catalog
 func NewSuccessMsg(ctx *fiber.Ctx, msg interface{}) error {
-	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+	if key, ok := msg.(string); ok {
+		msg = translate(ctx, key)
+	}
+	return respond(ctx, responses.Envelope{Status: true, Data: messageData(msg)}, fiber.Map{
 		"status": true,
 		"msg":    msg,
 	})
@@ -53,7 +157,7 @@ func NewSuccessMsg(ctx *fiber.Ctx, msg interface{}) error {
 // }
 
 func NewSuccessResponseSignIn(ctx *fiber.Ctx, data interface{}, token string) error {
-	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+	return respond(ctx, responses.Envelope{Status: true, Data: data, Meta: responses.Meta{"access_token": token}}, fiber.Map{
 		"status":       true,
 		"data":         data,
 		"access_token": token,
@@ -61,15 +165,28 @@ func NewSuccessResponseSignIn(ctx *fiber.Ctx, data interface{}, token string) er
 }
 
 func NewSuccessMessage(ctx *fiber.Ctx, data interface{}) error {
-	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+	if key, ok := data.(string); ok {
+		data = translate(ctx, key)
+	}
+	return respond(ctx, responses.Envelope{Status: true, Data: messageData(data)}, fiber.Map{
 		"status":  true,
 		"message": data,
 	})
 }
-func NewErrorValidate(ctx *fiber.Ctx, data interface{}) error {
-	validateError := fiber.Map{
-		"error":  data,
-		"status": false,
+
+func NewFileResponse(ctx *fiber.Ctx, file *responses.FileResponse) error {
+	ctx.Set(fiber.HeaderContentType, file.ContentType)
+	ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`inline; filename="%s"`, file.FileName))
+	if file.CacheControl != "" {
+		ctx.Set(fiber.HeaderCacheControl, file.CacheControl)
 	}
-	return ctx.Status(http.StatusUnprocessableEntity).JSON(validateError)
+	return ctx.Status(http.StatusOK).Send(file.Content)
+}
+
+// NewErrorValidate answers the failed rules of a request as a problem with the messages
+// of each field in the language of the request
+func NewErrorValidate(ctx *fiber.Ctx, errValidate validation.Errors) error {
+	appError := errs.New(errs.CodeValidationFailed)
+	appError.Fields = errValidate.Messages(language(ctx))
+	return NewErrorResponses(ctx, appError)
 }
@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go_starter/errs"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreatedAnswersSuccessesOnly(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Post("/students", Created(), func(ctx *fiber.Ctx) error {
+		return NewSuccessMsg(ctx, "created")
+	})
+	app.Post("/conflicts", Created(), func(ctx *fiber.Ctx) error {
+		return NewErrorResponses(ctx, errs.New(errs.CodeAlreadyExists))
+	})
+
+	for path, want := range map[string]int{"/students": http.StatusCreated, "/conflicts": http.StatusConflict} {
+		response, err := app.Test(httptest.NewRequest(http.MethodPost, path, nil))
+		if err != nil {
+			t.Fatalf("POST %s: %v", path, err)
+		}
+		if response.StatusCode != want {
+			t.Errorf("POST %s = %d, want %d", path, response.StatusCode, want)
+		}
+	}
+}
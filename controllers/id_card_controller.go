@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go_starter/logs"
+	"go_starter/requests"
+	"go_starter/services"
+	"go_starter/validation"
+)
+
+type IDCardController interface {
+	GenerateIDCardController(ctx *fiber.Ctx) error
+	GenerateClassroomIDCardsController(ctx *fiber.Ctx) error
+}
+
+type idCardController struct {
+	serviceIDCard services.IDCardService
+}
+
+func (i *idCardController) GenerateIDCardController(ctx *fiber.Ctx) error {
+	request := new(requests.IDCardRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		logs.Error(err)
+		return NewErrorResponses(ctx, err)
+	}
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := i.serviceIDCard.GenerateIDCardService(ctx.UserContext(), *request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewFileResponse(ctx, response)
+}
+
+func (i *idCardController) GenerateClassroomIDCardsController(ctx *fiber.Ctx) error {
+	request := new(requests.ClassroomIDCardRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		logs.Error(err)
+		return NewErrorResponses(ctx, err)
+	}
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := i.serviceIDCard.GenerateClassroomIDCardsService(ctx.UserContext(), *request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewFileResponse(ctx, response)
+}
+
+func NewIDCardController(serviceIDCard services.IDCardService) IDCardController {
+	return &idCardController{serviceIDCard: serviceIDCard}
+}
@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go_starter/i18n"
+	"go_starter/logs"
+	"go_starter/requests"
+	"go_starter/validation"
+	"time"
+)
+
+type LanguageController interface {
+	// SetLanguageController saves the language a client prefers over its
+	// Accept-Language, answered in that language
+	SetLanguageController(ctx *fiber.Ctx) error
+}
+
+type languageController struct{}
+
+func (l *languageController) SetLanguageController(ctx *fiber.Ctx) error {
+	request := new(requests.LanguageRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		logs.Error(err)
+		return NewErrorResponses(ctx, err)
+	}
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	ctx.Cookie(&fiber.Cookie{
+		Name:     languageCookie,
+		Value:    request.Language,
+		Path:     "/",
+		Expires:  time.Now().AddDate(1, 0, 0),
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+	ctx.Locals(localeKey, request.Language)
+	ctx.Set(fiber.HeaderContentLanguage, request.Language)
+	return NewSuccessMsg(ctx, i18n.MessageSuccess)
+}
+
+func NewLanguageController() LanguageController {
+	return &languageController{}
+}
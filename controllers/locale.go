@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go_starter/i18n"
+)
+
+const (
+	localeKey = "locale"
+	// languageCookie keeps the language a client chose with PUT /language
+	languageCookie = "lang"
+)
+
+// Locale picks the language of the messages answered to a request. A lang query
+// parameter wins over the language the client saved, which wins over its
+// Accept-Language.
+func Locale() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		language := negotiateLanguage(ctx)
+		ctx.Locals(localeKey, language)
+		ctx.Set(fiber.HeaderContentLanguage, language)
+		ctx.Vary(fiber.HeaderAcceptLanguage)
+		return ctx.Next()
+	}
+}
+
+func negotiateLanguage(ctx *fiber.Ctx) string {
+	for _, preferred := range []string{ctx.Query("lang"), ctx.Cookies(languageCookie)} {
+		if i18n.Supported(preferred) {
+			return preferred
+		}
+	}
+	if language := ctx.AcceptsLanguages(i18n.Languages...); language != "" {
+		return language
+	}
+	return i18n.DefaultLanguage
+}
+
+// language is the language Locale picked, negotiated here for routes outside it
+func language(ctx *fiber.Ctx) string {
+	if language, ok := ctx.Locals(localeKey).(string); ok {
+		return language
+	}
+	return negotiateLanguage(ctx)
+}
+
+// translate returns the message of key in the language of the request
+func translate(ctx *fiber.Ctx, key string) string {
+	return i18n.Translate(language(ctx), key, nil)
+}
@@ -0,0 +1,27 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go_starter/services"
+)
+
+type MetricsController interface {
+	GetMetricsController(ctx *fiber.Ctx) error
+}
+
+type metricsController struct {
+	serviceMetrics services.MetricsService
+}
+
+func (m *metricsController) GetMetricsController(ctx *fiber.Ctx) error {
+	metrics, err := m.serviceMetrics.GetMetricsService()
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	ctx.Set(fiber.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+	return ctx.SendString(metrics)
+}
+
+func NewMetricsController(serviceMetrics services.MetricsService) MetricsController {
+	return &metricsController{serviceMetrics: serviceMetrics}
+}
@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go_starter/models"
+	"go_starter/requests"
+	"go_starter/services"
+	"go_starter/trails"
+	"go_starter/validation"
+)
+
+type PhotoController interface {
+	UploadTeacherPhotoController(ctx *fiber.Ctx) error
+	UploadUserPhotoController(ctx *fiber.Ctx) error
+}
+
+type photoController struct {
+	servicePhoto services.PhotoService
+}
+
+func (p *photoController) UploadTeacherPhotoController(ctx *fiber.Ctx) error {
+	return p.uploadPhoto(ctx, models.FileOwnerTeacherPhoto, ctx.Params("id", ctx.FormValue("teacher_id")))
+}
+
+func (p *photoController) UploadUserPhotoController(ctx *fiber.Ctx) error {
+	return p.uploadPhoto(ctx, models.FileOwnerUserPhoto, ctx.Params("id", ctx.FormValue("user_id")))
+}
+
+func (p *photoController) uploadPhoto(ctx *fiber.Ctx, ownerType, ownerID string) error {
+	// Multipart form data handle
+	imageData, err := trails.HandleMultipartFormData(ctx)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	request := requests.PhotoRequest{
+		OwnerType: ownerType,
+		OwnerID:   ownerID,
+		Image:     imageData,
+	}
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := p.servicePhoto.UploadPhotoService(ctx.UserContext(), request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewSuccessMsg(ctx, response.Message)
+}
+
+func NewPhotoController(servicePhoto services.PhotoService) PhotoController {
+	return &photoController{servicePhoto: servicePhoto}
+}
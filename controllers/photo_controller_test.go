@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"go_starter/errs"
+	"go_starter/responses"
+	"go_starter/trails"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUploadPhotoControllerRejectsBadUploads(t *testing.T) {
+	// The upload is rejected before the service is called
+	controller := NewPhotoController(nil)
+	app := fiber.New(fiber.Config{BodyLimit: 2 * trails.MaximumFileSize, ErrorHandler: ErrorHandler})
+	app.Post("/teachers/:id/photo", controller.UploadTeacherPhotoController)
+
+	tests := []struct {
+		name        string
+		body        []byte
+		contentType string
+		status      int
+		code        errs.Code
+		field       string
+	}{
+		{
+			name:        "no image",
+			body:        multipartBody(t, "document", []byte("photo")),
+			contentType: "multipart/form-data; boundary=" + testBoundary,
+			status:      http.StatusUnprocessableEntity,
+			code:        errs.CodeValidationFailed,
+			field:       "image",
+		},
+		{
+			name:        "image too large",
+			body:        multipartBody(t, "image", make([]byte, trails.MaximumFileSize+1)),
+			contentType: "multipart/form-data; boundary=" + testBoundary,
+			status:      http.StatusRequestEntityTooLarge,
+			code:        errs.CodePayloadTooLarge,
+		},
+		{
+			name:        "not multipart",
+			body:        []byte(`{"image":"photo"}`),
+			contentType: fiber.MIMEApplicationJSON,
+			status:      http.StatusBadRequest,
+			code:        errs.CodeBadRequest,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/teachers/1/photo", bytes.NewReader(test.body))
+			request.Header.Set(fiber.HeaderContentType, test.contentType)
+			request.Header.Set(fiber.HeaderAccept, problemJSON)
+			response, err := app.Test(request, -1)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			defer response.Body.Close()
+
+			var problem responses.Problem
+			if err := json.NewDecoder(response.Body).Decode(&problem); err != nil {
+				t.Fatalf("decode problem: %v", err)
+			}
+			if response.StatusCode != test.status || problem.Code != test.code {
+				t.Errorf("answer = %d %s, want %d %s", response.StatusCode, problem.Code, test.status, test.code)
+			}
+			if test.field != "" && len(problem.Errors[test.field]) == 0 {
+				t.Errorf("errors = %v, want a message for %s", problem.Errors, test.field)
+			}
+		})
+	}
+}
+
+const testBoundary = "photo-boundary"
+
+// multipartBody is a multipart form with a single file field
+func multipartBody(t *testing.T, field string, content []byte) []byte {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.SetBoundary(testBoundary); err != nil {
+		t.Fatalf("set boundary: %v", err)
+	}
+	part, err := writer.CreateFormFile(field, "photo.jpg")
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	if _, err = part.Write(content); err != nil {
+		t.Fatalf("write form file: %v", err)
+	}
+	if err = writer.Close(); err != nil {
+		t.Fatalf("close form: %v", err)
+	}
+	return body.Bytes()
+}
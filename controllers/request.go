@@ -0,0 +1,18 @@
+package controllers
+
+import "github.com/gofiber/fiber/v2"
+
+// parseRequest fills request from the body, then the query string, then the path, so
+// a handler serves both the legacy routes naming the record in the body and the
+// /api routes naming it in the path. The path wins over a body naming another record.
+func parseRequest(ctx *fiber.Ctx, request interface{}) error {
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(request); err != nil {
+			return err
+		}
+	}
+	if err := ctx.QueryParser(request); err != nil {
+		return err
+	}
+	return ctx.ParamsParser(request)
+}
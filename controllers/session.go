@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gofiber/fiber/v2"
+	"go_starter/database"
+	"go_starter/security"
+	"strings"
+)
+
+// DatabaseSession keeps the read-your-writes window of the database per client: the
+// subject of its access token, a hash of a token that does not parse, or its address
+// when the app reads it from a trusted proxy. Other requests share one window.
+func DatabaseSession() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if key := sessionKey(ctx); key != "" {
+			ctx.SetUserContext(database.WithSession(ctx.UserContext(), key))
+		}
+		return ctx.Next()
+	}
+}
+
+func sessionKey(ctx *fiber.Ctx) string {
+	if token := strings.TrimPrefix(ctx.Get(fiber.HeaderAuthorization), "Bearer "); token != "" {
+		if claims, err := security.ParseAccessToken(token); err == nil && claims.Id != "" {
+			return "subject:" + claims.Id
+		}
+		// The token is never kept, a window outlives the request
+		sum := sha256.Sum256([]byte(token))
+		return "token:" + hex.EncodeToString(sum[:])
+	}
+	// Behind a proxy every client would share the address of the proxy, the address is
+	// only used when the app reads the client's from the header of a trusted proxy
+	if appConfig := ctx.App().Config(); appConfig.ProxyHeader != "" && appConfig.EnableTrustedProxyCheck {
+		return "ip:" + ctx.IP()
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gofiber/fiber/v2"
+	"go_starter/security"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSessionKey(t *testing.T) {
+	token, err := security.NewAccessToken("2055559999")
+	if err != nil {
+		t.Fatalf("access token: %v", err)
+	}
+	forged := sha256.Sum256([]byte("forged"))
+
+	direct := fiber.New()
+	proxied := fiber.New(fiber.Config{
+		ProxyHeader:             fiber.HeaderXForwardedFor,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          []string{"0.0.0.0"},
+	})
+	tests := []struct {
+		name          string
+		app           *fiber.App
+		authorization string
+		want          string
+	}{
+		{name: "access token", app: direct, authorization: "Bearer " + token, want: "subject:2055559999"},
+		{name: "same subject behind a proxy", app: proxied, authorization: "Bearer " + token, want: "subject:2055559999"},
+		{name: "token that does not parse", app: direct, authorization: "Bearer forged", want: "token:" + hex.EncodeToString(forged[:])},
+		// Without a trusted proxy the address may be the proxy's, shared by every client
+		{name: "anonymous", app: direct, want: ""},
+		{name: "anonymous behind a trusted proxy", app: proxied, want: "ip:203.0.113.7"},
+	}
+	for _, app := range []*fiber.App{direct, proxied} {
+		app.Get("/", func(ctx *fiber.Ctx) error { return ctx.SendString(sessionKey(ctx)) })
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.Header.Set(fiber.HeaderXForwardedFor, "203.0.113.7")
+			if test.authorization != "" {
+				request.Header.Set(fiber.HeaderAuthorization, test.authorization)
+			}
+			response, err := test.app.Test(request)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			key, _ := io.ReadAll(response.Body)
+			if string(key) != test.want {
+				t.Errorf("session key = %q, want %q", key, test.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"go_starter/errs"
 	"go_starter/logs"
 	"go_starter/requests"
 	"go_starter/services"
@@ -18,6 +19,7 @@ type StudentController interface {
 	GetStudentController(ctx *fiber.Ctx) error
 	GetStudentByIDController(ctx *fiber.Ctx) error
 	GetStudentByStudentIDControllerV2(ctx *fiber.Ctx) error
+	GetTeacherByIDController(ctx *fiber.Ctx) error
 	CreateStudentController(ctx *fiber.Ctx) error
 	UpdateStudentController(ctx *fiber.Ctx) error
 	DeleteStudentByIDController(ctx *fiber.Ctx) error
@@ -31,15 +33,15 @@ type studentController struct {
 
 func (c *studentController) GetStudentClassroomByClassroomIDController(ctx *fiber.Ctx) error {
 	req := new(requests.ClassroomIDRequest)
-	if err := ctx.BodyParser(req); err != nil {
+	if err := parseRequest(ctx, req); err != nil {
 		logs.Error(err)
 		return NewErrorResponses(ctx, err)
 	}
 	errValidate := validation.Validate(req)
 	if errValidate != nil {
-		return NewErrorValidate(ctx, errValidate[0].Error)
+		return NewErrorValidate(ctx, errValidate)
 	}
-	response, err := c.serviceStudent.GetStudentClassroomByClassroomIDService(*req)
+	response, err := c.serviceStudent.GetStudentClassroomByClassroomIDService(ctx.UserContext(), *req)
 	if err != nil {
 		return NewErrorResponses(ctx, err)
 	}
@@ -54,9 +56,9 @@ func (c *studentController) SignInController(ctx *fiber.Ctx) error {
 	}
 	errValidate := validation.Validate(req)
 	if errValidate != nil {
-		return NewErrorValidate(ctx, errValidate[0].Error)
+		return NewErrorValidate(ctx, errValidate)
 	}
-	response, err := c.serviceStudent.SignInService(*req)
+	response, err := c.serviceStudent.SignInService(ctx.UserContext(), *req)
 	if err != nil {
 		return NewErrorResponses(ctx, err)
 	}
@@ -71,9 +73,9 @@ func (c *studentController) SignUpController(ctx *fiber.Ctx) error {
 	}
 	errValidate := validation.Validate(req)
 	if errValidate != nil {
-		return NewErrorValidate(ctx, errValidate[0].Error)
+		return NewErrorValidate(ctx, errValidate)
 	}
-	response, err := c.serviceStudent.SignUpService(*req)
+	response, err := c.serviceStudent.SignUpService(ctx.UserContext(), *req)
 	if err != nil {
 		return NewErrorResponses(ctx, err)
 	}
@@ -84,21 +86,21 @@ func (c *studentController) UploadStudentImageController(ctx *fiber.Ctx) error {
 	// Multipart form data handle
 	imageData, err := trails.HandleMultipartFormData(ctx)
 	if err != nil {
-		return err
+		return NewErrorResponses(ctx, err)
 	}
 
 	// Create a student image request instance
 	request := requests.StudentImageRequest{
-		StudentID: ctx.FormValue("student_id"),
+		StudentID: ctx.Params("studentId", ctx.FormValue("student_id")),
 		Image:     imageData,
 	}
 	//fmt.Printf("%v\n", request)
 	errValidate := validation.Validate(request)
 	if errValidate != nil {
-		return NewErrorValidate(ctx, errValidate[0].Error)
+		return NewErrorValidate(ctx, errValidate)
 	}
 	// Call the service
-	response, err := c.serviceStudent.UploadStudentImageService(request)
+	response, err := c.serviceStudent.UploadStudentImageService(ctx.UserContext(), request)
 	if err != nil {
 		return NewErrorResponses(ctx, err)
 	}
@@ -112,9 +114,9 @@ func (c *studentController) CreateStudentController(ctx *fiber.Ctx) error {
 	}
 	errValidate := validation.Validate(request)
 	if errValidate != nil {
-		return NewErrorValidate(ctx, errValidate[0].Error)
+		return NewErrorValidate(ctx, errValidate)
 	}
-	response, err := c.serviceStudent.CreateStudentService(*request)
+	response, err := c.serviceStudent.CreateStudentService(ctx.UserContext(), *request)
 	if err != nil {
 		return NewErrorResponses(ctx, err)
 	}
@@ -123,14 +125,14 @@ func (c *studentController) CreateStudentController(ctx *fiber.Ctx) error {
 
 func (c *studentController) UpdateStudentController(ctx *fiber.Ctx) error {
 	request := new(requests.StudentRequest)
-	if err := ctx.BodyParser(request); err != nil {
+	if err := parseRequest(ctx, request); err != nil {
 		return NewErrorResponses(ctx, err)
 	}
 	errValidate := validation.Validate(request)
 	if errValidate != nil {
-		return NewErrorValidate(ctx, errValidate[0].Error)
+		return NewErrorValidate(ctx, errValidate)
 	}
-	response, err := c.serviceStudent.UpdateStudentService(*request)
+	response, err := c.serviceStudent.UpdateStudentService(ctx.UserContext(), *request)
 	if err != nil {
 		return NewErrorResponses(ctx, err)
 	}
@@ -139,14 +141,14 @@ func (c *studentController) UpdateStudentController(ctx *fiber.Ctx) error {
 
 func (c *studentController) DeleteStudentByIDController(ctx *fiber.Ctx) error {
 	request := new(requests.StudentIdRequest)
-	if err := ctx.BodyParser(request); err != nil {
+	if err := parseRequest(ctx, request); err != nil {
 		return NewErrorResponses(ctx, err)
 	}
 	errValidate := validation.Validate(request)
 	if errValidate != nil {
-		return NewErrorValidate(ctx, errValidate[0].Error)
+		return NewErrorValidate(ctx, errValidate)
 	}
-	response, err := c.serviceStudent.DeleteStudentByIDService(*request)
+	response, err := c.serviceStudent.DeleteStudentByIDService(ctx.UserContext(), *request)
 	if err != nil {
 		return NewErrorResponses(ctx, err)
 	}
@@ -155,15 +157,27 @@ func (c *studentController) DeleteStudentByIDController(ctx *fiber.Ctx) error {
 
 func (c *studentController) GetStudentByStudentIDControllerV2(ctx *fiber.Ctx) error {
 	req := new(requests.StudentIdRequest)
-	if err := ctx.BodyParser(req); err != nil {
+	if err := parseRequest(ctx, req); err != nil {
 		logs.Error(err)
 		return NewErrorResponses(ctx, err)
 	}
 	errValidate := validation.Validate(req)
 	if errValidate != nil {
-		return NewErrorValidate(ctx, errValidate[0].Error)
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := c.serviceStudent.GetStudentByStudentIdServiceV2(ctx.UserContext(), *req)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewSuccessResponse(ctx, response)
+}
+
+func (c *studentController) GetTeacherByIDController(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return NewErrorResponses(ctx, errs.New(errs.CodeBadRequest).WithField("id", "validation.invalid"))
 	}
-	response, err := c.serviceStudent.GetStudentByStudentIdServiceV2(*req)
+	response, err := c.serviceStudent.GetTeacherByIDService(ctx.UserContext(), uint(id))
 	if err != nil {
 		return NewErrorResponses(ctx, err)
 	}
@@ -173,13 +187,9 @@ func (c *studentController) GetStudentByStudentIDControllerV2(ctx *fiber.Ctx) er
 func (c *studentController) GetStudentByIDController(ctx *fiber.Ctx) error {
 	id, err := ctx.ParamsInt("id", 1)
 	if err != nil {
-		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"message": "Failed to retrieve customer data",
-			"error":   err.Error(),
-		})
+		return NewErrorMessageResponses(ctx, errs.New(errs.CodeBadRequest).WithField("id", "validation.invalid"), retrieveFailedMessage)
 	}
-	response, err := c.serviceStudent.GetStudentByIdService(uint(id))
+	response, err := c.serviceStudent.GetStudentByIdService(ctx.UserContext(), uint(id))
 	if err != nil {
 		return NewErrorResponses(ctx, err)
 	}
@@ -189,20 +199,13 @@ func (c *studentController) GetStudentByIDController(ctx *fiber.Ctx) error {
 func (c *studentController) GetStudentController(ctx *fiber.Ctx) error {
 
 	//fetch customer data from service folder
-	customers, err := c.serviceStudent.GetStudentService()
+	customers, err := c.serviceStudent.GetStudentService(ctx.UserContext())
 	if err != nil {
-		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"message": "Failed to retrieve customer data",
-			"error":   err.Error(),
-		})
+		return NewErrorMessageResponses(ctx, err, retrieveFailedMessage)
 	}
 
 	//return http response
-	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
-		"success": true,
-		"data":    customers,
-	})
+	return NewListResponse(ctx, customers, len(customers))
 }
 
 func NewCustomerController(serviceService services.StudentService) StudentController {
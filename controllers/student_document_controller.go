@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"go_starter/errs"
+	"go_starter/logs"
+	"go_starter/requests"
+	"go_starter/services"
+	"go_starter/trails"
+	"go_starter/validation"
+	"io/ioutil"
+)
+
+type StudentDocumentController interface {
+	GetStudentDocumentTypesController(ctx *fiber.Ctx) error
+	UploadStudentDocumentController(ctx *fiber.Ctx) error
+	GetStudentDocumentsController(ctx *fiber.Ctx) error
+	DownloadStudentDocumentController(ctx *fiber.Ctx) error
+	DeleteStudentDocumentController(ctx *fiber.Ctx) error
+}
+
+type studentDocumentController struct {
+	serviceStudentDocument services.StudentDocumentService
+}
+
+func (s *studentDocumentController) GetStudentDocumentTypesController(ctx *fiber.Ctx) error {
+	response, err := s.serviceStudentDocument.GetStudentDocumentTypesService(ctx.UserContext())
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewSuccessResponse(ctx, response)
+}
+
+func (s *studentDocumentController) UploadStudentDocumentController(ctx *fiber.Ctx) error {
+	studentID, err := ctx.ParamsInt("id")
+	if err != nil || studentID <= 0 {
+		return NewErrorResponses(ctx, errs.New(errs.CodeBadRequest).WithField("id", "validation.invalid"))
+	}
+	request := new(requests.StudentDocumentRequest)
+	if err = ctx.BodyParser(request); err != nil {
+		logs.Error(err)
+		return NewErrorResponses(ctx, err)
+	}
+
+	request.StudentID = uint(studentID)
+	if request.UploadID == "" {
+		file, err := ctx.FormFile("document")
+		if err != nil {
+			return NewErrorResponses(ctx, errs.New(errs.CodeBadRequest).WithField("document", "validation.required"))
+		}
+		// Per type limits are checked by the service, this only bounds what is read into memory
+		if file.Size > trails.MaximumFileSize {
+			return NewErrorResponses(ctx, errs.New(errs.CodePayloadTooLarge))
+		}
+		uploadedFile, err := file.Open()
+		if err != nil {
+			return NewErrorResponses(ctx, err)
+		}
+		defer uploadedFile.Close()
+		content, err := ioutil.ReadAll(uploadedFile)
+		if err != nil {
+			return NewErrorResponses(ctx, err)
+		}
+		request.FileName = file.Filename
+		request.Content = content
+	}
+
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := s.serviceStudentDocument.UploadStudentDocumentService(ctx.UserContext(), *request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewSuccessResponse(ctx, response)
+}
+
+func (s *studentDocumentController) GetStudentDocumentsController(ctx *fiber.Ctx) error {
+	studentID, err := ctx.ParamsInt("id")
+	if err != nil || studentID <= 0 {
+		return NewErrorResponses(ctx, errs.New(errs.CodeBadRequest).WithField("id", "validation.invalid"))
+	}
+	response, err := s.serviceStudentDocument.GetStudentDocumentsService(ctx.UserContext(), uint(studentID))
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewSuccessResponse(ctx, response)
+}
+
+func (s *studentDocumentController) DownloadStudentDocumentController(ctx *fiber.Ctx) error {
+	request, err := studentDocumentIDRequest(ctx)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	response, err := s.serviceStudentDocument.DownloadStudentDocumentService(ctx.UserContext(), *request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	ctx.Set(fiber.HeaderContentType, response.ContentType)
+	ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename=%q`, response.FileName))
+	ctx.Set(fiber.HeaderCacheControl, response.CacheControl)
+	return ctx.Status(fiber.StatusOK).Send(response.Content)
+}
+
+func (s *studentDocumentController) DeleteStudentDocumentController(ctx *fiber.Ctx) error {
+	request, err := studentDocumentIDRequest(ctx)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	response, err := s.serviceStudentDocument.DeleteStudentDocumentService(ctx.UserContext(), *request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewSuccessMsg(ctx, response.Message)
+}
+
+func studentDocumentIDRequest(ctx *fiber.Ctx) (*requests.StudentDocumentIDRequest, error) {
+	studentID, err := ctx.ParamsInt("id")
+	if err != nil || studentID <= 0 {
+		return nil, errs.New(errs.CodeBadRequest).WithField("id", "validation.invalid")
+	}
+	documentID, err := ctx.ParamsInt("document_id")
+	if err != nil || documentID <= 0 {
+		return nil, errs.New(errs.CodeBadRequest).WithField("document_id", "validation.invalid")
+	}
+	request := &requests.StudentDocumentIDRequest{
+		StudentID:  uint(studentID),
+		DocumentID: uint(documentID),
+	}
+	return request, nil
+}
+
+func NewStudentDocumentController(serviceStudentDocument services.StudentDocumentService) StudentDocumentController {
+	return &studentDocumentController{serviceStudentDocument: serviceStudentDocument}
+}
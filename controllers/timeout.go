@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"context"
+	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
+	"go_starter/config"
+	"go_starter/logs"
+	"strconv"
+	"time"
+)
+
+// RequestTimeout bounds the handlers after it by the seconds configured under key, in
+// place of any deadline set before it, 0 removes the deadline. Work still running at the
+// deadline is cancelled and answered with 504 by NewErrorResponses.
+func RequestTimeout(key string, defaultSeconds int) fiber.Handler {
+	seconds, err := strconv.Atoi(config.GetEnv(key, strconv.Itoa(defaultSeconds)))
+	if err != nil || seconds < 0 {
+		logs.Error(errors.Errorf("invalid %s", key))
+		seconds = defaultSeconds
+	}
+	timeout := time.Duration(seconds) * time.Second
+
+	return func(ctx *fiber.Ctx) error {
+		// fasthttp does not report a client hanging up, the deadline is what stops
+		// the queries of a request nobody waits for anymore
+		previous := ctx.UserContext()
+		userContext := context.WithoutCancel(previous)
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			userContext, cancel = context.WithTimeout(userContext, timeout)
+			defer cancel()
+		}
+		ctx.SetUserContext(userContext)
+		// An error returned past here reaches the app's ErrorHandler after cancel, which
+		// must not take it for a cancelled request
+		defer ctx.SetUserContext(previous)
+		return ctx.Next()
+	}
+}
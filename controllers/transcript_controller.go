@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"go_starter/errs"
+	"go_starter/logs"
+	"go_starter/requests"
+	"go_starter/services"
+	"go_starter/trails"
+	"go_starter/validation"
+	"io/ioutil"
+)
+
+type TranscriptController interface {
+	GenerateTranscriptController(ctx *fiber.Ctx) error
+	VerifyTranscriptBySerialController(ctx *fiber.Ctx) error
+	VerifyTranscriptDocumentController(ctx *fiber.Ctx) error
+}
+
+type transcriptController struct {
+	serviceTranscript services.TranscriptService
+}
+
+func (t *transcriptController) GenerateTranscriptController(ctx *fiber.Ctx) error {
+	request := new(requests.TranscriptRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		logs.Error(err)
+		return NewErrorResponses(ctx, err)
+	}
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := t.serviceTranscript.GenerateTranscriptService(ctx.UserContext(), *request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	ctx.Set(fiber.HeaderContentType, "application/pdf")
+	ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, response.FileName))
+	ctx.Set("X-Transcript-Serial", response.SerialNo)
+	return ctx.Status(fiber.StatusOK).Send(response.Content)
+}
+
+func (t *transcriptController) VerifyTranscriptBySerialController(ctx *fiber.Ctx) error {
+	request := requests.TranscriptSerialRequest{SerialNo: ctx.Params("serial")}
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := t.serviceTranscript.VerifyTranscriptBySerialService(ctx.UserContext(), request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	response.Message = translate(ctx, response.Message)
+	return NewSuccessResponse(ctx, response)
+}
+
+func (t *transcriptController) VerifyTranscriptDocumentController(ctx *fiber.Ctx) error {
+	file, err := ctx.FormFile("transcript")
+	if err != nil {
+		return NewErrorResponses(ctx, errs.New(errs.CodeBadRequest).WithField("transcript", "validation.required"))
+	}
+	if file.Size > trails.MaximumFileSize {
+		return NewErrorResponses(ctx, errs.New(errs.CodePayloadTooLarge))
+	}
+	uploadedFile, err := file.Open()
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	defer uploadedFile.Close()
+	document, err := ioutil.ReadAll(uploadedFile)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+
+	request := requests.TranscriptDocumentRequest{Document: document}
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := t.serviceTranscript.VerifyTranscriptDocumentService(ctx.UserContext(), request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	response.Message = translate(ctx, response.Message)
+	return NewSuccessResponse(ctx, response)
+}
+
+func NewTranscriptController(serviceTranscript services.TranscriptService) TranscriptController {
+	return &transcriptController{serviceTranscript: serviceTranscript}
+}
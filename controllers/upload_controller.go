@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go_starter/errs"
+	"go_starter/logs"
+	"go_starter/requests"
+	"go_starter/responses"
+	"go_starter/services"
+	"go_starter/validation"
+	"strconv"
+)
+
+type UploadController interface {
+	CreateUploadSessionController(ctx *fiber.Ctx) error
+	GetUploadOffsetController(ctx *fiber.Ctx) error
+	GetUploadSessionController(ctx *fiber.Ctx) error
+	AppendUploadChunkController(ctx *fiber.Ctx) error
+	DeleteUploadSessionController(ctx *fiber.Ctx) error
+}
+
+type uploadController struct {
+	serviceUpload services.UploadService
+}
+
+func (u *uploadController) CreateUploadSessionController(ctx *fiber.Ctx) error {
+	request := new(requests.UploadSessionRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		logs.Error(err)
+		return NewErrorResponses(ctx, err)
+	}
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := u.serviceUpload.CreateUploadSessionService(ctx.UserContext(), *request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	setUploadHeaders(ctx, response)
+	return NewSuccessResponse(ctx, response)
+}
+
+// GetUploadOffsetController answers HEAD with the offset to resume from
+func (u *uploadController) GetUploadOffsetController(ctx *fiber.Ctx) error {
+	response, err := u.serviceUpload.GetUploadSessionService(ctx.UserContext(), requests.UploadIDRequest{UploadID: ctx.Params("id")})
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	setUploadHeaders(ctx, response)
+	ctx.Set(fiber.HeaderCacheControl, "no-store")
+	return ctx.SendStatus(fiber.StatusOK)
+}
+
+func (u *uploadController) GetUploadSessionController(ctx *fiber.Ctx) error {
+	response, err := u.serviceUpload.GetUploadSessionService(ctx.UserContext(), requests.UploadIDRequest{UploadID: ctx.Params("id")})
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	setUploadHeaders(ctx, response)
+	ctx.Set(fiber.HeaderCacheControl, "no-store")
+	return NewSuccessResponse(ctx, response)
+}
+
+// AppendUploadChunkController takes a PATCH whose body is the chunk starting at Upload-Offset
+func (u *uploadController) AppendUploadChunkController(ctx *fiber.Ctx) error {
+	offset, err := strconv.ParseInt(ctx.Get("Upload-Offset"), 10, 64)
+	if err != nil {
+		return NewErrorResponses(ctx, errs.New(errs.CodeBadRequest).WithField("Upload-Offset", "validation.invalid"))
+	}
+	request := requests.UploadChunkRequest{
+		UploadID: ctx.Params("id"),
+		Offset:   offset,
+		Checksum: ctx.Get("Upload-Checksum"),
+		// fasthttp reuses the body buffer after the handler returns
+		Content: append([]byte(nil), ctx.Body()...),
+	}
+	errValidate := validation.Validate(request)
+	if errValidate != nil {
+		return NewErrorValidate(ctx, errValidate)
+	}
+	response, err := u.serviceUpload.AppendUploadChunkService(ctx.UserContext(), request)
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	setUploadHeaders(ctx, response)
+	return NewSuccessResponse(ctx, response)
+}
+
+func (u *uploadController) DeleteUploadSessionController(ctx *fiber.Ctx) error {
+	response, err := u.serviceUpload.DeleteUploadSessionService(ctx.UserContext(), requests.UploadIDRequest{UploadID: ctx.Params("id")})
+	if err != nil {
+		return NewErrorResponses(ctx, err)
+	}
+	return NewSuccessMsg(ctx, response.Message)
+}
+
+func setUploadHeaders(ctx *fiber.Ctx, response *responses.UploadSessionResponse) {
+	ctx.Set("Upload-Offset", strconv.FormatInt(response.Offset, 10))
+	ctx.Set("Upload-Length", strconv.FormatInt(response.Size, 10))
+}
+
+func NewUploadController(serviceUpload services.UploadService) UploadController {
+	return &uploadController{serviceUpload: serviceUpload}
+}
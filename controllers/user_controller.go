@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"go_starter/errs"
 	"go_starter/logs"
 	"go_starter/requests"
 	"go_starter/services"
@@ -37,11 +38,11 @@ func (u *userController) LoginController(ctx *fiber.Ctx) error {
 	// Validate request data
 	errValidate := validation.Validate(request)
 	if errValidate != nil {
-		return NewErrorValidate(ctx, errValidate[0].Error)
+		return NewErrorValidate(ctx, errValidate)
 	}
 
 	// Call the login service
-	response, token, err := u.serviceUser.LoginService(*request)
+	response, token, err := u.serviceUser.LoginService(ctx.UserContext(), *request)
 	if err != nil {
 		return NewErrorResponses(ctx, err)
 	}
@@ -57,15 +58,15 @@ func (u *userController) DeleteUserController(ctx *fiber.Ctx) error {
 
 	request := new(requests.DeleteUserRequest)
 
-	if err := ctx.BodyParser(request); err != nil {
+	if err := parseRequest(ctx, request); err != nil {
 		return NewErrorResponses(ctx, err)
 	}
 	errValidate := validation.Validate(request)
 	if errValidate != nil {
 
-		return NewErrorValidate(ctx, errValidate[0].Error)
+		return NewErrorValidate(ctx, errValidate)
 	}
-	response, err := u.serviceUser.DeleteUserService(*request)
+	response, err := u.serviceUser.DeleteUserService(ctx.UserContext(), *request)
 
 	if err != nil {
 		return NewErrorResponses(ctx, err)
@@ -79,33 +80,22 @@ func (u *userController) DeleteUserController(ctx *fiber.Ctx) error {
 func (u *userController) GetAllUserController(ctx *fiber.Ctx) error {
 
 	//fetch User data from service folder
-	data, err := u.serviceUser.GetAllUserService()
+	data, err := u.serviceUser.GetAllUserService(ctx.UserContext())
 	if err != nil {
-		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"message": "Failed to retrieve customer data",
-			"error":   err.Error(),
-		})
+		return NewErrorMessageResponses(ctx, err, retrieveFailedMessage)
 	}
 
 	//return http response
-	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
-		"success": true,
-		"data":    data,
-	})
+	return NewListResponse(ctx, data, len(data))
 }
 
 // GetUserByIdController implements UserController.
 func (u *userController) GetUserByIdController(ctx *fiber.Ctx) error {
 	id, err := ctx.ParamsInt("id")
-	if err != nil {
-		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"message": "Failed to retrieve customer data",
-			"error":   err.Error(),
-		})
+	if err != nil || id <= 0 {
+		return NewErrorMessageResponses(ctx, errs.New(errs.CodeBadRequest).WithField("id", "validation.invalid"), retrieveFailedMessage)
 	}
-	response, err := u.serviceUser.GetByIdUserService(uint(id))
+	response, err := u.serviceUser.GetByIdUserService(ctx.UserContext(), uint(id))
 	if err != nil {
 		return NewErrorResponses(ctx, err)
 	}
@@ -128,9 +118,9 @@ func (u *userController) SignInUserController(ctx *fiber.Ctx) error {
 	}
 	errValidate := validation.Validate(request)
 	if errValidate != nil {
-		return NewErrorValidate(ctx, errValidate[0].Error)
+		return NewErrorValidate(ctx, errValidate)
 	}
-	response, err := u.serviceUser.SignInUserService(*request)
+	response, err := u.serviceUser.SignInUserService(ctx.UserContext(), *request)
 
 	if err != nil {
 		return NewErrorResponses(ctx, err)
@@ -162,14 +152,14 @@ func (u *userController) SignInUserController(ctx *fiber.Ctx) error {
 func (u *userController) UpdateUserController(ctx *fiber.Ctx) error {
 
 	request := new(requests.UpdateUserRequest)
-	if err := ctx.BodyParser(request); err != nil {
+	if err := parseRequest(ctx, request); err != nil {
 		return NewErrorResponses(ctx, err)
 	}
 	errValidate := validation.Validate(request)
 	if errValidate != nil {
-		return NewErrorValidate(ctx, errValidate[0].Error)
+		return NewErrorValidate(ctx, errValidate)
 	}
-	response, err := u.serviceUser.UpdateUserService(*request)
+	response, err := u.serviceUser.UpdateUserService(ctx.UserContext(), *request)
 	if err != nil {
 		return NewErrorResponses(ctx, err)
 	}
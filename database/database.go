@@ -0,0 +1,177 @@
+package database
+
+import (
+	"fmt"
+	"github.com/glebarez/sqlite"
+	"github.com/pkg/errors"
+	"go_starter/config"
+	"go_starter/logs"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Drivers selectable with database.driver, named like the gorm dialectors
+const (
+	DriverPostgres = "postgres"
+	DriverMysql    = "mysql"
+	DriverSqlite   = "sqlite"
+)
+
+// sqliteMemory is the sqlite path of a database living only as long as its connection
+const sqliteMemory = ":memory:"
+
+// Connection opens the database selected by database.driver, postgres when unset
+func Connection() (*gorm.DB, error) {
+	return Open(config.GetEnv("database.driver", DriverPostgres))
+}
+
+// Open connects to a driver configured in its own block of config.yaml, applies the
+// pool settings and query timeout of the database block, translates driver errors to
+// errs codes and installs the read replicas listed there
+func Open(driver string) (*gorm.DB, error) {
+	dsn, err := DSN(driver)
+	if err != nil {
+		return nil, err
+	}
+	db, err := connect(driver, dsn, strings.ToUpper(driver), true)
+	if err != nil {
+		return nil, err
+	}
+	if driver == DriverSqlite && sqlitePath(nil) == sqliteMemory {
+		// Every connection to :memory: is a separate empty database
+		sqlDB, _ := db.DB()
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+		sqlDB.SetConnMaxIdleTime(0)
+	}
+	if err = db.Use(&ErrorTranslator{}); err != nil {
+		return nil, err
+	}
+	if err = useQueryTimeout(db); err != nil {
+		return nil, err
+	}
+	if err = useReplicas(db, driver); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// connect opens one connection pool, without ping a server that is down only fails
+// its first query
+func connect(driver, dsn, name string, ping bool) (*gorm.DB, error) {
+	var dialector gorm.Dialector
+	switch driver {
+	case DriverPostgres:
+		dialector = postgres.Open(dsn)
+	case DriverMysql:
+		dialector = mysql.Open(dsn)
+	case DriverSqlite:
+		dialector = sqlite.Open(dsn)
+	}
+
+	location := timezone()
+	fmt.Println("CONNECTING_TO_" + name + "_DB")
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger:               logger.Default.LogMode(logger.Info),
+		DisableAutomaticPing: !ping,
+		NowFunc: func() time.Time {
+			return time.Now().In(location)
+		},
+	})
+	if err != nil {
+		logs.Error(err)
+		return nil, errors.Wrapf(err, "failed to connect to %s", strings.ToLower(name))
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(configInt("database.max_open_conns", 25))
+	sqlDB.SetMaxIdleConns(configInt("database.max_idle_conns", 10))
+	sqlDB.SetConnMaxLifetime(time.Duration(configInt("database.conn_max_lifetime_minutes", 30)) * time.Minute)
+	sqlDB.SetConnMaxIdleTime(time.Duration(configInt("database.conn_max_idle_time_minutes", 5)) * time.Minute)
+	if !ping {
+		return db, nil
+	}
+	if err = sqlDB.Ping(); err != nil {
+		logs.Error(err)
+		return nil, errors.Wrapf(err, "failed to ping %s", strings.ToLower(name))
+	}
+	fmt.Println(name + "_CONNECTED")
+	return db, nil
+}
+
+// DSN builds the connection string of a driver, postgres and mysql read host, port,
+// user, password and database from their block, sqlite reads path
+func DSN(driver string) (string, error) {
+	return dsn(driver, nil)
+}
+
+// dsn is DSN with keys of the driver block replaced by overrides, a replica only
+// lists what differs from the primary
+func dsn(driver string, overrides map[string]string) (string, error) {
+	setting := func(key, defaultValue string) string {
+		if value := overrides[key]; value != "" {
+			return value
+		}
+		return config.GetEnv(driver+"."+key, defaultValue)
+	}
+	switch driver {
+	case DriverPostgres:
+		return fmt.Sprintf("host=%v user=%v password=%v dbname=%v port=%v sslmode=%v TimeZone=%v",
+			setting("host", ""),
+			setting("user", ""),
+			setting("password", ""),
+			setting("database", ""),
+			setting("port", "5432"),
+			setting("sslmode", "disable"),
+			timezone().String(),
+		), nil
+	case DriverMysql:
+		return fmt.Sprintf("%v:%v@tcp(%v:%v)/%v?charset=utf8mb4&parseTime=True&loc=%v",
+			setting("user", ""),
+			setting("password", ""),
+			setting("host", ""),
+			setting("port", "3306"),
+			setting("database", ""),
+			url.QueryEscape(timezone().String()),
+		), nil
+	case DriverSqlite:
+		// Foreign keys are off in sqlite unless asked for, the busy timeout lets
+		// writers wait for each other instead of failing
+		return sqlitePath(overrides) + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)", nil
+	}
+	return "", fmt.Errorf("unknown database driver %q, use postgres, mysql or sqlite", driver)
+}
+
+func sqlitePath(overrides map[string]string) string {
+	if path := overrides["path"]; path != "" {
+		return path
+	}
+	return config.GetEnv("sqlite.path", "go_starter.db")
+}
+
+func timezone() *time.Location {
+	location, err := time.LoadLocation(config.GetEnv("database.timezone", "Asia/Bangkok"))
+	if err != nil {
+		logs.Error(err)
+		return time.Local
+	}
+	return location
+}
+
+func configInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(config.GetEnv(key, strconv.Itoa(defaultValue)))
+	if err != nil || value < 0 {
+		logs.Error(errors.Errorf("invalid %s", key))
+		return defaultValue
+	}
+	return value
+}
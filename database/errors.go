@@ -0,0 +1,151 @@
+package database
+
+import (
+	"errors"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgconn"
+	"go_starter/errs"
+	"gorm.io/gorm"
+	"regexp"
+	"strings"
+)
+
+const errorTranslatorName = "error_translator"
+
+// notFoundCodes is the code of a row missing from a table
+var notFoundCodes = map[string]errs.Code{
+	"students":           errs.CodeStudentNotFound,
+	"teachers":           errs.CodeTeacherNotFound,
+	"users":              errs.CodeUserNotFound,
+	"classrooms":         errs.CodeClassroomNotFound,
+	"classroom_sessions": errs.CodeClassroomSessionNotFound,
+	"terms":              errs.CodeTermNotFound,
+	"grading_scales":     errs.CodeGradingScaleNotFound,
+	"assessments":        errs.CodeAssessmentNotFound,
+	"transcripts":        errs.CodeTranscriptNotFound,
+	"student_documents":  errs.CodeDocumentNotFound,
+	"upload_sessions":    errs.CodeUploadNotFound,
+	"file_objects":       errs.CodeFileNotFound,
+}
+
+type uniqueConstraint struct {
+	code errs.Code
+	// field is the request field holding the duplicate value
+	field string
+	// columns is how sqlite, which reports no constraint names, lists the columns
+	columns string
+}
+
+// uniqueConstraints are the unique constraints of the migrations a client can run into,
+// any other violation is answered with errs.CodeAlreadyExists
+var uniqueConstraints = map[string]uniqueConstraint{
+	"uni_students_phone":           {errs.CodePhoneInUse, "phone", "students.phone"},
+	"uni_teachers_phone":           {errs.CodePhoneInUse, "phone", "teachers.phone"},
+	"uni_users_email":              {errs.CodeEmailInUse, "email", "users.email"},
+	"uni_terms_name":               {errs.CodeNameInUse, "name", "terms.name"},
+	"uni_grading_scales_name":      {errs.CodeNameInUse, "name", "grading_scales.name"},
+	"idx_check_in_session_student": {errs.CodeAlreadyCheckedIn, "", "attendance_check_ins.classroom_session_id, attendance_check_ins.student_id"},
+}
+
+// sqlite extended result codes, see https://www.sqlite.org/rescode.html
+const (
+	sqliteConstraintForeignKey = 787
+	sqliteConstraintPrimaryKey = 1555
+	sqliteConstraintUnique     = 2067
+)
+
+var mysqlDuplicateKey = regexp.MustCompile(`for key '([^']+)'`)
+
+var sqliteUniqueColumns = regexp.MustCompile(`UNIQUE constraint failed: (.+?)(?: \(\d+\))?$`)
+
+// ErrorTranslator is a gorm plugin replacing driver errors by errs catalog errors. A
+// missing row gets the not found code of its table, unique and foreign key violations
+// the code of their constraint. The original error stays the cause, errors.Is still
+// finds gorm.ErrRecordNotFound.
+type ErrorTranslator struct{}
+
+func (e *ErrorTranslator) Name() string {
+	return errorTranslatorName
+}
+
+func (e *ErrorTranslator) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	for _, err := range []error{
+		callback.Query().After("*").Register("error_translator:translate", translateError),
+		callback.Row().After("*").Register("error_translator:translate", translateError),
+		callback.Create().After("*").Register("error_translator:translate", translateError),
+		callback.Update().After("*").Register("error_translator:translate", translateError),
+		callback.Delete().After("*").Register("error_translator:translate", translateError),
+		callback.Raw().After("*").Register("error_translator:translate", translateError),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func translateError(db *gorm.DB) {
+	if db.Error == nil {
+		return
+	}
+	var appError errs.AppError
+	if errors.As(db.Error, &appError) {
+		return
+	}
+	if errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		code, ok := notFoundCodes[db.Statement.Table]
+		if !ok {
+			code = errs.CodeNotFound
+		}
+		db.Error = errs.Wrap(code, db.Error)
+		return
+	}
+	if constraint, unique, foreignKey := violation(db.Error); unique {
+		translated, ok := uniqueConstraints[constraint]
+		if !ok {
+			db.Error = errs.Wrap(errs.CodeAlreadyExists, db.Error)
+			return
+		}
+		appError = errs.Wrap(translated.code, db.Error)
+		if translated.field != "" {
+			appError = appError.WithField(translated.field, string(translated.code))
+		}
+		db.Error = appError
+	} else if foreignKey {
+		db.Error = errs.Wrap(errs.CodeReferenceViolated, db.Error)
+	}
+}
+
+// violation reports whether err is a unique or foreign key violation and the name of
+// the violated constraint, for sqlite the columns it lists
+func violation(err error) (constraint string, unique, foreignKey bool) {
+	var pgError *pgconn.PgError
+	if errors.As(err, &pgError) {
+		return pgError.ConstraintName, pgError.Code == "23505", pgError.Code == "23503"
+	}
+	var mysqlError *mysql.MySQLError
+	if errors.As(err, &mysqlError) {
+		if match := mysqlDuplicateKey.FindStringSubmatch(mysqlError.Message); match != nil {
+			// MySQL 8 prefixes the key with its table
+			constraint = match[1][strings.LastIndex(match[1], ".")+1:]
+		}
+		return constraint, mysqlError.Number == 1062, mysqlError.Number == 1451 || mysqlError.Number == 1452
+	}
+	var sqliteError interface{ Code() int }
+	if errors.As(err, &sqliteError) {
+		code := sqliteError.Code()
+		if code == sqliteConstraintUnique || code == sqliteConstraintPrimaryKey {
+			if match := sqliteUniqueColumns.FindStringSubmatch(err.Error()); match != nil {
+				for name, candidate := range uniqueConstraints {
+					if candidate.columns == match[1] {
+						return name, true, false
+					}
+				}
+			}
+			return "", true, false
+		}
+		return "", false, code == sqliteConstraintForeignKey
+	}
+	return "", false, false
+}
@@ -0,0 +1,362 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"go_starter/logs"
+	"gorm.io/gorm"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const replicaSetName = "replicas"
+
+type contextKey int
+
+const (
+	sessionContextKey contextKey = iota
+	primaryContextKey
+	queryTimeoutContextKey
+)
+
+// ReplicaStatus is the health of a read replica as of its last check
+type ReplicaStatus struct {
+	Name       string    `json:"name"`
+	Healthy    bool      `json:"healthy"`
+	LagSeconds float64   `json:"lag_seconds"`
+	Reads      uint64    `json:"reads"`
+	CheckedAt  time.Time `json:"checked_at"`
+	Error      string    `json:"error,omitempty"`
+}
+
+type replica struct {
+	name  string
+	db    *sql.DB
+	reads uint64
+
+	mu     sync.RWMutex
+	status ReplicaStatus
+}
+
+func (r *replica) healthy() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.status.Healthy
+}
+
+// ReplicaSet is a gorm plugin sending reads made outside a transaction to the healthy
+// read replicas in turn. Writes, locking reads, reads inside transactions and reads of
+// a session that wrote within the read-your-writes window stay on the primary.
+type ReplicaSet struct {
+	driver       string
+	primary      gorm.ConnPool
+	replicas     []*replica
+	next         uint64
+	primaryReads uint64
+	maxLag       time.Duration
+	window       time.Duration
+
+	mu     sync.Mutex
+	writes map[string]time.Time
+}
+
+// WithSession tags ctx with the client it serves, the read-your-writes window is kept
+// per session. Queries without one share a single window.
+func WithSession(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, sessionContextKey, key)
+}
+
+// WithPrimary sends every read made with ctx to the primary
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryContextKey, true)
+}
+
+// Replicas returns the replica set installed on db, nil when none is configured
+func Replicas(db *gorm.DB) *ReplicaSet {
+	if plugin, ok := db.Config.Plugins[replicaSetName]; ok {
+		return plugin.(*ReplicaSet)
+	}
+	return nil
+}
+
+// useReplicas connects the replicas listed under database.replicas, each entry overrides
+// keys of the driver block, and installs them on the primary
+func useReplicas(db *gorm.DB, driver string) error {
+	var entries []map[string]string
+	if err := viper.UnmarshalKey("database.replicas", &entries); err != nil {
+		return errors.Wrap(err, "invalid database.replicas")
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	set := &ReplicaSet{
+		driver:  driver,
+		primary: db.Config.ConnPool,
+		maxLag:  time.Duration(configInt("database.replica_max_lag_seconds", 5)) * time.Second,
+		window:  time.Duration(configInt("database.read_your_writes_seconds", 10)) * time.Second,
+		writes:  map[string]time.Time{},
+	}
+	for i, entry := range entries {
+		name := entry["name"]
+		if name == "" {
+			name = "replica-" + strconv.Itoa(i+1)
+		}
+		replicaDSN, err := dsn(driver, entry)
+		if err != nil {
+			return err
+		}
+		// A replica that is down gets no reads until a health check passes
+		replicaDB, err := connect(driver, replicaDSN, strings.ToUpper(driver)+"_"+strings.ToUpper(name), false)
+		if err != nil {
+			return err
+		}
+		sqlDB, err := replicaDB.DB()
+		if err != nil {
+			return err
+		}
+		set.replicas = append(set.replicas, &replica{name: name, db: sqlDB, status: ReplicaStatus{Name: name}})
+	}
+	if err := db.Use(set); err != nil {
+		return err
+	}
+
+	set.CheckReplicas()
+	interval := time.Duration(configInt("database.replica_health_check_seconds", 10)) * time.Second
+	if interval > 0 {
+		go func() {
+			for range time.Tick(interval) {
+				set.CheckReplicas()
+			}
+		}()
+	}
+	return nil
+}
+
+func (r *ReplicaSet) Name() string {
+	return replicaSetName
+}
+
+func (r *ReplicaSet) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	for _, err := range []error{
+		callback.Query().Before("gorm:query").Register("replicas:route", r.routeRead),
+		callback.Row().Before("gorm:row").Register("replicas:route", r.routeRead),
+		callback.Create().Before("gorm:create").Register("replicas:route", r.routeWrite),
+		callback.Update().Before("gorm:update").Register("replicas:route", r.routeWrite),
+		callback.Delete().Before("gorm:delete").Register("replicas:route", r.routeWrite),
+		callback.Raw().Before("gorm:raw").Register("replicas:route", r.routeWrite),
+		callback.Create().After("gorm:create").Register("replicas:record_write", r.recordWrite),
+		callback.Update().After("gorm:update").Register("replicas:record_write", r.recordWrite),
+		callback.Delete().After("gorm:delete").Register("replicas:record_write", r.recordWrite),
+		callback.Raw().After("gorm:raw").Register("replicas:record_write", r.recordWrite),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Status returns the last health check of every replica
+func (r *ReplicaSet) Status() []ReplicaStatus {
+	var status []ReplicaStatus
+	for _, replica := range r.replicas {
+		replica.mu.RLock()
+		row := replica.status
+		replica.mu.RUnlock()
+		row.Reads = atomic.LoadUint64(&replica.reads)
+		status = append(status, row)
+	}
+	return status
+}
+
+// PrimaryReads counts the reads kept on the primary by the read-your-writes window,
+// WithPrimary or a lack of healthy replicas
+func (r *ReplicaSet) PrimaryReads() uint64 {
+	return atomic.LoadUint64(&r.primaryReads)
+}
+
+// CheckReplicas pings every replica and measures its lag, a replica failing either
+// check gets no reads until it passes again
+func (r *ReplicaSet) CheckReplicas() {
+	for _, replica := range r.replicas {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		lag, err := replicationLag(ctx, r.driver, replica.db)
+		cancel()
+
+		status := ReplicaStatus{Name: replica.name, LagSeconds: lag, CheckedAt: time.Now()}
+		if err != nil {
+			status.Error = err.Error()
+		} else if r.maxLag > 0 && lag > r.maxLag.Seconds() {
+			status.Error = fmt.Sprintf("lag of %.1fs is over %s", lag, r.maxLag)
+		}
+		status.Healthy = status.Error == ""
+
+		replica.mu.Lock()
+		if replica.status.Healthy != status.Healthy && !(replica.status.CheckedAt.IsZero() && status.Healthy) {
+			if status.Healthy {
+				logs.Info("read replica " + replica.name + " is healthy again")
+			} else {
+				logs.Error("read replica " + replica.name + " taken out of rotation: " + status.Error)
+			}
+		}
+		replica.status = status
+		replica.mu.Unlock()
+	}
+}
+
+func (r *ReplicaSet) routeRead(db *gorm.DB) {
+	if db.Error != nil || inTransaction(db) {
+		return
+	}
+	if _, locking := db.Statement.Clauses["FOR"]; locking {
+		return
+	}
+	// Raw SQL run through Scan or Row is only moved when it is a plain select
+	if raw := strings.ToLower(strings.TrimSpace(db.Statement.SQL.String())); raw != "" {
+		if !strings.HasPrefix(raw, "select") || strings.HasSuffix(raw, "for update") {
+			return
+		}
+	}
+	ctx := db.Statement.Context
+	if (ctx != nil && ctx.Value(primaryContextKey) != nil) || r.wroteRecently(sessionKey(ctx)) {
+		atomic.AddUint64(&r.primaryReads, 1)
+		return
+	}
+	replica := r.pick()
+	if replica == nil {
+		atomic.AddUint64(&r.primaryReads, 1)
+		return
+	}
+	db.Statement.ConnPool = replica.db
+}
+
+// routeWrite moves a statement reused after a read back to the primary
+func (r *ReplicaSet) routeWrite(db *gorm.DB) {
+	if inTransaction(db) {
+		return
+	}
+	for _, replica := range r.replicas {
+		if db.Statement.ConnPool == gorm.ConnPool(replica.db) {
+			db.Statement.ConnPool = r.primary
+			return
+		}
+	}
+}
+
+func (r *ReplicaSet) recordWrite(db *gorm.DB) {
+	if db.Error != nil || r.window <= 0 {
+		return
+	}
+	key := sessionKey(db.Statement.Context)
+	now := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.writes[key] = now
+	if len(r.writes) > 1024 {
+		for session, wroteAt := range r.writes {
+			if now.Sub(wroteAt) >= r.window {
+				delete(r.writes, session)
+			}
+		}
+	}
+}
+
+func (r *ReplicaSet) wroteRecently(key string) bool {
+	if r.window <= 0 {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	wroteAt, ok := r.writes[key]
+	return ok && time.Since(wroteAt) < r.window
+}
+
+// pick returns the next healthy replica round-robin, nil when none is healthy
+func (r *ReplicaSet) pick() *replica {
+	count := uint64(len(r.replicas))
+	start := atomic.AddUint64(&r.next, 1)
+	for i := uint64(0); i < count; i++ {
+		replica := r.replicas[(start+i)%count]
+		if replica.healthy() {
+			atomic.AddUint64(&replica.reads, 1)
+			return replica
+		}
+	}
+	return nil
+}
+
+func sessionKey(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	key, _ := ctx.Value(sessionContextKey).(string)
+	return key
+}
+
+func inTransaction(db *gorm.DB) bool {
+	_, ok := db.Statement.ConnPool.(gorm.TxCommitter)
+	return ok
+}
+
+// replicationLag returns how many seconds a replica is behind its primary
+func replicationLag(ctx context.Context, driver string, db *sql.DB) (float64, error) {
+	switch driver {
+	case DriverPostgres:
+		// A replica that replayed everything it received is current however old its
+		// last transaction is
+		var lag float64
+		err := db.QueryRowContext(ctx, `SELECT CASE
+			WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0::float8
+			ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)::float8
+		END`).Scan(&lag)
+		return lag, err
+	case DriverMysql:
+		return mysqlReplicationLag(ctx, db)
+	}
+	// sqlite replicas are file copies without a replication stream to measure
+	return 0, db.PingContext(ctx)
+}
+
+func mysqlReplicationLag(ctx context.Context, db *sql.DB) (float64, error) {
+	rows, err := db.QueryContext(ctx, "SHOW REPLICA STATUS")
+	if err != nil {
+		// Servers before 8.0.22 only know the old name
+		if rows, err = db.QueryContext(ctx, "SHOW SLAVE STATUS"); err != nil {
+			return 0, err
+		}
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	if !rows.Next() {
+		// Not replicating, a standalone server is never behind
+		return 0, rows.Err()
+	}
+	values := make([]sql.NullString, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	if err = rows.Scan(pointers...); err != nil {
+		return 0, err
+	}
+	for i, column := range columns {
+		if column == "Seconds_Behind_Source" || column == "Seconds_Behind_Master" {
+			if !values[i].Valid {
+				return 0, errors.New("replication is not running")
+			}
+			return strconv.ParseFloat(values[i].String, 64)
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,100 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+)
+
+const queryTimeoutName = "query_timeout"
+
+// queryTimeoutSetting is the statement setting holding the context to restore once
+// the statement finished
+type queryTimeoutSetting struct{}
+
+type queryTimeoutState struct {
+	parent context.Context
+	cancel context.CancelFunc
+}
+
+// WithQueryTimeout gives every query made with ctx its own deadline in place of
+// database.query_timeout_seconds, 0 lets a query run as long as ctx allows
+func WithQueryTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, queryTimeoutContextKey, timeout)
+}
+
+// QueryTimeout is a gorm plugin bounding every statement by a deadline, a query
+// outliving it is cancelled and fails with context.DeadlineExceeded
+type QueryTimeout struct {
+	timeout time.Duration
+}
+
+func (q *QueryTimeout) Name() string {
+	return queryTimeoutName
+}
+
+func (q *QueryTimeout) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	// Row and Rows are left alone, their result is scanned after the callbacks returned
+	for _, err := range []error{
+		callback.Query().Before("*").Register("query_timeout:start", q.start),
+		callback.Create().Before("*").Register("query_timeout:start", q.start),
+		callback.Update().Before("*").Register("query_timeout:start", q.start),
+		callback.Delete().Before("*").Register("query_timeout:start", q.start),
+		callback.Raw().Before("*").Register("query_timeout:start", q.start),
+		callback.Query().After("*").Register("query_timeout:finish", q.finish),
+		callback.Create().After("*").Register("query_timeout:finish", q.finish),
+		callback.Update().After("*").Register("query_timeout:finish", q.finish),
+		callback.Delete().After("*").Register("query_timeout:finish", q.finish),
+		callback.Raw().After("*").Register("query_timeout:finish", q.finish),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (q *QueryTimeout) start(db *gorm.DB) {
+	parent := db.Statement.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+	timeout := q.timeout
+	if override, ok := parent.Value(queryTimeoutContextKey).(time.Duration); ok {
+		timeout = override
+	}
+	if timeout <= 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	db.Statement.Context = ctx
+	db.Statement.Settings.Store(queryTimeoutSetting{}, queryTimeoutState{parent: parent, cancel: cancel})
+}
+
+func (q *QueryTimeout) finish(db *gorm.DB) {
+	value, ok := db.Statement.Settings.LoadAndDelete(queryTimeoutSetting{})
+	if !ok {
+		return
+	}
+	state := value.(queryTimeoutState)
+	// Drivers report an interrupted query in their own words, keep the cause visible
+	// to errors.Is
+	if err := db.Statement.Context.Err(); err != nil && db.Error != nil &&
+		!errors.Is(db.Error, context.DeadlineExceeded) && !errors.Is(db.Error, context.Canceled) {
+		db.Error = fmt.Errorf("%w: %v", err, db.Error)
+	}
+	state.cancel()
+	db.Statement.Context = state.parent
+}
+
+// useQueryTimeout installs the deadline of database.query_timeout_seconds, 0 turns it off
+func useQueryTimeout(db *gorm.DB) error {
+	timeout := time.Duration(configInt("database.query_timeout_seconds", 10)) * time.Second
+	if timeout <= 0 {
+		return nil
+	}
+	return db.Use(&QueryTimeout{timeout: timeout})
+}
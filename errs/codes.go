@@ -0,0 +1,237 @@
+package errs
+
+import (
+	"net/http"
+	"sort"
+)
+
+// Code is a stable, machine-readable error identifier. Clients branch on it instead
+// of the message, which may be reworded or translated.
+type Code string
+
+// StatusClientClosedRequest answers a request whose client went away or whose work was
+// cancelled before it finished, nginx logs such requests with the same code
+const StatusClientClosedRequest = 499
+
+// StatusChecksumMismatch is the tus status for a chunk whose checksum does not match
+const StatusChecksumMismatch = 460
+
+// Generic codes, used when no more specific one applies
+const (
+	CodeBadRequest           Code = "BAD_REQUEST"
+	CodeValidationFailed     Code = "VALIDATION_FAILED"
+	CodeUnauthorized         Code = "UNAUTHORIZED"
+	CodeForbidden            Code = "FORBIDDEN"
+	CodeNotFound             Code = "NOT_FOUND"
+	CodeConflict             Code = "CONFLICT"
+	CodeAlreadyExists        Code = "ALREADY_EXISTS"
+	CodeReferenceViolated    Code = "REFERENCE_VIOLATED"
+	CodeGone                 Code = "GONE"
+	CodePayloadTooLarge      Code = "PAYLOAD_TOO_LARGE"
+	CodeUnsupportedMediaType Code = "UNSUPPORTED_MEDIA_TYPE"
+	CodeChecksumMismatch     Code = "CHECKSUM_MISMATCH"
+	CodeInternal             Code = "INTERNAL_ERROR"
+	CodeRequestTimeout       Code = "REQUEST_TIMEOUT"
+	CodeRequestCancelled     Code = "REQUEST_CANCELLED"
+)
+
+// Domain codes
+const (
+	CodeInvalidCredentials       Code = "INVALID_CREDENTIALS"
+	CodeInvalidUserType          Code = "INVALID_USER_TYPE"
+	CodeInvalidDate              Code = "INVALID_DATE"
+	CodeAccountCreated           Code = "ACCOUNT_CREATED"
+	CodeStudentNotFound          Code = "STUDENT_NOT_FOUND"
+	CodeTeacherNotFound          Code = "TEACHER_NOT_FOUND"
+	CodeUserNotFound             Code = "USER_NOT_FOUND"
+	CodeClassroomNotFound        Code = "CLASSROOM_NOT_FOUND"
+	CodeClassroomSessionNotFound Code = "CLASSROOM_SESSION_NOT_FOUND"
+	CodeTermNotFound             Code = "TERM_NOT_FOUND"
+	CodeGradingScaleNotFound     Code = "GRADING_SCALE_NOT_FOUND"
+	CodeAssessmentNotFound       Code = "ASSESSMENT_NOT_FOUND"
+	CodeTranscriptNotFound       Code = "TRANSCRIPT_NOT_FOUND"
+	CodeDocumentNotFound         Code = "DOCUMENT_NOT_FOUND"
+	CodeUploadNotFound           Code = "UPLOAD_NOT_FOUND"
+	CodeFileNotFound             Code = "FILE_NOT_FOUND"
+	CodePhoneInUse               Code = "PHONE_IN_USE"
+	CodeEmailInUse               Code = "EMAIL_IN_USE"
+	CodeStudentIDInUse           Code = "STUDENT_ID_IN_USE"
+	CodeNameInUse                Code = "NAME_IN_USE"
+	CodeNotEnrolled              Code = "NOT_ENROLLED"
+	CodeAlreadyCheckedIn         Code = "ALREADY_CHECKED_IN"
+	CodeCheckInCodeUsed          Code = "CHECK_IN_CODE_USED"
+	CodeNotClassroomTeacher      Code = "NOT_CLASSROOM_TEACHER"
+	CodeTermFinalized            Code = "TERM_FINALIZED"
+	CodeVirusDetected            Code = "VIRUS_DETECTED"
+	CodeInvalidAccessToken       Code = "INVALID_ACCESS_TOKEN"
+	CodeCredentialsRequired      Code = "CREDENTIALS_REQUIRED"
+	CodeInvalidSignedURL         Code = "INVALID_SIGNED_URL"
+	CodeSignedURLExpired         Code = "SIGNED_URL_EXPIRED"
+	CodePhotoForbidden           Code = "PHOTO_FORBIDDEN"
+	CodeInvalidImageWidth        Code = "INVALID_IMAGE_WIDTH"
+	CodeImageVariantResize       Code = "IMAGE_VARIANT_RESIZE"
+	CodeInvalidDateRange         Code = "INVALID_DATE_RANGE"
+	CodeDuplicateGradeLetter     Code = "DUPLICATE_GRADE_LETTER"
+	CodeGradingScaleIncomplete   Code = "GRADING_SCALE_INCOMPLETE"
+	CodeStudentNotInClassroom    Code = "STUDENT_NOT_IN_CLASSROOM"
+	CodeStudentListedTwice       Code = "STUDENT_LISTED_TWICE"
+	CodeScoreAboveMax            Code = "SCORE_ABOVE_MAX"
+	CodeNoAttendanceRecords      Code = "NO_ATTENDANCE_RECORDS"
+	CodeInvalidCheckInCode       Code = "INVALID_CHECK_IN_CODE"
+	CodeCheckInCodeExpired       Code = "CHECK_IN_CODE_EXPIRED"
+	CodeUnknownDocumentType      Code = "UNKNOWN_DOCUMENT_TYPE"
+	CodeEmptyDocument            Code = "EMPTY_DOCUMENT"
+	CodeDocumentTooLarge         Code = "DOCUMENT_TOO_LARGE"
+	CodeDocumentContentType      Code = "DOCUMENT_CONTENT_TYPE"
+	CodeUploadTooLarge           Code = "UPLOAD_TOO_LARGE"
+	CodeInvalidChecksum          Code = "INVALID_CHECKSUM"
+	CodeUploadCompleted          Code = "UPLOAD_COMPLETED"
+	CodeUploadNotCompleted       Code = "UPLOAD_NOT_COMPLETED"
+	CodeUploadOffsetMismatch     Code = "UPLOAD_OFFSET_MISMATCH"
+	CodeUploadOffsetMoved        Code = "UPLOAD_OFFSET_MOVED"
+	CodeUploadExpired            Code = "UPLOAD_EXPIRED"
+	CodeUploadChecksumMismatch   Code = "UPLOAD_CHECKSUM_MISMATCH"
+	CodeChunkTooLarge            Code = "CHUNK_TOO_LARGE"
+	CodeInvalidChunkChecksum     Code = "INVALID_CHUNK_CHECKSUM"
+	CodeChunkChecksumMismatch    Code = "CHUNK_CHECKSUM_MISMATCH"
+	CodeUnsupportedImage         Code = "UNSUPPORTED_IMAGE"
+	CodeCorruptedImage           Code = "CORRUPTED_IMAGE"
+	CodeImageTooSmall            Code = "IMAGE_TOO_SMALL"
+	CodeImageTooLarge            Code = "IMAGE_TOO_LARGE"
+	CodeNoFinalizedGrades        Code = "NO_FINALIZED_GRADES"
+	CodeNoStudentsEnrolled       Code = "NO_STUDENTS_ENROLLED"
+)
+
+// Definition is the HTTP status and title a code is answered with
+type Definition struct {
+	Code   Code   `json:"code"`
+	Status int    `json:"status"`
+	Title  string `json:"title"`
+}
+
+var catalog = map[Code]Definition{}
+
+func define(code Code, status int, title string) {
+	catalog[code] = Definition{Code: code, Status: status, Title: title}
+}
+
+func init() {
+	define(CodeBadRequest, http.StatusBadRequest, "bad request")
+	define(CodeValidationFailed, http.StatusUnprocessableEntity, "validation failed")
+	define(CodeUnauthorized, http.StatusUnauthorized, "unauthorized")
+	define(CodeForbidden, http.StatusForbidden, "forbidden")
+	define(CodeNotFound, http.StatusNotFound, "not found")
+	define(CodeConflict, http.StatusConflict, "conflict")
+	define(CodeAlreadyExists, http.StatusConflict, "record already exists")
+	define(CodeReferenceViolated, http.StatusConflict, "record is referenced by or references a missing record")
+	define(CodeGone, http.StatusGone, "gone")
+	define(CodePayloadTooLarge, http.StatusRequestEntityTooLarge, "payload too large")
+	define(CodeUnsupportedMediaType, http.StatusUnsupportedMediaType, "unsupported media type")
+	define(CodeChecksumMismatch, StatusChecksumMismatch, "checksum mismatch")
+	define(CodeInternal, http.StatusInternalServerError, "internal server error")
+	define(CodeRequestTimeout, http.StatusGatewayTimeout, "request timed out")
+	define(CodeRequestCancelled, StatusClientClosedRequest, "request cancelled")
+
+	define(CodeInvalidCredentials, http.StatusUnauthorized, "phone, email or password is incorrect")
+	define(CodeInvalidUserType, http.StatusBadRequest, "invalid user type")
+	define(CodeInvalidDate, http.StatusBadRequest, "invalid date, use DD-MM-YYYY")
+	define(CodeAccountCreated, http.StatusUnprocessableEntity, "account created, log in again")
+	define(CodeStudentNotFound, http.StatusNotFound, "student not found")
+	define(CodeTeacherNotFound, http.StatusNotFound, "teacher not found")
+	define(CodeUserNotFound, http.StatusNotFound, "user not found")
+	define(CodeClassroomNotFound, http.StatusNotFound, "classroom not found")
+	define(CodeClassroomSessionNotFound, http.StatusNotFound, "classroom session not found")
+	define(CodeTermNotFound, http.StatusNotFound, "term not found")
+	define(CodeGradingScaleNotFound, http.StatusNotFound, "grading scale not found")
+	define(CodeAssessmentNotFound, http.StatusNotFound, "assessment not found")
+	define(CodeTranscriptNotFound, http.StatusNotFound, "transcript not found")
+	define(CodeDocumentNotFound, http.StatusNotFound, "document not found")
+	define(CodeUploadNotFound, http.StatusNotFound, "upload not found")
+	define(CodeFileNotFound, http.StatusNotFound, "file not found")
+	define(CodePhoneInUse, http.StatusConflict, "phone number already in use")
+	define(CodeEmailInUse, http.StatusConflict, "email already in use")
+	define(CodeStudentIDInUse, http.StatusConflict, "student ID already in use")
+	define(CodeNameInUse, http.StatusConflict, "name already in use")
+	define(CodeNotEnrolled, http.StatusForbidden, "student is not enrolled in this classroom")
+	define(CodeAlreadyCheckedIn, http.StatusConflict, "already checked in to this session")
+	define(CodeCheckInCodeUsed, http.StatusConflict, "check-in code already used, scan the current code")
+	define(CodeNotClassroomTeacher, http.StatusForbidden, "only the teacher of this classroom can do this")
+	define(CodeTermFinalized, http.StatusConflict, "grades for this term are finalized")
+	define(CodeVirusDetected, http.StatusUnprocessableEntity, "document failed the virus scan")
+	define(CodeInvalidAccessToken, http.StatusUnauthorized, "invalid access token")
+	define(CodeCredentialsRequired, http.StatusUnauthorized, "signed url or access token required")
+	define(CodeInvalidSignedURL, http.StatusForbidden, "invalid signed url")
+	define(CodeSignedURLExpired, http.StatusForbidden, "signed url has expired")
+	define(CodePhotoForbidden, http.StatusForbidden, "not allowed to view this photo")
+	define(CodeInvalidImageWidth, http.StatusBadRequest, "width must be positive")
+	define(CodeImageVariantResize, http.StatusBadRequest, "cannot resize an image variant")
+	define(CodeInvalidDateRange, http.StatusBadRequest, "end date must not be before start date")
+	define(CodeDuplicateGradeLetter, http.StatusBadRequest, "letter {letter} appears more than once")
+	define(CodeGradingScaleIncomplete, http.StatusBadRequest, "grading scale must have a grade starting at 0 percent")
+	define(CodeStudentNotInClassroom, http.StatusBadRequest, "student {student_id} is not enrolled in classroom {classroom_id}")
+	define(CodeStudentListedTwice, http.StatusBadRequest, "student {student_id} is listed more than once")
+	define(CodeScoreAboveMax, http.StatusBadRequest, "score for student {student_id} exceeds max score {max_score}")
+	define(CodeNoAttendanceRecords, http.StatusBadRequest, "no attendance records to mark")
+	define(CodeInvalidCheckInCode, http.StatusBadRequest, "invalid check-in code")
+	define(CodeCheckInCodeExpired, http.StatusBadRequest, "check-in code has expired, scan the current code")
+	define(CodeUnknownDocumentType, http.StatusBadRequest, "unknown document type")
+	define(CodeEmptyDocument, http.StatusBadRequest, "document file is empty")
+	define(CodeDocumentTooLarge, http.StatusRequestEntityTooLarge, "{document_type} must be at most {max_mb} MB")
+	define(CodeDocumentContentType, http.StatusUnsupportedMediaType, "{document_type} must be one of {content_types}")
+	define(CodeUploadTooLarge, http.StatusRequestEntityTooLarge, "upload must be at most {max_mb} MB")
+	define(CodeInvalidChecksum, http.StatusBadRequest, "checksum must be a SHA-256 hex digest")
+	define(CodeUploadCompleted, http.StatusConflict, "upload already completed")
+	define(CodeUploadNotCompleted, http.StatusConflict, "upload is not completed")
+	define(CodeUploadOffsetMismatch, http.StatusConflict, "upload offset is {offset}")
+	define(CodeUploadOffsetMoved, http.StatusConflict, "upload offset has moved, request the current offset")
+	define(CodeUploadExpired, http.StatusGone, "upload has expired")
+	define(CodeUploadChecksumMismatch, StatusChecksumMismatch, "file checksum mismatch, the upload was discarded")
+	define(CodeChunkTooLarge, http.StatusBadRequest, "chunk exceeds the upload size")
+	define(CodeInvalidChunkChecksum, http.StatusBadRequest, "chunk checksum must be sha256 and a base64 encoded digest")
+	define(CodeChunkChecksumMismatch, StatusChecksumMismatch, "chunk checksum mismatch")
+	define(CodeUnsupportedImage, http.StatusUnsupportedMediaType, "file is not a PNG, JPEG or WebP image")
+	define(CodeCorruptedImage, http.StatusUnprocessableEntity, "image is corrupted")
+	define(CodeImageTooSmall, http.StatusUnprocessableEntity, "image must be at least {pixels}x{pixels} pixels")
+	define(CodeImageTooLarge, http.StatusUnprocessableEntity, "image must be at most {pixels}x{pixels} pixels")
+	define(CodeNoFinalizedGrades, http.StatusBadRequest, "student has no finalized grades")
+	define(CodeNoStudentsEnrolled, http.StatusBadRequest, "no students enrolled in this classroom")
+}
+
+// Lookup returns the definition of code, an unknown code is answered as an internal error
+func Lookup(code Code) Definition {
+	if definition, ok := catalog[code]; ok {
+		return definition
+	}
+	definition := catalog[CodeInternal]
+	definition.Code = code
+	return definition
+}
+
+// Catalog returns every defined code ordered by code
+func Catalog() []Definition {
+	definitions := make([]Definition, 0, len(catalog))
+	for _, definition := range catalog {
+		definitions = append(definitions, definition)
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Code < definitions[j].Code
+	})
+	return definitions
+}
+
+// codeForStatus is the generic code of errors built from a bare HTTP status
+var codeForStatus = map[int]Code{
+	http.StatusBadRequest:            CodeBadRequest,
+	http.StatusUnauthorized:          CodeUnauthorized,
+	http.StatusForbidden:             CodeForbidden,
+	http.StatusNotFound:              CodeNotFound,
+	http.StatusConflict:              CodeConflict,
+	http.StatusGone:                  CodeGone,
+	http.StatusRequestEntityTooLarge: CodePayloadTooLarge,
+	http.StatusUnsupportedMediaType:  CodeUnsupportedMediaType,
+	http.StatusUnprocessableEntity:   CodeValidationFailed,
+	StatusChecksumMismatch:           CodeChecksumMismatch,
+	http.StatusInternalServerError:   CodeInternal,
+	http.StatusGatewayTimeout:        CodeRequestTimeout,
+	StatusClientClosedRequest:        CodeRequestCancelled,
+}
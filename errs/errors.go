@@ -1,42 +1,132 @@
 package errs
 
-import "net/http"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
 
 type AppError struct {
 	Status  int
+	Code    Code
 	Message string
-	Code    int
+	// Fields holds the messages of each offending request field
+	Fields map[string][]string
+	// Params fill the "{name}" placeholders of the code's title in every language
+	Params map[string]string
+	// Cause is the error this one was translated from, kept for logs and errors.Is
+	Cause error
 }
 
 func (a AppError) Error() string {
+	if a.Cause != nil {
+		return a.Message + ": " + a.Cause.Error()
+	}
 	return a.Message
 }
 
+func (a AppError) Unwrap() error {
+	return a.Cause
+}
+
+// WithField adds a message of one offending request field
+func (a AppError) WithField(field, message string) AppError {
+	fields := make(map[string][]string, len(a.Fields)+1)
+	for key, messages := range a.Fields {
+		fields[key] = messages
+	}
+	fields[field] = append(fields[field][:len(fields[field]):len(fields[field])], message)
+	a.Fields = fields
+	return a
+}
+
+// WithParam fills a placeholder of the code's title, the message becomes the title with
+// every param filled in
+func (a AppError) WithParam(name, value string) AppError {
+	params := make(map[string]string, len(a.Params)+1)
+	for key, param := range a.Params {
+		params[key] = param
+	}
+	params[name] = value
+	a.Params = params
+	a.Message = fill(Lookup(a.Code).Title, params)
+	return a
+}
+
+// Titled reports whether the message is the code's title rather than one of its own
+func (a AppError) Titled() bool {
+	return a.Message == fill(Lookup(a.Code).Title, a.Params)
+}
+
+func fill(message string, params map[string]string) string {
+	for name, value := range params {
+		message = strings.ReplaceAll(message, "{"+name+"}", value)
+	}
+	return message
+}
+
+// New returns the error of a catalog code with the code's title as message
+func New(code Code) AppError {
+	definition := Lookup(code)
+	return AppError{Status: definition.Status, Code: code, Message: definition.Title}
+}
+
+// Newf returns the error of a catalog code with its own message
+func Newf(code Code, format string, args ...interface{}) AppError {
+	appError := New(code)
+	appError.Message = fmt.Sprintf(format, args...)
+	return appError
+}
+
+// Wrap returns the error of a catalog code caused by err
+func Wrap(code Code, err error) AppError {
+	appError := New(code)
+	appError.Cause = err
+	return appError
+}
+
+// From returns the AppError err is or wraps. Cancelled requests and requests past
+// their deadline get their own codes, any other error is an internal error.
+func From(err error) AppError {
+	var appError AppError
+	switch {
+	case err == nil:
+		return New(CodeInternal)
+	case errors.As(err, &appError):
+		return appError
+	case errors.Is(err, context.DeadlineExceeded):
+		return Wrap(CodeRequestTimeout, err)
+	case errors.Is(err, context.Canceled):
+		return Wrap(CodeRequestCancelled, err)
+	}
+	return Wrap(CodeInternal, err)
+}
+
+// NewError returns an error with a bare HTTP status, its code is the generic one of
+// the status. Prefer New with a catalog code.
 func NewError(code int, errMsg string) error {
 	return AppError{
 		Status:  code,
+		Code:    statusCode(code),
 		Message: errMsg,
 	}
 }
 
 func ErrorBadRequest(errorMessage string) error {
-	return AppError{
-		Status:  http.StatusBadRequest,
-		Message: errorMessage,
-	}
+	return NewError(http.StatusBadRequest, errorMessage)
 }
 func ErrorUnprocessableEntity(errorMessage string) error {
-	return AppError{
-		Status:  http.StatusUnprocessableEntity,
-		Message: errorMessage,
-	}
+	return NewError(http.StatusUnprocessableEntity, errorMessage)
+}
+
+func ErrorUnsupportedMediaType(errorMessage string) error {
+	return NewError(http.StatusUnsupportedMediaType, errorMessage)
 }
 
 func ErrorInternalServerError(errorMessage string) error {
-	return AppError{
-		Status:  http.StatusInternalServerError,
-		Message: errorMessage,
-	}
+	return NewError(http.StatusInternalServerError, errorMessage)
 }
 
 func ErrorPanic(err error) {
@@ -46,22 +136,23 @@ func ErrorPanic(err error) {
 }
 
 func NewNotFoundError(message string) error {
-	return AppError{
-		Code:    http.StatusNotFound,
-		Message: message,
-	}
+	return NewError(http.StatusNotFound, message)
 }
 
 func NewUnexpectedError() error {
-	return AppError{
-		Code:    http.StatusInternalServerError,
-		Message: "unexpected error",
-	}
+	return NewError(http.StatusInternalServerError, "unexpected error")
 }
 
 func NewValidationError(message string) error {
-	return AppError{
-		Code:    http.StatusUnprocessableEntity,
-		Message: message,
+	return NewError(http.StatusUnprocessableEntity, message)
+}
+
+func statusCode(status int) Code {
+	if code, ok := codeForStatus[status]; ok {
+		return code
+	}
+	if status >= http.StatusInternalServerError {
+		return CodeInternal
 	}
+	return CodeBadRequest
 }
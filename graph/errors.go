@@ -0,0 +1,72 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+	"go_starter/errs"
+	"go_starter/i18n"
+	"go_starter/logs"
+	"go_starter/validation"
+)
+
+// queryError is an error of a resolver as the REST handlers answer it, the translated
+// title as message and the code, status and field messages as extensions
+type queryError struct {
+	message    string
+	extensions map[string]interface{}
+}
+
+func (q queryError) Error() string {
+	return q.message
+}
+
+func (q queryError) Extensions() map[string]interface{} {
+	return q.extensions
+}
+
+// newQueryError converts err like NewErrorResponses, errors outside the errs catalog are
+// logged and answered as internal errors without their message
+func newQueryError(ctx context.Context, err error) error {
+	if requestErr := ctx.Err(); requestErr != nil {
+		err = requestErr
+	}
+	state := requestState(ctx)
+	appError := errs.From(err)
+	if appError.Status >= http.StatusInternalServerError {
+		logs.Error(err, zap.String("request_id", state.requestID))
+	}
+	extensions := map[string]interface{}{
+		"code":   appError.Code,
+		"status": appError.Status,
+	}
+	// a message of its own is detail only English has
+	if !appError.Titled() {
+		extensions["detail"] = appError.Message
+	}
+	if len(appError.Fields) > 0 {
+		fields := make(map[string][]string, len(appError.Fields))
+		for field, messages := range appError.Fields {
+			for _, message := range messages {
+				fields[field] = append(fields[field], i18n.Translate(state.language, message, nil))
+			}
+		}
+		extensions["errors"] = fields
+	}
+	return queryError{
+		message:    i18n.Translate(state.language, string(appError.Code), appError.Params),
+		extensions: extensions,
+	}
+}
+
+// validate runs the rules of request like the REST handlers before calling a service
+func validate(ctx context.Context, request interface{}) error {
+	errValidate := validation.Validate(request)
+	if errValidate == nil {
+		return nil
+	}
+	appError := errs.New(errs.CodeValidationFailed)
+	appError.Fields = errValidate.Messages(requestState(ctx).language)
+	return newQueryError(ctx, appError)
+}
@@ -0,0 +1,73 @@
+package graph
+
+import (
+	"context"
+	"sync"
+)
+
+// loader batches the lookups of a relation within one request: every key queued or
+// asked for before a fetch is fetched with it, so a list and the relation of each of
+// its items take two queries instead of one per item. Results are kept for the rest of
+// the request.
+type loader[V any] struct {
+	fetch func(ctx context.Context, keys []uint) (map[uint]V, error)
+	// fetched sees every batch before its waiters do, to queue the keys of the next level
+	fetched func(values map[uint]V)
+
+	mu      sync.Mutex
+	queued  []uint
+	results map[uint]*loaded[V]
+}
+
+type loaded[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+func newLoader[V any](fetch func(ctx context.Context, keys []uint) (map[uint]V, error)) *loader[V] {
+	return &loader[V]{fetch: fetch, results: map[uint]*loaded[V]{}}
+}
+
+// queue adds keys to the next fetch, a list resolver queues the keys of its items
+// before any of them is loaded
+func (l *loader[V]) queue(keys ...uint) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.queued = append(l.queued, keys...)
+}
+
+// load returns the value of key, fetching it with every key queued so far
+func (l *loader[V]) load(ctx context.Context, key uint) (V, error) {
+	l.mu.Lock()
+	if result, ok := l.results[key]; ok {
+		l.mu.Unlock()
+		<-result.done
+		return result.value, result.err
+	}
+	batch := map[uint]*loaded[V]{}
+	for _, queued := range append(l.queued, key) {
+		if _, ok := l.results[queued]; !ok {
+			result := &loaded[V]{done: make(chan struct{})}
+			l.results[queued] = result
+			batch[queued] = result
+		}
+	}
+	l.queued = nil
+	l.mu.Unlock()
+
+	keys := make([]uint, 0, len(batch))
+	for batchKey := range batch {
+		keys = append(keys, batchKey)
+	}
+	values, err := l.fetch(ctx, keys)
+	if err == nil && l.fetched != nil {
+		l.fetched(values)
+	}
+	for batchKey, result := range batch {
+		result.value, result.err = values[batchKey], err
+		close(result.done)
+	}
+	result := batch[key]
+	return result.value, result.err
+}
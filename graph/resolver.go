@@ -0,0 +1,226 @@
+package graph
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/graph-gophers/graphql-go"
+	"go_starter/errs"
+	"go_starter/i18n"
+	"go_starter/requests"
+	"go_starter/services"
+)
+
+// resolver resolves the queries and mutations of the schema, each delegating to the
+// service of the REST handler doing the same
+type resolver struct {
+	serviceStudent services.StudentService
+	serviceUser    services.UserService
+}
+
+type studentInput struct {
+	StudentID *string
+	Firstname *string
+	Lastname  *string
+	Phone     string
+	Email     *string
+	Password  *string
+	Birthday  *string
+	Gender    *string
+	Status    *int32
+}
+
+func (i studentInput) request(studentID string) requests.StudentRequest {
+	request := requests.StudentRequest{
+		StudentID: value(i.StudentID),
+		Firstname: value(i.Firstname),
+		Lastname:  value(i.Lastname),
+		Phone:     i.Phone,
+		Email:     value(i.Email),
+		Password:  value(i.Password),
+		Birthday:  value(i.Birthday),
+		Gender:    value(i.Gender),
+	}
+	if i.Status != nil {
+		request.Status = int(*i.Status)
+	}
+	if studentID != "" {
+		request.StudentID = studentID
+	}
+	return request
+}
+
+type userInput struct {
+	Email string
+	Name  *string
+}
+
+func (r *resolver) Students(ctx context.Context) ([]*student, error) {
+	students, err := r.serviceStudent.GetStudentService(ctx)
+	if err != nil {
+		return nil, newQueryError(ctx, err)
+	}
+	return newStudents(ctx, students...), nil
+}
+
+func (r *resolver) Student(ctx context.Context, args struct{ StudentID string }) (*student, error) {
+	request := requests.StudentIdRequest{StudentID: args.StudentID}
+	if err := validate(ctx, request); err != nil {
+		return nil, err
+	}
+	response, err := r.serviceStudent.GetStudentByStudentIdServiceV2(ctx, request)
+	if err != nil {
+		return nil, newQueryError(ctx, err)
+	}
+	return newStudents(ctx, *response)[0], nil
+}
+
+func (r *resolver) Classrooms(ctx context.Context) ([]*classroom, error) {
+	classrooms, err := r.serviceStudent.GetClassroomsService(ctx)
+	if err != nil {
+		return nil, newQueryError(ctx, err)
+	}
+	return newClassrooms(ctx, classrooms...), nil
+}
+
+func (r *resolver) Classroom(ctx context.Context, args struct{ ID graphql.ID }) (*classroom, error) {
+	id, err := parseID(ctx, args.ID)
+	if err != nil {
+		return nil, err
+	}
+	response, err := r.serviceStudent.GetClassroomByIDService(ctx, id)
+	if err != nil {
+		return nil, newQueryError(ctx, err)
+	}
+	return newClassrooms(ctx, *response)[0], nil
+}
+
+func (r *resolver) Teacher(ctx context.Context, args struct{ ID graphql.ID }) (*teacher, error) {
+	id, err := parseID(ctx, args.ID)
+	if err != nil {
+		return nil, err
+	}
+	response, err := r.serviceStudent.GetTeacherByIDService(ctx, id)
+	if err != nil {
+		return nil, newQueryError(ctx, err)
+	}
+	return newTeacher(*response), nil
+}
+
+func (r *resolver) Users(ctx context.Context) ([]*user, error) {
+	responses, err := r.serviceUser.GetAllUserService(ctx)
+	if err != nil {
+		return nil, newQueryError(ctx, err)
+	}
+	list := make([]*user, 0, len(responses))
+	for _, response := range responses {
+		list = append(list, newUser(response))
+	}
+	return list, nil
+}
+
+func (r *resolver) User(ctx context.Context, args struct{ ID graphql.ID }) (*user, error) {
+	id, err := parseID(ctx, args.ID)
+	if err != nil {
+		return nil, err
+	}
+	response, err := r.serviceUser.GetByIdUserService(ctx, id)
+	if err != nil {
+		return nil, newQueryError(ctx, err)
+	}
+	return newUser(*response), nil
+}
+
+func (r *resolver) CreateStudent(ctx context.Context, args struct{ Input studentInput }) (*message, error) {
+	request := args.Input.request("")
+	if err := validate(ctx, request); err != nil {
+		return nil, err
+	}
+	response, err := r.serviceStudent.CreateStudentService(ctx, request)
+	if err != nil {
+		return nil, newQueryError(ctx, err)
+	}
+	return newMessage(ctx, response.Message), nil
+}
+
+func (r *resolver) UpdateStudent(ctx context.Context, args struct {
+	StudentID string
+	Input     studentInput
+}) (*message, error) {
+	request := args.Input.request(args.StudentID)
+	if err := validate(ctx, request); err != nil {
+		return nil, err
+	}
+	response, err := r.serviceStudent.UpdateStudentService(ctx, request)
+	if err != nil {
+		return nil, newQueryError(ctx, err)
+	}
+	return newMessage(ctx, response.Message), nil
+}
+
+func (r *resolver) DeleteStudent(ctx context.Context, args struct{ StudentID string }) (*message, error) {
+	request := requests.StudentIdRequest{StudentID: args.StudentID}
+	if err := validate(ctx, request); err != nil {
+		return nil, err
+	}
+	response, err := r.serviceStudent.DeleteStudentByIDService(ctx, request)
+	if err != nil {
+		return nil, newQueryError(ctx, err)
+	}
+	return newMessage(ctx, response.Message), nil
+}
+
+func (r *resolver) UpdateUser(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input userInput
+}) (*message, error) {
+	id, err := parseID(ctx, args.ID)
+	if err != nil {
+		return nil, err
+	}
+	request := requests.UpdateUserRequest{ID: id, Email: args.Input.Email, Name: value(args.Input.Name)}
+	if err = validate(ctx, request); err != nil {
+		return nil, err
+	}
+	response, err := r.serviceUser.UpdateUserService(ctx, request)
+	if err != nil {
+		return nil, newQueryError(ctx, err)
+	}
+	return newMessage(ctx, response.Message), nil
+}
+
+func (r *resolver) DeleteUser(ctx context.Context, args struct{ ID graphql.ID }) (*message, error) {
+	id, err := parseID(ctx, args.ID)
+	if err != nil {
+		return nil, err
+	}
+	request := requests.DeleteUserRequest{ID: id}
+	if err = validate(ctx, request); err != nil {
+		return nil, err
+	}
+	response, err := r.serviceUser.DeleteUserService(ctx, request)
+	if err != nil {
+		return nil, newQueryError(ctx, err)
+	}
+	return newMessage(ctx, response.Message), nil
+}
+
+// newMessage translates message when it is a key of the i18n catalog, like NewSuccessMsg
+func newMessage(ctx context.Context, text string) *message {
+	return &message{Message: i18n.Translate(requestState(ctx).language, text, nil)}
+}
+
+func parseID(ctx context.Context, id graphql.ID) (uint, error) {
+	parsed, err := strconv.ParseUint(string(id), 10, 64)
+	if err != nil || parsed == 0 {
+		return 0, newQueryError(ctx, errs.New(errs.CodeValidationFailed).WithField("id", "validation.invalid"))
+	}
+	return uint(parsed), nil
+}
+
+func value(pointer *string) string {
+	if pointer == nil {
+		return ""
+	}
+	return *pointer
+}
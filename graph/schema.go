@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"context"
+	_ "embed"
+	"strconv"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/pkg/errors"
+	"go_starter/config"
+	"go_starter/logs"
+	"go_starter/requests"
+	"go_starter/responses"
+	"go_starter/services"
+)
+
+//go:embed schema.graphql
+var schemaDefinition string
+
+// Schema answers GraphQL queries over the students, classrooms, teachers and users
+// with the services of the REST handlers
+type Schema struct {
+	schema         *graphql.Schema
+	serviceStudent services.StudentService
+}
+
+// state is what the resolvers of one request share
+type state struct {
+	language  string
+	requestID string
+	// students by classroom id and classrooms by student id
+	students   *loader[[]responses.StudentResponse]
+	classrooms *loader[[]responses.ClassroomResponse]
+}
+
+type stateKey struct{}
+
+func requestState(ctx context.Context) *state {
+	return ctx.Value(stateKey{}).(*state)
+}
+
+// NewSchema parses the schema, graphql.max_depth bounds how deep a query nests
+func NewSchema(serviceStudent services.StudentService, serviceUser services.UserService) (*Schema, error) {
+	root := &resolver{serviceStudent: serviceStudent, serviceUser: serviceUser}
+	schema, err := graphql.ParseSchema(schemaDefinition, root,
+		graphql.UseFieldResolvers(),
+		graphql.MaxDepth(configInt("graphql.max_depth", 8)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{schema: schema, serviceStudent: serviceStudent}, nil
+}
+
+// Exec answers request, its messages and errors in language
+func (s *Schema) Exec(ctx context.Context, language, requestID string, request requests.GraphQLRequest) *graphql.Response {
+	requestState := &state{
+		language:   language,
+		requestID:  requestID,
+		students:   newLoader(s.serviceStudent.GetStudentsByClassroomIDsService),
+		classrooms: newLoader(s.serviceStudent.GetClassroomsByStudentIDsService),
+	}
+	// the items of a relation are resolved concurrently, so the keys of the next level are
+	// queued from the whole batch rather than item by item
+	requestState.students.fetched = func(values map[uint][]responses.StudentResponse) {
+		for _, students := range values {
+			for _, student := range students {
+				requestState.classrooms.queue(student.ID)
+			}
+		}
+	}
+	requestState.classrooms.fetched = func(values map[uint][]responses.ClassroomResponse) {
+		for _, classrooms := range values {
+			for _, classroom := range classrooms {
+				requestState.students.queue(classroom.ID)
+			}
+		}
+	}
+	ctx = context.WithValue(ctx, stateKey{}, requestState)
+	return s.schema.Exec(ctx, request.Query, request.OperationName, request.Variables)
+}
+
+func configInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(config.GetEnv(key, strconv.Itoa(defaultValue)))
+	if err != nil || value <= 0 {
+		logs.Error(errors.Errorf("invalid %s", key))
+		return defaultValue
+	}
+	return value
+}
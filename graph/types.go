@@ -0,0 +1,183 @@
+package graph
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/graph-gophers/graphql-go"
+	"go_starter/responses"
+)
+
+// The types of the schema resolve their fields from the struct fields of the same
+// name, a relation from the method of the same name
+
+type student struct {
+	ID            graphql.ID
+	StudentID     string
+	Firstname     string
+	Lastname      string
+	Phone         string
+	Email         string
+	Birthday      string
+	Gender        string
+	Status        int32
+	ImageURL      *string
+	ImageVariants []*imageVariant
+	CreatedAt     string
+	UpdatedAt     string
+
+	// key is the id the loaders are keyed by
+	key uint
+}
+
+// newStudents queues the students for the classrooms loader, so that the classrooms of
+// all of them are fetched at once
+func newStudents(ctx context.Context, data ...responses.StudentResponse) []*student {
+	students := make([]*student, 0, len(data))
+	ids := make([]uint, 0, len(data))
+	for _, response := range data {
+		students = append(students, &student{
+			ID:            graphID(response.ID),
+			StudentID:     response.StudentID,
+			Firstname:     response.Firstname,
+			Lastname:      response.Lastname,
+			Phone:         response.Phone,
+			Email:         response.Email,
+			Birthday:      response.Birthday,
+			Gender:        response.Gender,
+			Status:        int32(response.Status),
+			ImageURL:      optional(response.ImageURL),
+			ImageVariants: imageVariants(response.ImageVariants),
+			CreatedAt:     response.CreatedAt,
+			UpdatedAt:     response.UpdatedAt,
+			key:           response.ID,
+		})
+		ids = append(ids, response.ID)
+	}
+	requestState(ctx).classrooms.queue(ids...)
+	return students
+}
+
+func (s *student) Classrooms(ctx context.Context) ([]*classroom, error) {
+	classrooms, err := requestState(ctx).classrooms.load(ctx, s.key)
+	if err != nil {
+		return nil, newQueryError(ctx, err)
+	}
+	return newClassrooms(ctx, classrooms...), nil
+}
+
+type classroom struct {
+	ID          graphql.ID
+	ClassName   string
+	ClassYear   int32
+	SubjectName string
+	Credits     int32
+
+	// key is the id the loaders are keyed by
+	key uint
+}
+
+// newClassrooms queues the classrooms for the students loader, so that the students of
+// all of them are fetched at once
+func newClassrooms(ctx context.Context, data ...responses.ClassroomResponse) []*classroom {
+	classrooms := make([]*classroom, 0, len(data))
+	ids := make([]uint, 0, len(data))
+	for _, response := range data {
+		classrooms = append(classrooms, &classroom{
+			ID:          graphID(response.ID),
+			ClassName:   response.ClassName,
+			ClassYear:   int32(response.ClassYear),
+			SubjectName: response.SubjectName,
+			Credits:     int32(response.Credits),
+			key:         response.ID,
+		})
+		ids = append(ids, response.ID)
+	}
+	requestState(ctx).students.queue(ids...)
+	return classrooms
+}
+
+func (c *classroom) Students(ctx context.Context) ([]*student, error) {
+	students, err := requestState(ctx).students.load(ctx, c.key)
+	if err != nil {
+		return nil, newQueryError(ctx, err)
+	}
+	return newStudents(ctx, students...), nil
+}
+
+type teacher struct {
+	ID            graphql.ID
+	Phone         string
+	Firstname     string
+	Lastname      string
+	ImageURL      *string
+	ImageVariants []*imageVariant
+	CreatedAt     string
+	UpdatedAt     string
+}
+
+func newTeacher(response responses.TeacherResponse) *teacher {
+	return &teacher{
+		ID:            graphID(response.ID),
+		Phone:         response.Phone,
+		Firstname:     response.Firstname,
+		Lastname:      response.Lastname,
+		ImageURL:      optional(response.ImageURL),
+		ImageVariants: imageVariants(response.ImageVariants),
+		CreatedAt:     response.CreatedAt,
+		UpdatedAt:     response.UpdatedAt,
+	}
+}
+
+type user struct {
+	ID            graphql.ID
+	Name          string
+	Email         string
+	ImageURL      *string
+	ImageVariants []*imageVariant
+	CreatedAt     string
+	UpdatedAt     string
+}
+
+func newUser(response responses.UserResponse) *user {
+	return &user{
+		ID:            graphID(response.ID),
+		Name:          response.Name,
+		Email:         response.Email,
+		ImageURL:      optional(response.ImageURL),
+		ImageVariants: imageVariants(response.ImageVariants),
+		CreatedAt:     response.CreatedAt,
+		UpdatedAt:     response.UpdatedAt,
+	}
+}
+
+type imageVariant struct {
+	Name string
+	URL  string
+}
+
+type message struct {
+	Message string
+}
+
+// imageVariants lists variants by name, a map has no order of its own
+func imageVariants(variants map[string]string) []*imageVariant {
+	list := make([]*imageVariant, 0, len(variants))
+	for name, url := range variants {
+		list = append(list, &imageVariant{Name: name, URL: url})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+func graphID(value uint) graphql.ID {
+	return graphql.ID(strconv.FormatUint(uint64(value), 10))
+}
+
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,93 @@
+package i18n
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	English = "en"
+	Lao     = "lo"
+	Thai    = "th"
+)
+
+// DefaultLanguage answers clients that ask for none of Languages
+const DefaultLanguage = English
+
+// Languages are the languages of the catalog, the default first so that a request
+// without Accept-Language negotiates it
+var Languages = []string{English, Lao, Thai}
+
+// Translations holds a message in each language, "{name}" is a placeholder filled
+// from the params of Translate
+type Translations map[string]string
+
+// Supported reports whether the catalog is translated into language
+func Supported(language string) bool {
+	for _, supported := range Languages {
+		if supported == language {
+			return true
+		}
+	}
+	return false
+}
+
+// Has reports whether key is in the catalog
+func Has(key string) bool {
+	_, ok := catalog[key]
+	return ok
+}
+
+// Translate returns the message of key in language, falling back to English. A key
+// that is not in the catalog is returned as is, so plain messages pass through.
+func Translate(language, key string, params map[string]string) string {
+	translations, ok := catalog[key]
+	if !ok {
+		return key
+	}
+	message, ok := translations[language]
+	if !ok || message == "" {
+		message = translations[DefaultLanguage]
+	}
+	for name, value := range params {
+		message = strings.ReplaceAll(message, "{"+name+"}", value)
+	}
+	return message
+}
+
+var placeholder = regexp.MustCompile(`\{[a-z_]+\}`)
+
+// Check verifies that every key, including the required ones, is translated into
+// every language with the placeholders of its English message
+func Check(required ...string) error {
+	var problems []string
+	for _, key := range required {
+		if !Has(key) {
+			problems = append(problems, fmt.Sprintf("%s: missing", key))
+		}
+	}
+	for key, translations := range catalog {
+		english := placeholders(translations[English])
+		for _, language := range Languages {
+			message := translations[language]
+			if message == "" {
+				problems = append(problems, fmt.Sprintf("%s: no %s translation", key, language))
+			} else if placeholders(message) != english {
+				problems = append(problems, fmt.Sprintf("%s: %s placeholders differ from English", key, language))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("incomplete translations:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+func placeholders(message string) string {
+	found := placeholder.FindAllString(message, -1)
+	sort.Strings(found)
+	return strings.Join(found, ",")
+}
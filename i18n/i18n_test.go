@@ -0,0 +1,39 @@
+package i18n
+
+import (
+	"go_starter/errs"
+	"testing"
+)
+
+// TestCatalogTranslated runs the check main runs at startup, every error code must be
+// translated into every language
+func TestCatalogTranslated(t *testing.T) {
+	var codes []string
+	for _, definition := range errs.Catalog() {
+		codes = append(codes, string(definition.Code))
+	}
+	if err := Check(codes...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestCatalogPlaceholders requires the title of a code and its translations to use the
+// same placeholders, the params of an error fill both
+func TestCatalogPlaceholders(t *testing.T) {
+	for _, definition := range errs.Catalog() {
+		title := placeholders(definition.Title)
+		if english := placeholders(catalog[string(definition.Code)][English]); english != title {
+			t.Errorf("%s: title placeholders %q, translation placeholders %q", definition.Code, title, english)
+		}
+	}
+}
+
+func TestTranslateParams(t *testing.T) {
+	appError := errs.New(errs.CodeStudentNotInClassroom).WithParam("student_id", "7").WithParam("classroom_id", "3")
+	if appError.Message != "student 7 is not enrolled in classroom 3" || !appError.Titled() {
+		t.Fatalf("message = %q, titled %v", appError.Message, appError.Titled())
+	}
+	if message := Translate(Lao, string(appError.Code), appError.Params); message != "ນັກສຶກສາ 7 ບໍ່ໄດ້ລົງທະບຽນໃນຫ້ອງຮຽນ 3" {
+		t.Fatalf("lao message = %q", message)
+	}
+}
@@ -0,0 +1,137 @@
+package i18n
+
+import "go_starter/errs"
+
+// Keys of the success messages services answer with
+const (
+	MessageSuccess  = "message.success"
+	MessageUploaded = "message.uploaded"
+	MessageDeleted  = "message.deleted"
+
+	// verdicts of a transcript verification
+	MessageTranscriptAuthentic        = "message.transcript_authentic"
+	MessageTranscriptSignatureInvalid = "message.transcript_signature_invalid"
+	MessageTranscriptIssued           = "message.transcript_issued"
+	MessageTranscriptNotIssued        = "message.transcript_not_issued"
+	MessageTranscriptNotMatching      = "message.transcript_not_matching"
+)
+
+// catalog holds every translated message. Errors are keyed by their errs code,
+// validation rules by "validation.<tag>" and success messages by "message.<name>".
+var catalog = map[string]Translations{
+	// success messages
+	MessageSuccess:  {English: "success", Lao: "ສຳເລັດ", Thai: "สำเร็จ"},
+	MessageUploaded: {English: "uploaded success", Lao: "ອັບໂຫຼດສຳເລັດ", Thai: "อัปโหลดสำเร็จ"},
+	MessageDeleted:  {English: "deleted success", Lao: "ລຶບສຳເລັດ", Thai: "ลบสำเร็จ"},
+
+	// transcript verdicts
+	MessageTranscriptAuthentic:        {English: "transcript is authentic", Lao: "ໃບຄະແນນນີ້ເປັນຂອງແທ້", Thai: "ใบแสดงผลการเรียนนี้เป็นของจริง"},
+	MessageTranscriptSignatureInvalid: {English: "transcript signature is invalid", Lao: "ລາຍເຊັນຂອງໃບຄະແນນບໍ່ຖືກຕ້ອງ", Thai: "ลายเซ็นของใบแสดงผลการเรียนไม่ถูกต้อง"},
+	MessageTranscriptIssued:           {English: "a transcript was issued with this serial number, upload it to check it was not altered", Lao: "ມີໃບຄະແນນທີ່ອອກດ້ວຍເລກລຳດັບນີ້, ອັບໂຫຼດມັນເພື່ອກວດສອບວ່າບໍ່ຖືກແກ້ໄຂ", Thai: "มีใบแสดงผลการเรียนที่ออกด้วยเลขลำดับนี้ อัปโหลดเพื่อตรวจสอบว่าไม่ถูกแก้ไข"},
+	MessageTranscriptNotIssued:        {English: "no transcript was issued with this serial number", Lao: "ບໍ່ມີໃບຄະແນນທີ່ອອກດ້ວຍເລກລຳດັບນີ້", Thai: "ไม่มีใบแสดงผลการเรียนที่ออกด้วยเลขลำดับนี้"},
+	MessageTranscriptNotMatching:      {English: "document does not match any issued transcript; it may have been altered", Lao: "ເອກະສານບໍ່ກົງກັບໃບຄະແນນໃດທີ່ອອກໃຫ້, ມັນອາດຈະຖືກແກ້ໄຂ", Thai: "เอกสารไม่ตรงกับใบแสดงผลการเรียนที่ออกให้ใด ๆ อาจถูกแก้ไข"},
+
+	// generic errors
+	string(errs.CodeBadRequest):           {English: "bad request", Lao: "ຄຳຮ້ອງຂໍບໍ່ຖືກຕ້ອງ", Thai: "คำขอไม่ถูกต้อง"},
+	string(errs.CodeValidationFailed):     {English: "validation failed", Lao: "ຂໍ້ມູນບໍ່ຖືກຕ້ອງ", Thai: "ข้อมูลไม่ถูกต้อง"},
+	string(errs.CodeUnauthorized):         {English: "unauthorized", Lao: "ບໍ່ໄດ້ຮັບອະນຸຍາດ, ກະລຸນາເຂົ້າສູ່ລະບົບ", Thai: "ไม่ได้รับอนุญาต กรุณาเข้าสู่ระบบ"},
+	string(errs.CodeForbidden):            {English: "forbidden", Lao: "ບໍ່ມີສິດເຂົ້າເຖິງ", Thai: "ไม่มีสิทธิ์เข้าถึง"},
+	string(errs.CodeNotFound):             {English: "not found", Lao: "ບໍ່ພົບຂໍ້ມູນ", Thai: "ไม่พบข้อมูล"},
+	string(errs.CodeConflict):             {English: "conflict", Lao: "ຂໍ້ມູນຂັດແຍ່ງກັນ", Thai: "ข้อมูลขัดแย้งกัน"},
+	string(errs.CodeAlreadyExists):        {English: "record already exists", Lao: "ມີຂໍ້ມູນນີ້ຢູ່ແລ້ວ", Thai: "มีข้อมูลนี้อยู่แล้ว"},
+	string(errs.CodeReferenceViolated):    {English: "record is referenced by or references a missing record", Lao: "ຂໍ້ມູນນີ້ຖືກອ້າງອີງຢູ່ ຫຼື ອ້າງອີງເຖິງຂໍ້ມູນທີ່ບໍ່ມີ", Thai: "ข้อมูลนี้ถูกอ้างอิงอยู่ หรืออ้างอิงถึงข้อมูลที่ไม่มีอยู่"},
+	string(errs.CodeGone):                 {English: "gone", Lao: "ໝົດອາຍຸແລ້ວ", Thai: "หมดอายุแล้ว"},
+	string(errs.CodePayloadTooLarge):      {English: "payload too large", Lao: "ຂໍ້ມູນໃຫຍ່ເກີນໄປ", Thai: "ข้อมูลมีขนาดใหญ่เกินไป"},
+	string(errs.CodeUnsupportedMediaType): {English: "unsupported media type", Lao: "ບໍ່ຮອງຮັບປະເພດໄຟລ໌ນີ້", Thai: "ไม่รองรับประเภทไฟล์นี้"},
+	string(errs.CodeChecksumMismatch):     {English: "checksum mismatch", Lao: "ຄ່າກວດສອບໄຟລ໌ບໍ່ກົງກັນ", Thai: "ค่าตรวจสอบไฟล์ไม่ตรงกัน"},
+	string(errs.CodeInternal):             {English: "internal server error", Lao: "ເກີດຂໍ້ຜິດພາດໃນລະບົບ", Thai: "เกิดข้อผิดพลาดภายในระบบ"},
+	string(errs.CodeRequestTimeout):       {English: "request timed out", Lao: "ໝົດເວລາດຳເນີນການ", Thai: "หมดเวลาดำเนินการ"},
+	string(errs.CodeRequestCancelled):     {English: "request cancelled", Lao: "ຄຳຮ້ອງຂໍຖືກຍົກເລີກ", Thai: "คำขอถูกยกเลิก"},
+
+	// domain errors
+	string(errs.CodeInvalidCredentials):       {English: "phone, email or password is incorrect", Lao: "ເບີໂທ, ອີເມວ ຫຼື ລະຫັດຜ່ານບໍ່ຖືກຕ້ອງ", Thai: "เบอร์โทร อีเมล หรือรหัสผ่านไม่ถูกต้อง"},
+	string(errs.CodeInvalidUserType):          {English: "invalid user type", Lao: "ປະເພດຜູ້ໃຊ້ບໍ່ຖືກຕ້ອງ", Thai: "ประเภทผู้ใช้ไม่ถูกต้อง"},
+	string(errs.CodeInvalidDate):              {English: "invalid date, use DD-MM-YYYY", Lao: "ວັນທີບໍ່ຖືກຕ້ອງ, ໃຊ້ຮູບແບບ DD-MM-YYYY", Thai: "วันที่ไม่ถูกต้อง ใช้รูปแบบ DD-MM-YYYY"},
+	string(errs.CodeAccountCreated):           {English: "account created, log in again", Lao: "ສ້າງບັນຊີແລ້ວ, ກະລຸນາເຂົ້າສູ່ລະບົບອີກຄັ້ງ", Thai: "สร้างบัญชีแล้ว กรุณาเข้าสู่ระบบอีกครั้ง"},
+	string(errs.CodeStudentNotFound):          {English: "student not found", Lao: "ບໍ່ພົບນັກສຶກສາ", Thai: "ไม่พบนักศึกษา"},
+	string(errs.CodeTeacherNotFound):          {English: "teacher not found", Lao: "ບໍ່ພົບອາຈານ", Thai: "ไม่พบอาจารย์"},
+	string(errs.CodeUserNotFound):             {English: "user not found", Lao: "ບໍ່ພົບຜູ້ໃຊ້", Thai: "ไม่พบผู้ใช้"},
+	string(errs.CodeClassroomNotFound):        {English: "classroom not found", Lao: "ບໍ່ພົບຫ້ອງຮຽນ", Thai: "ไม่พบห้องเรียน"},
+	string(errs.CodeClassroomSessionNotFound): {English: "classroom session not found", Lao: "ບໍ່ພົບຊົ່ວໂມງຮຽນ", Thai: "ไม่พบคาบเรียน"},
+	string(errs.CodeTermNotFound):             {English: "term not found", Lao: "ບໍ່ພົບພາກຮຽນ", Thai: "ไม่พบภาคเรียน"},
+	string(errs.CodeGradingScaleNotFound):     {English: "grading scale not found", Lao: "ບໍ່ພົບເກນການໃຫ້ຄະແນນ", Thai: "ไม่พบเกณฑ์การให้เกรด"},
+	string(errs.CodeAssessmentNotFound):       {English: "assessment not found", Lao: "ບໍ່ພົບການປະເມີນຜົນ", Thai: "ไม่พบการประเมินผล"},
+	string(errs.CodeTranscriptNotFound):       {English: "transcript not found", Lao: "ບໍ່ພົບໃບຄະແນນ", Thai: "ไม่พบใบแสดงผลการเรียน"},
+	string(errs.CodeDocumentNotFound):         {English: "document not found", Lao: "ບໍ່ພົບເອກະສານ", Thai: "ไม่พบเอกสาร"},
+	string(errs.CodeUploadNotFound):           {English: "upload not found", Lao: "ບໍ່ພົບການອັບໂຫຼດ", Thai: "ไม่พบการอัปโหลด"},
+	string(errs.CodeFileNotFound):             {English: "file not found", Lao: "ບໍ່ພົບໄຟລ໌", Thai: "ไม่พบไฟล์"},
+	string(errs.CodePhoneInUse):               {English: "phone number already in use", Lao: "ເບີໂທລະສັບນີ້ຖືກໃຊ້ແລ້ວ", Thai: "เบอร์โทรศัพท์นี้ถูกใช้แล้ว"},
+	string(errs.CodeEmailInUse):               {English: "email already in use", Lao: "ອີເມວນີ້ຖືກໃຊ້ແລ້ວ", Thai: "อีเมลนี้ถูกใช้แล้ว"},
+	string(errs.CodeStudentIDInUse):           {English: "student ID already in use", Lao: "ລະຫັດນັກສຶກສານີ້ຖືກໃຊ້ແລ້ວ", Thai: "รหัสนักศึกษานี้ถูกใช้แล้ว"},
+	string(errs.CodeNameInUse):                {English: "name already in use", Lao: "ຊື່ນີ້ຖືກໃຊ້ແລ້ວ", Thai: "ชื่อนี้ถูกใช้แล้ว"},
+	string(errs.CodeNotEnrolled):              {English: "student is not enrolled in this classroom", Lao: "ນັກສຶກສາບໍ່ໄດ້ລົງທະບຽນໃນຫ້ອງຮຽນນີ້", Thai: "นักศึกษาไม่ได้ลงทะเบียนในห้องเรียนนี้"},
+	string(errs.CodeAlreadyCheckedIn):         {English: "already checked in to this session", Lao: "ໄດ້ລົງຊື່ເຂົ້າຮຽນຊົ່ວໂມງນີ້ແລ້ວ", Thai: "เช็กชื่อเข้าคาบเรียนนี้แล้ว"},
+	string(errs.CodeCheckInCodeUsed):          {English: "check-in code already used, scan the current code", Lao: "ລະຫັດລົງຊື່ນີ້ຖືກໃຊ້ແລ້ວ, ກະລຸນາສະແກນລະຫັດປັດຈຸບັນ", Thai: "รหัสเช็กชื่อนี้ถูกใช้แล้ว กรุณาสแกนรหัสปัจจุบัน"},
+	string(errs.CodeTermFinalized):            {English: "grades for this term are finalized", Lao: "ຄະແນນຂອງພາກຮຽນນີ້ຖືກສະຫຼຸບແລ້ວ", Thai: "เกรดของภาคเรียนนี้สรุปแล้ว"},
+	string(errs.CodeVirusDetected):            {English: "document failed the virus scan", Lao: "ເອກະສານບໍ່ຜ່ານການກວດໄວຣັສ", Thai: "เอกสารไม่ผ่านการตรวจไวรัส"},
+	string(errs.CodeInvalidAccessToken):       {English: "invalid access token", Lao: "ໂທເຄັນເຂົ້າໃຊ້ບໍ່ຖືກຕ້ອງ", Thai: "โทเค็นเข้าใช้งานไม่ถูกต้อง"},
+	string(errs.CodeCredentialsRequired):      {English: "signed url or access token required", Lao: "ຕ້ອງມີລິ້ງທີ່ລົງລາຍເຊັນ ຫຼື ໂທເຄັນເຂົ້າໃຊ້", Thai: "ต้องใช้ลิงก์ที่ลงลายมือชื่อหรือโทเค็นเข้าใช้งาน"},
+	string(errs.CodeInvalidSignedURL):         {English: "invalid signed url", Lao: "ລິ້ງທີ່ລົງລາຍເຊັນບໍ່ຖືກຕ້ອງ", Thai: "ลิงก์ที่ลงลายมือชื่อไม่ถูกต้อง"},
+	string(errs.CodeSignedURLExpired):         {English: "signed url has expired", Lao: "ລິ້ງທີ່ລົງລາຍເຊັນໝົດອາຍຸແລ້ວ", Thai: "ลิงก์ที่ลงลายมือชื่อหมดอายุแล้ว"},
+	string(errs.CodeNotClassroomTeacher):      {English: "only the teacher of this classroom can do this", Lao: "ສະເພາະອາຈານຂອງຫ້ອງຮຽນນີ້ເທົ່ານັ້ນທີ່ເຮັດໄດ້", Thai: "เฉพาะอาจารย์ของห้องเรียนนี้เท่านั้นที่ทำได้"},
+	string(errs.CodePhotoForbidden):           {English: "not allowed to view this photo", Lao: "ບໍ່ມີສິດເບິ່ງຮູບນີ້", Thai: "ไม่มีสิทธิ์ดูรูปนี้"},
+	string(errs.CodeInvalidImageWidth):        {English: "width must be positive", Lao: "ຄວາມກວ້າງຕ້ອງເປັນຄ່າບວກ", Thai: "ความกว้างต้องเป็นค่าบวก"},
+	string(errs.CodeImageVariantResize):       {English: "cannot resize an image variant", Lao: "ບໍ່ສາມາດປ່ຽນຂະໜາດຮູບທີ່ຖືກຍໍ້ແລ້ວ", Thai: "ไม่สามารถปรับขนาดรูปที่ย่อแล้วได้"},
+	string(errs.CodeInvalidDateRange):         {English: "end date must not be before start date", Lao: "ວັນທີສິ້ນສຸດຕ້ອງບໍ່ກ່ອນວັນທີເລີ່ມຕົ້ນ", Thai: "วันที่สิ้นสุดต้องไม่อยู่ก่อนวันที่เริ่มต้น"},
+	string(errs.CodeDuplicateGradeLetter):     {English: "letter {letter} appears more than once", Lao: "ເກຣດ {letter} ມີຫຼາຍກວ່າໜຶ່ງເທື່ອ", Thai: "เกรด {letter} ซ้ำกันมากกว่าหนึ่งครั้ง"},
+	string(errs.CodeGradingScaleIncomplete):   {English: "grading scale must have a grade starting at 0 percent", Lao: "ເກນການໃຫ້ຄະແນນຕ້ອງມີເກຣດທີ່ເລີ່ມຈາກ 0 ເປີເຊັນ", Thai: "เกณฑ์การให้เกรดต้องมีเกรดที่เริ่มจาก 0 เปอร์เซ็นต์"},
+	string(errs.CodeStudentNotInClassroom):    {English: "student {student_id} is not enrolled in classroom {classroom_id}", Lao: "ນັກສຶກສາ {student_id} ບໍ່ໄດ້ລົງທະບຽນໃນຫ້ອງຮຽນ {classroom_id}", Thai: "นักศึกษา {student_id} ไม่ได้ลงทะเบียนในห้องเรียน {classroom_id}"},
+	string(errs.CodeStudentListedTwice):       {English: "student {student_id} is listed more than once", Lao: "ນັກສຶກສາ {student_id} ມີຫຼາຍກວ່າໜຶ່ງເທື່ອ", Thai: "นักศึกษา {student_id} มีมากกว่าหนึ่งครั้ง"},
+	string(errs.CodeScoreAboveMax):            {English: "score for student {student_id} exceeds max score {max_score}", Lao: "ຄະແນນຂອງນັກສຶກສາ {student_id} ເກີນຄະແນນເຕັມ {max_score}", Thai: "คะแนนของนักศึกษา {student_id} เกินคะแนนเต็ม {max_score}"},
+	string(errs.CodeNoAttendanceRecords):      {English: "no attendance records to mark", Lao: "ບໍ່ມີລາຍການເຂົ້າຮຽນໃຫ້ບັນທຶກ", Thai: "ไม่มีรายการเข้าเรียนให้บันทึก"},
+	string(errs.CodeInvalidCheckInCode):       {English: "invalid check-in code", Lao: "ລະຫັດລົງຊື່ບໍ່ຖືກຕ້ອງ", Thai: "รหัสเช็กชื่อไม่ถูกต้อง"},
+	string(errs.CodeCheckInCodeExpired):       {English: "check-in code has expired, scan the current code", Lao: "ລະຫັດລົງຊື່ໝົດອາຍຸແລ້ວ, ກະລຸນາສະແກນລະຫັດປັດຈຸບັນ", Thai: "รหัสเช็กชื่อหมดอายุแล้ว กรุณาสแกนรหัสปัจจุบัน"},
+	string(errs.CodeUnknownDocumentType):      {English: "unknown document type", Lao: "ບໍ່ຮູ້ຈັກປະເພດເອກະສານນີ້", Thai: "ไม่รู้จักประเภทเอกสารนี้"},
+	string(errs.CodeEmptyDocument):            {English: "document file is empty", Lao: "ໄຟລ໌ເອກະສານຫວ່າງເປົ່າ", Thai: "ไฟล์เอกสารว่างเปล่า"},
+	string(errs.CodeDocumentTooLarge):         {English: "{document_type} must be at most {max_mb} MB", Lao: "{document_type} ຕ້ອງບໍ່ເກີນ {max_mb} MB", Thai: "{document_type} ต้องไม่เกิน {max_mb} MB"},
+	string(errs.CodeDocumentContentType):      {English: "{document_type} must be one of {content_types}", Lao: "{document_type} ຕ້ອງເປັນໜຶ່ງໃນ {content_types}", Thai: "{document_type} ต้องเป็นหนึ่งใน {content_types}"},
+	string(errs.CodeUploadTooLarge):           {English: "upload must be at most {max_mb} MB", Lao: "ໄຟລ໌ທີ່ອັບໂຫຼດຕ້ອງບໍ່ເກີນ {max_mb} MB", Thai: "ไฟล์ที่อัปโหลดต้องไม่เกิน {max_mb} MB"},
+	string(errs.CodeInvalidChecksum):          {English: "checksum must be a SHA-256 hex digest", Lao: "ຄ່າກວດສອບຕ້ອງເປັນ SHA-256 ແບບເລກຖານສິບຫົກ", Thai: "ค่าตรวจสอบต้องเป็น SHA-256 แบบเลขฐานสิบหก"},
+	string(errs.CodeUploadCompleted):          {English: "upload already completed", Lao: "ອັບໂຫຼດສຳເລັດແລ້ວ", Thai: "อัปโหลดเสร็จแล้ว"},
+	string(errs.CodeUploadNotCompleted):       {English: "upload is not completed", Lao: "ການອັບໂຫຼດຍັງບໍ່ສຳເລັດ", Thai: "การอัปโหลดยังไม่เสร็จ"},
+	string(errs.CodeUploadOffsetMismatch):     {English: "upload offset is {offset}", Lao: "ຕຳແໜ່ງການອັບໂຫຼດແມ່ນ {offset}", Thai: "ตำแหน่งการอัปโหลดคือ {offset}"},
+	string(errs.CodeUploadOffsetMoved):        {English: "upload offset has moved, request the current offset", Lao: "ຕຳແໜ່ງການອັບໂຫຼດປ່ຽນແລ້ວ, ກະລຸນາຂໍຕຳແໜ່ງປັດຈຸບັນ", Thai: "ตำแหน่งการอัปโหลดเปลี่ยนแล้ว กรุณาขอตำแหน่งปัจจุบัน"},
+	string(errs.CodeUploadExpired):            {English: "upload has expired", Lao: "ການອັບໂຫຼດໝົດອາຍຸແລ້ວ", Thai: "การอัปโหลดหมดอายุแล้ว"},
+	string(errs.CodeUploadChecksumMismatch):   {English: "file checksum mismatch, the upload was discarded", Lao: "ຄ່າກວດສອບໄຟລ໌ບໍ່ກົງກັນ, ການອັບໂຫຼດຖືກຍົກເລີກ", Thai: "ค่าตรวจสอบไฟล์ไม่ตรงกัน การอัปโหลดถูกยกเลิก"},
+	string(errs.CodeChunkTooLarge):            {English: "chunk exceeds the upload size", Lao: "ສ່ວນຂອງໄຟລ໌ເກີນຂະໜາດການອັບໂຫຼດ", Thai: "ส่วนของไฟล์เกินขนาดการอัปโหลด"},
+	string(errs.CodeInvalidChunkChecksum):     {English: "chunk checksum must be sha256 and a base64 encoded digest", Lao: "ຄ່າກວດສອບຂອງສ່ວນໄຟລ໌ຕ້ອງເປັນ sha256 ແລະ ຄ່າທີ່ເຂົ້າລະຫັດ base64", Thai: "ค่าตรวจสอบของส่วนไฟล์ต้องเป็น sha256 และค่าที่เข้ารหัส base64"},
+	string(errs.CodeChunkChecksumMismatch):    {English: "chunk checksum mismatch", Lao: "ຄ່າກວດສອບຂອງສ່ວນໄຟລ໌ບໍ່ກົງກັນ", Thai: "ค่าตรวจสอบของส่วนไฟล์ไม่ตรงกัน"},
+	string(errs.CodeUnsupportedImage):         {English: "file is not a PNG, JPEG or WebP image", Lao: "ໄຟລ໌ບໍ່ແມ່ນຮູບ PNG, JPEG ຫຼື WebP", Thai: "ไฟล์ไม่ใช่รูป PNG, JPEG หรือ WebP"},
+	string(errs.CodeCorruptedImage):           {English: "image is corrupted", Lao: "ຮູບເສຍຫາຍ", Thai: "รูปเสียหาย"},
+	string(errs.CodeImageTooSmall):            {English: "image must be at least {pixels}x{pixels} pixels", Lao: "ຮູບຕ້ອງມີຢ່າງໜ້ອຍ {pixels}x{pixels} ພິກເຊວ", Thai: "รูปต้องมีขนาดอย่างน้อย {pixels}x{pixels} พิกเซล"},
+	string(errs.CodeImageTooLarge):            {English: "image must be at most {pixels}x{pixels} pixels", Lao: "ຮູບຕ້ອງມີບໍ່ເກີນ {pixels}x{pixels} ພິກເຊວ", Thai: "รูปต้องมีขนาดไม่เกิน {pixels}x{pixels} พิกเซล"},
+	string(errs.CodeNoFinalizedGrades):        {English: "student has no finalized grades", Lao: "ນັກສຶກສາຍັງບໍ່ມີຄະແນນທີ່ຢືນຢັນແລ້ວ", Thai: "นักศึกษายังไม่มีเกรดที่ยืนยันแล้ว"},
+	string(errs.CodeNoStudentsEnrolled):       {English: "no students enrolled in this classroom", Lao: "ບໍ່ມີນັກສຶກສາລົງທະບຽນໃນຫ້ອງຮຽນນີ້", Thai: "ไม่มีนักศึกษาลงทะเบียนในห้องเรียนนี้"},
+
+	// validation rules, min, max and len have a variant per kind of value
+	"validation.required":   {English: "is required", Lao: "ຕ້ອງລະບຸ", Thai: "ต้องระบุ"},
+	"validation.min":        {English: "must be at least {param}", Lao: "ຕ້ອງບໍ່ໜ້ອຍກວ່າ {param}", Thai: "ต้องไม่น้อยกว่า {param}"},
+	"validation.min:length": {English: "must be at least {param} characters", Lao: "ຕ້ອງມີຢ່າງໜ້ອຍ {param} ຕົວອັກສອນ", Thai: "ต้องมีอย่างน้อย {param} ตัวอักษร"},
+	"validation.min:items":  {English: "must have at least {param} items", Lao: "ຕ້ອງມີຢ່າງໜ້ອຍ {param} ລາຍການ", Thai: "ต้องมีอย่างน้อย {param} รายการ"},
+	"validation.max":        {English: "must be at most {param}", Lao: "ຕ້ອງບໍ່ເກີນ {param}", Thai: "ต้องไม่เกิน {param}"},
+	"validation.max:length": {English: "must be at most {param} characters", Lao: "ຕ້ອງມີບໍ່ເກີນ {param} ຕົວອັກສອນ", Thai: "ต้องมีไม่เกิน {param} ตัวอักษร"},
+	"validation.max:items":  {English: "must have at most {param} items", Lao: "ຕ້ອງມີບໍ່ເກີນ {param} ລາຍການ", Thai: "ต้องมีไม่เกิน {param} รายการ"},
+	"validation.len":        {English: "must be {param}", Lao: "ຕ້ອງເທົ່າກັບ {param}", Thai: "ต้องเท่ากับ {param}"},
+	"validation.len:length": {English: "must be {param} characters", Lao: "ຕ້ອງມີ {param} ຕົວອັກສອນ", Thai: "ต้องมี {param} ตัวอักษร"},
+	"validation.len:items":  {English: "must have {param} items", Lao: "ຕ້ອງມີ {param} ລາຍການ", Thai: "ต้องมี {param} รายการ"},
+	"validation.gt":         {English: "must be greater than {param}", Lao: "ຕ້ອງຫຼາຍກວ່າ {param}", Thai: "ต้องมากกว่า {param}"},
+	"validation.gte":        {English: "must be {param} or more", Lao: "ຕ້ອງບໍ່ໜ້ອຍກວ່າ {param}", Thai: "ต้องไม่น้อยกว่า {param}"},
+	"validation.lt":         {English: "must be less than {param}", Lao: "ຕ້ອງໜ້ອຍກວ່າ {param}", Thai: "ต้องน้อยกว่า {param}"},
+	"validation.lte":        {English: "must be {param} or less", Lao: "ຕ້ອງບໍ່ເກີນ {param}", Thai: "ต้องไม่เกิน {param}"},
+	"validation.oneof":      {English: "must be one of {param}", Lao: "ຕ້ອງແມ່ນໜຶ່ງໃນ {param}", Thai: "ต้องเป็นหนึ่งใน {param}"},
+	"validation.email":      {English: "must be an email address", Lao: "ຕ້ອງເປັນທີ່ຢູ່ອີເມວ", Thai: "ต้องเป็นที่อยู่อีเมล"},
+	"validation.lao_phone":  {English: "must be a Lao phone number, e.g. 20XXXXXXXX or 021XXXXXX", Lao: "ຕ້ອງເປັນເບີໂທລະສັບລາວ, ເຊັ່ນ 20XXXXXXXX ຫຼື 021XXXXXX", Thai: "ต้องเป็นเบอร์โทรศัพท์ลาว เช่น 20XXXXXXXX หรือ 021XXXXXX"},
+	"validation.student_id": {English: "is not a student ID", Lao: "ບໍ່ແມ່ນລະຫັດນັກສຶກສາ", Thai: "ไม่ใช่รหัสนักศึกษา"},
+	"validation.date":       {English: "must be a date as DD-MM-YYYY", Lao: "ຕ້ອງເປັນວັນທີແບບ DD-MM-YYYY", Thai: "ต้องเป็นวันที่แบบ DD-MM-YYYY"},
+	"validation.invalid":    {English: "is invalid", Lao: "ບໍ່ຖືກຕ້ອງ", Thai: "ไม่ถูกต้อง"},
+}
@@ -8,37 +8,81 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"go_starter/config"
 	"go_starter/controllers"
+	"go_starter/controllers/api"
 	"go_starter/controllers/web"
+	api2 "go_starter/routes/api"
 	web2 "go_starter/routes/web"
 
 	//"go_starter/controllers/web"
 	"go_starter/database"
+	"go_starter/errs"
+	"go_starter/graph"
+	"go_starter/i18n"
 	"go_starter/logs"
+	"go_starter/migrations"
+	"go_starter/openapi"
 	"go_starter/partners"
 	"go_starter/repositories"
+	"go_starter/requests"
+	"go_starter/scanner"
+	"go_starter/security"
 	//web2 "go_starter/routes/web"
 	"go_starter/services"
+	"go_starter/storage"
 	"go_starter/trails"
 	"log"
 	"net/http"
+	"strings"
 )
 
 func main() {
 
-	//connect database
+	//postgres, mysql or sqlite from database.driver
 	//postgres
-	postgresConnection, err := database.PostgresConnection()
+	dbConnection, err := database.Connection()
 	if err != nil {
 		logs.Error(err)
 		return
 	}
 
-	////mysql
-	//mySQLConnection, err := database.MysqlConnection()
-	//if err != nil {
-	//	logs.Error(err)
-	//	return
-	//}
+	//refuse to serve from a schema that is behind the migrations
+	if err = migrations.Check(dbConnection); err != nil {
+		logs.Error(err)
+		return
+	}
+
+	//signing keys of this deployment
+	if err = security.LoadSecrets(); err != nil {
+		logs.Error(err)
+		return
+	}
+
+	//metrics
+	metricsService := services.NewMetricsService(database.Replicas(dbConnection))
+	metricsController := controllers.NewMetricsController(metricsService)
+
+	//every message must be translated into every language, an error code included
+	var errorCodes []string
+	for _, definition := range errs.Catalog() {
+		errorCodes = append(errorCodes, string(definition.Code))
+	}
+	if err = i18n.Check(errorCodes...); err != nil {
+		logs.Error(err)
+		return
+	}
+
+	//error catalog
+	errorController := controllers.NewErrorController()
+
+	//language preference
+	languageController := controllers.NewLanguageController()
+
+	//file storage
+	fileStorage, err := storage.NewStorage()
+	if err != nil {
+		logs.Error(err)
+		return
+	}
 
 	//call api client interface
 	httpClient := http.Client{}
@@ -46,31 +90,110 @@ func main() {
 	partners.NewPartner(newHttpClientTrail)
 
 	//basic structure
-	newRepository := repositories.NewRepository(postgresConnection)
+	newRepository := repositories.NewRepository(dbConnection)
 	newService := services.NewService(newRepository)
-	//newControllerApi := api.NewControllerApi(newService)
+	newControllerApi := api.NewControllerApi(newService)
+
+	//unit of work for services writing through several repositories
+	unitOfWork := repositories.NewUnitOfWork(dbConnection)
 
 	//student
-	studentRepository := repositories.NewStudentRepository(postgresConnection)
-	studentService := services.NewStudentServices(studentRepository)
+	fileRepository := repositories.NewFileRepository(dbConnection)
+	studentRepository := repositories.NewStudentRepository(dbConnection)
+	studentService := services.NewStudentServices(studentRepository, fileRepository, unitOfWork, fileStorage)
 	studentController := controllers.NewCustomerController(studentService)
 
 	// User
-	userRepository := repositories.NewUserRepository(postgresConnection)
-	userService := services.NewUserService(userRepository)
+	userRepository := repositories.NewUserRepository(dbConnection)
+	userService := services.NewUserService(userRepository, fileRepository, unitOfWork, fileStorage)
 	userController := controllers.NewUserController(userService)
 
+	//attendance
+	attendanceRepository := repositories.NewAttendanceRepository(dbConnection)
+	attendanceService := services.NewAttendanceService(attendanceRepository, studentRepository)
+	attendanceController := controllers.NewAttendanceController(attendanceService)
+
+	//gradebook
+	gradeRepository := repositories.NewGradeRepository(dbConnection)
+	gradeService := services.NewGradeService(gradeRepository, studentRepository, unitOfWork)
+	gradeController := controllers.NewGradeController(gradeService)
+
+	//transcript
+	transcriptRepository := repositories.NewTranscriptRepository(dbConnection)
+	transcriptService := services.NewTranscriptService(transcriptRepository, gradeRepository, studentRepository)
+	transcriptController := controllers.NewTranscriptController(transcriptService)
+
+	//id card
+	idCardService := services.NewIDCardService(studentRepository, gradeRepository, fileStorage)
+	idCardController := controllers.NewIDCardController(idCardService)
+
+	//resumable uploads
+	uploadRepository := repositories.NewUploadRepository(dbConnection)
+	uploadService := services.NewUploadService(uploadRepository, fileStorage)
+	uploadController := controllers.NewUploadController(uploadService)
+
+	//student documents
+	virusScanner, err := scanner.NewScanner()
+	if err != nil {
+		logs.Error(err)
+		return
+	}
+	documentRepository := repositories.NewStudentDocumentRepository(dbConnection)
+	documentService := services.NewStudentDocumentService(documentRepository, studentRepository, fileRepository, uploadRepository, fileStorage, virusScanner)
+	documentController := controllers.NewStudentDocumentController(documentService)
+
+	//photo
+	photoService := services.NewPhotoService(fileRepository, fileStorage)
+	photoController := controllers.NewPhotoController(photoService)
+
+	//file
+	fileService := services.NewFileService(fileRepository, studentRepository, userRepository, fileStorage)
+	fileController := controllers.NewFileController(fileService)
+
+	//graphql over the student and user services
+	graphSchema, err := graph.NewSchema(studentService, userService)
+	if err != nil {
+		logs.Error(err)
+		return
+	}
+	graphQLController := controllers.NewGraphQLController(graphSchema)
+
 	//connect route
 	app := fiber.New(fiber.Config{
 		JSONEncoder: json.Marshal,
 		JSONDecoder: json.Unmarshal,
 		BodyLimit:   16 * 1024 * 1024,
+		// The client address is read from ProxyHeader only when the request comes from
+		// one of the trusted proxies
+		ProxyHeader:             config.GetEnv("app.proxy_header", ""),
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          strings.Fields(config.GetEnv("app.trusted_proxies", "")),
+		// Unknown routes and unreadable bodies get the envelope of every other error
+		ErrorHandler: controllers.ErrorHandler,
 	})
-	app.Use(logger.New())
+	app.Use(controllers.RequestID())
+	app.Use(logger.New(logger.Config{
+		Format: "${time} | ${locals:requestid} | ${status} | ${latency} | ${ip} | ${method} | ${path} | ${error}\n",
+	}))
 	app.Use(cors.New())
+	// Keep the read-your-writes window per client, reads after its own write go to the primary
+	app.Use(controllers.DatabaseSession())
+	// Bound every request, the report routes set a longer deadline of their own
+	app.Use(controllers.RequestTimeout("app.request_timeout_seconds", 30))
+	// Answer messages in the language of the client
+	app.Use(controllers.Locale())
+
+	// Every route registers through router, which documents it for /openapi.json
+	router := openapi.NewRouter(app)
+	system := []string{"system"}
+	router.Register(fiber.MethodGet, "/metrics", openapi.Operation{Summary: "Prometheus metrics", Tags: system, Produces: []string{openapi.Text}}, metricsController.GetMetricsController)
+	router.Register(fiber.MethodGet, "/errors", openapi.Operation{Summary: "List the error codes", Tags: system, Response: []errs.Definition{}}, errorController.GetErrorCatalogController)
+	router.Register(fiber.MethodPut, "/language", openapi.Operation{Summary: "Remember the language of the client", Tags: system, Request: requests.LanguageRequest{}, Response: openapi.Message{}}, languageController.SetLanguageController)
 
-	// Serve static files from the "assets/ceit/2024/images" directory
-	app.Static("/ceit/2024/images", "./assets/ceit/2024/images")
+	// Serve student photos and avatars from the configured storage backend, signed URL or access token required
+	files := []string{"files"}
+	router.Register(fiber.MethodGet, "/ceit/2024/images/*", openapi.Operation{Summary: "Get a student photo", Tags: files, Request: requests.ImageRequest{}, Produces: []string{"image/*"}}, fileController.GetPhotoController)
+	router.Register(fiber.MethodGet, "/ceit/2024/avatars/*", openapi.Operation{Summary: "Get an avatar", Tags: files, Request: requests.ImageRequest{}, Produces: []string{"image/*"}}, fileController.GetPhotoController)
 
 	//Web routes
 	newController := web.NewController(newService)
@@ -78,18 +201,29 @@ func main() {
 		newController,
 		studentController,
 		userController,
+		attendanceController,
+		gradeController,
+		transcriptController,
+		idCardController,
+		documentController,
+		uploadController,
+		photoController,
 		//new web controller
 	)
-	newWebRoute.Install(app)
-
-	//Api routes
-	// newControllerApi = api.NewControllerApi(newService)
-	// newApiRoute := api2.NewApiRoutes(
-	// 	newControllerApi,
-	// 	studentController,
-	// 	userController,
-	// )
-	// newApiRoute.Install(app)
+	newWebRoute.Install(router)
+
+	//Api routes, versioned under /api/v1
+	newApiRoute := api2.NewApiRoutes(
+		newControllerApi,
+		studentController,
+		userController,
+		photoController,
+		graphQLController,
+	)
+	newApiRoute.Install(router)
+
+	//OpenAPI document of the routes above at /openapi.json, browsed at /docs
+	openapi.Install(router)
 
 	log.Fatal(app.Listen(fmt.Sprintf(":%s", config.Env("app.port"))))
 
@@ -0,0 +1,378 @@
+// Package migrations applies the versioned schema changes kept in one directory per
+// database dialect. A migration is a <version>_<name>.up.sql and <version>_<name>.down.sql
+// pair, versions are UTC timestamps and applied versions are recorded in schema_migrations.
+package migrations
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"github.com/pkg/errors"
+	"go_starter/database"
+	"gorm.io/gorm"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+//go:embed postgres/*.sql mysql/*.sql sqlite/*.sql
+var files embed.FS
+
+// Dialects are the databases migrations are written for, named like gorm dialectors
+var Dialects = []string{"postgres", "mysql", "sqlite"}
+
+// VersionFormat is the layout of migration versions
+const VersionFormat = "20060102150405"
+
+const schemaMigrationsTable = "schema_migrations"
+
+// ErrPendingMigrations is returned by Check while the schema is behind the embedded migrations
+var ErrPendingMigrations = errors.New("database schema is not up to date")
+
+// ErrIrreversible is returned by Down on reaching a migration that cannot be rolled back
+var ErrIrreversible = errors.New("migration cannot be rolled back")
+
+// irreversibleMarker is the line of a down file that has the migration never rolled back
+const irreversibleMarker = "-- irreversible"
+
+type Migration struct {
+	Version string
+	Name    string
+	Up      string
+	Down    string
+	// Irreversible marks a migration whose down file is the irreversible marker, Down
+	// refuses to roll it back
+	Irreversible bool
+}
+
+type MigrationStatus struct {
+	Version   string     `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+	// Unknown marks a version applied to the database that this build has no file for
+	Unknown bool `json:"unknown,omitempty"`
+}
+
+type schemaMigration struct {
+	Version   string
+	Name      string
+	AppliedAt time.Time
+}
+
+var fileName = regexp.MustCompile(`^(\d{14})_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Load returns the migrations of a dialect ordered by version
+func Load(dialect string) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, dialect)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for dialect %q", dialect)
+	}
+	byVersion := map[string]*Migration{}
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %s/%s", dialect, entry.Name())
+		}
+		content, err := files.ReadFile(path.Join(dialect, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		migration, ok := byVersion[match[1]]
+		if !ok {
+			migration = &Migration{Version: match[1], Name: match[2]}
+			byVersion[match[1]] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %s has two names, %s and %s", match[1], migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	var migrations []Migration
+	for _, migration := range byVersion {
+		if strings.TrimSpace(migration.Up) == "" {
+			return nil, fmt.Errorf("migration %s_%s has no up file", migration.Version, migration.Name)
+		}
+		if irreversible(migration.Down) {
+			if len(statements(migration.Down)) > 0 {
+				return nil, fmt.Errorf("migration %s_%s is irreversible but its down file has statements", migration.Version, migration.Name)
+			}
+			migration.Irreversible = true
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	dialect    string
+	migrations []Migration
+}
+
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	// Applied versions are read from the primary, a lagging replica would rerun migrations.
+	// Building an index may take far longer than the query timeout allows.
+	db = db.WithContext(database.WithQueryTimeout(database.WithPrimary(context.Background()), 0))
+	dialect := db.Dialector.Name()
+	migrations, err := Load(dialect)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, dialect: dialect, migrations: migrations}, nil
+}
+
+// Check refuses a schema with pending migrations, run at startup so the app never
+// serves from tables it does not match
+func Check(db *gorm.DB) error {
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	pending, err := migrator.Pending()
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: %d pending migrations starting at %s_%s, run: go run ./cmd/migrate up",
+			ErrPendingMigrations, len(pending), pending[0].Version, pending[0].Name)
+	}
+	return nil
+}
+
+func (m *Migrator) Dialect() string {
+	return m.dialect
+}
+
+// Status lists every known migration and every applied version, ordered by version
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	var status []MigrationStatus
+	for _, migration := range m.migrations {
+		row := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			row.Applied = true
+			row.AppliedAt = &appliedAt
+			delete(applied, migration.Version)
+		}
+		status = append(status, row)
+	}
+	for _, record := range applied {
+		appliedAt := record.AppliedAt
+		status = append(status, MigrationStatus{
+			Version:   record.Version,
+			Name:      record.Name,
+			Applied:   true,
+			AppliedAt: &appliedAt,
+			Unknown:   true,
+		})
+	}
+	sort.Slice(status, func(i, j int) bool {
+		return status[i].Version < status[j].Version
+	})
+	return status, nil
+}
+
+// Pending returns the migrations not applied yet, in the order they will run
+func (m *Migrator) Pending() ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// Up applies up to steps pending migrations, every pending one when steps is 0
+func (m *Migrator) Up(steps int) ([]Migration, error) {
+	if err := m.createTable(); err != nil {
+		return nil, err
+	}
+	pending, err := m.Pending()
+	if err != nil {
+		return nil, err
+	}
+	if steps > 0 && steps < len(pending) {
+		pending = pending[:steps]
+	}
+	var done []Migration
+	for _, migration := range pending {
+		err = m.run(migration, migration.Up, func(tx *gorm.DB) error {
+			return tx.Table(schemaMigrationsTable).Create(&schemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now().UTC(),
+			}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %s_%s failed: %v", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down rolls back the last steps applied migrations, newest first. It stops with
+// ErrIrreversible before an irreversible migration, the baseline never goes.
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	known := map[string]Migration{}
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+	var versions []string
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(versions)))
+	if steps > 0 && steps < len(versions) {
+		versions = versions[:steps]
+	}
+
+	var done []Migration
+	for _, version := range versions {
+		migration, ok := known[version]
+		if !ok {
+			return done, fmt.Errorf("migration %s_%s is applied but has no file in this build", version, applied[version].Name)
+		}
+		if migration.Irreversible {
+			return done, fmt.Errorf("%w: %s_%s", ErrIrreversible, migration.Version, migration.Name)
+		}
+		if strings.TrimSpace(migration.Down) == "" {
+			return done, fmt.Errorf("migration %s_%s has no down file", migration.Version, migration.Name)
+		}
+		err = m.run(migration, migration.Down, func(tx *gorm.DB) error {
+			return tx.Table(schemaMigrationsTable).Where("version = ?", migration.Version).Delete(&schemaMigration{}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("rollback of %s_%s failed: %v", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// run executes a migration script and its bookkeeping in one transaction. MySQL commits
+// DDL implicitly, so a script failing halfway there has to be repaired by hand.
+func (m *Migrator) run(migration Migration, script string, record func(tx *gorm.DB) error) error {
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range statements(script) {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return record(tx)
+	})
+}
+
+func (m *Migrator) createTable() error {
+	return m.db.Exec(`CREATE TABLE IF NOT EXISTS ` + schemaMigrationsTable + ` (
+		version VARCHAR(14) NOT NULL PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		applied_at TIMESTAMP NOT NULL
+	)`).Error
+}
+
+func (m *Migrator) applied() (map[string]schemaMigration, error) {
+	applied := map[string]schemaMigration{}
+	// A database nothing was applied to yet has no table, every migration is pending
+	if !m.db.Migrator().HasTable(schemaMigrationsTable) {
+		return applied, nil
+	}
+	var records []schemaMigration
+	if err := m.db.Table(schemaMigrationsTable).Find(&records).Error; err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// statements splits a script on semicolons ending a line, drivers are not relied on
+// to accept several statements in one call
+func statements(script string) []string {
+	var result []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			result = append(result, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		result = append(result, rest)
+	}
+	return result
+}
+
+// irreversible tells whether a down script carries the irreversible marker
+func irreversible(script string) bool {
+	for _, line := range strings.Split(script, "\n") {
+		if strings.TrimSpace(line) == irreversibleMarker {
+			return true
+		}
+	}
+	return false
+}
+
+var nameCleaner = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Create writes empty up and down files of a new migration for every dialect under
+// dir and returns their paths
+func Create(dir, name string, now time.Time) ([]string, error) {
+	name = strings.Trim(nameCleaner.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return nil, errors.New("migration name is empty")
+	}
+	version := now.UTC().Format(VersionFormat)
+	var created []string
+	for _, dialect := range Dialects {
+		for _, direction := range []string{"up", "down"} {
+			file := filepath.Join(dir, dialect, fmt.Sprintf("%s_%s.%s.sql", version, name, direction))
+			content := fmt.Sprintf("-- %s %s (%s)\n-- end every statement with a semicolon at the end of a line\n\n", direction, name, dialect)
+			destination, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+			if err != nil {
+				return created, err
+			}
+			_, err = destination.WriteString(content)
+			destination.Close()
+			if err != nil {
+				return created, err
+			}
+			created = append(created, file)
+		}
+	}
+	return created, nil
+}
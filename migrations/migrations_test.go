@@ -0,0 +1,71 @@
+package migrations
+
+import (
+	"errors"
+	"go_starter/database"
+	"testing"
+)
+
+func TestDownKeepsBaselineTables(t *testing.T) {
+	t.Setenv("SQLITE_PATH", ":memory:")
+	db, err := database.Open(database.DriverSqlite)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	})
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err = migrator.Up(0); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err = db.Exec("INSERT INTO students (student_id, firstname) VALUES ('STU001', 'Somchai')").Error; err != nil {
+		t.Fatalf("create student: %v", err)
+	}
+
+	done, err := migrator.Down(0)
+	if !errors.Is(err, ErrIrreversible) {
+		t.Fatalf("down to zero = %v, want %v", err, ErrIrreversible)
+	}
+	if want := len(migrator.migrations) - 1; len(done) != want {
+		t.Errorf("rolled back %d migrations, want every one above the baseline, %d", len(done), want)
+	}
+	if db.Migrator().HasTable("terms") {
+		t.Error("terms survived the rollback of the gradebook")
+	}
+	for _, table := range []string{"students", "users", "teachers", "classrooms", "student_classrooms"} {
+		if !db.Migrator().HasTable(table) {
+			t.Errorf("%s was dropped", table)
+		}
+	}
+	var students int64
+	db.Table("students").Count(&students)
+	if students != 1 {
+		t.Errorf("students = %d, want the one created before the rollback", students)
+	}
+	pending, err := migrator.Pending()
+	if err != nil {
+		t.Fatalf("pending: %v", err)
+	}
+	if len(pending) > 0 && pending[0].Name == "baseline" {
+		t.Error("baseline was unrecorded")
+	}
+}
+
+func TestLoadMarksBaselineIrreversible(t *testing.T) {
+	for _, dialect := range Dialects {
+		migrations, err := Load(dialect)
+		if err != nil {
+			t.Fatalf("load %s: %v", dialect, err)
+		}
+		for _, migration := range migrations {
+			if want := migration.Name == "baseline"; migration.Irreversible != want {
+				t.Errorf("%s %s_%s irreversible = %v, want %v", dialect, migration.Version, migration.Name, migration.Irreversible, want)
+			}
+		}
+	}
+}
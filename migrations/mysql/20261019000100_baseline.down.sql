@@ -0,0 +1,3 @@
+-- irreversible
+-- The baseline adopts the tables that existed before migrations, rolling it back would
+-- drop them with every record they hold. Down stops here.
@@ -0,0 +1,57 @@
+-- Tables that existed before migrations, IF NOT EXISTS keeps databases built by AutoMigrate intact
+CREATE TABLE IF NOT EXISTS students (
+    id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    student_id varchar(191),
+    firstname varchar(255),
+    lastname varchar(255),
+    phone varchar(191),
+    email varchar(191),
+    password varchar(191),
+    birthday datetime(3),
+    gender varchar(255),
+    status bigint,
+    image varchar(255),
+    created_at datetime(3),
+    updated_at datetime(3),
+    deleted_at datetime(3),
+    token text,
+    CONSTRAINT uni_students_phone UNIQUE (phone)
+);
+
+CREATE TABLE IF NOT EXISTS classrooms (
+    id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    class_name varchar(255),
+    class_year bigint,
+    subject_name varchar(255)
+);
+
+CREATE TABLE IF NOT EXISTS student_classrooms (
+    id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    student_id bigint unsigned,
+    classroom_id bigint unsigned,
+    CONSTRAINT fk_student_classrooms_student FOREIGN KEY (student_id) REFERENCES students (id),
+    CONSTRAINT fk_student_classrooms_classroom FOREIGN KEY (classroom_id) REFERENCES classrooms (id)
+);
+
+CREATE TABLE IF NOT EXISTS teachers (
+    id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    phone varchar(191),
+    firstname varchar(255),
+    lastname varchar(255),
+    password varchar(191),
+    token text,
+    created_at datetime(3),
+    updated_at datetime(3),
+    CONSTRAINT uni_teachers_phone UNIQUE (phone)
+);
+
+CREATE TABLE IF NOT EXISTS users (
+    id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    email varchar(191),
+    password varchar(191),
+    token text,
+    created_at datetime(3),
+    updated_at datetime(3),
+    CONSTRAINT uni_users_email UNIQUE (email),
+    CONSTRAINT uni_users_password UNIQUE (password)
+);
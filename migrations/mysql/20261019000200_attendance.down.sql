@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS attendances;
+DROP TABLE IF EXISTS classroom_sessions;
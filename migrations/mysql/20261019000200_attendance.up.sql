@@ -0,0 +1,24 @@
+CREATE TABLE classroom_sessions (
+    id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    classroom_id bigint unsigned,
+    session_date datetime(3),
+    topic text,
+    created_at datetime(3),
+    updated_at datetime(3),
+    CONSTRAINT fk_classroom_sessions_classroom FOREIGN KEY (classroom_id) REFERENCES classrooms (id)
+);
+CREATE INDEX idx_classroom_sessions_classroom_id ON classroom_sessions (classroom_id);
+
+CREATE TABLE attendances (
+    id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    classroom_session_id bigint unsigned,
+    student_id bigint unsigned,
+    status varchar(191),
+    note text,
+    marked_by bigint unsigned,
+    created_at datetime(3),
+    updated_at datetime(3),
+    CONSTRAINT fk_attendances_classroom_session FOREIGN KEY (classroom_session_id) REFERENCES classroom_sessions (id),
+    CONSTRAINT fk_attendances_student FOREIGN KEY (student_id) REFERENCES students (id)
+);
+CREATE UNIQUE INDEX idx_attendance_session_student ON attendances (classroom_session_id, student_id);
@@ -0,0 +1,8 @@
+CREATE TABLE attendance_check_ins (
+    id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    classroom_session_id bigint unsigned,
+    student_id bigint unsigned,
+    token_id varchar(255),
+    created_at datetime(3)
+);
+CREATE UNIQUE INDEX idx_check_in_session_student ON attendance_check_ins (classroom_session_id, student_id);
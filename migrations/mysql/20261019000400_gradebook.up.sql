@@ -0,0 +1,73 @@
+CREATE TABLE grading_scales (
+    id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    name varchar(191),
+    is_default boolean,
+    created_at datetime(3),
+    updated_at datetime(3),
+    CONSTRAINT uni_grading_scales_name UNIQUE (name)
+);
+
+CREATE TABLE grade_boundaries (
+    id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    grading_scale_id bigint unsigned,
+    letter varchar(255),
+    min_percentage double,
+    grade_point double,
+    CONSTRAINT fk_grading_scales_grades FOREIGN KEY (grading_scale_id) REFERENCES grading_scales (id)
+);
+CREATE INDEX idx_grade_boundaries_grading_scale_id ON grade_boundaries (grading_scale_id);
+
+CREATE TABLE terms (
+    id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    name varchar(191),
+    start_date datetime(3),
+    end_date datetime(3),
+    grading_scale_id bigint unsigned,
+    finalized_at datetime(3),
+    created_at datetime(3),
+    updated_at datetime(3),
+    CONSTRAINT uni_terms_name UNIQUE (name)
+);
+
+CREATE TABLE assessments (
+    id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    classroom_id bigint unsigned,
+    term_id bigint unsigned,
+    title varchar(255),
+    type varchar(255),
+    weight double,
+    max_score double,
+    created_at datetime(3),
+    updated_at datetime(3),
+    CONSTRAINT fk_assessments_classroom FOREIGN KEY (classroom_id) REFERENCES classrooms (id),
+    CONSTRAINT fk_assessments_term FOREIGN KEY (term_id) REFERENCES terms (id)
+);
+CREATE INDEX idx_assessments_classroom_id ON assessments (classroom_id);
+CREATE INDEX idx_assessments_term_id ON assessments (term_id);
+
+CREATE TABLE assessment_scores (
+    id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    assessment_id bigint unsigned,
+    student_id bigint unsigned,
+    score double,
+    graded_by bigint unsigned,
+    created_at datetime(3),
+    updated_at datetime(3),
+    CONSTRAINT fk_assessment_scores_assessment FOREIGN KEY (assessment_id) REFERENCES assessments (id)
+);
+CREATE UNIQUE INDEX idx_score_assessment_student ON assessment_scores (assessment_id, student_id);
+
+CREATE TABLE final_grades (
+    id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    student_id bigint unsigned,
+    classroom_id bigint unsigned,
+    term_id bigint unsigned,
+    percentage double,
+    letter varchar(255),
+    grade_point double,
+    created_at datetime(3),
+    CONSTRAINT fk_final_grades_student FOREIGN KEY (student_id) REFERENCES students (id),
+    CONSTRAINT fk_final_grades_classroom FOREIGN KEY (classroom_id) REFERENCES classrooms (id),
+    CONSTRAINT fk_final_grades_term FOREIGN KEY (term_id) REFERENCES terms (id)
+);
+CREATE UNIQUE INDEX idx_final_grade_student_classroom_term ON final_grades (student_id, classroom_id, term_id);
@@ -0,0 +1,14 @@
+ALTER TABLE classrooms ADD COLUMN credits bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE transcripts (
+    id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    serial_no varchar(191),
+    student_id bigint unsigned,
+    digest varchar(191),
+    signature text,
+    issued_at datetime(3),
+    CONSTRAINT uni_transcripts_serial_no UNIQUE (serial_no),
+    CONSTRAINT fk_transcripts_student FOREIGN KEY (student_id) REFERENCES students (id)
+);
+CREATE INDEX idx_transcripts_student_id ON transcripts (student_id);
+CREATE INDEX idx_transcripts_digest ON transcripts (digest);
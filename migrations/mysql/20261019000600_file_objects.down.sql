@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS file_references;
+DROP TABLE IF EXISTS file_objects;
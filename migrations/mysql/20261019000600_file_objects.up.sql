@@ -0,0 +1,22 @@
+CREATE TABLE file_objects (
+    id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    storage_key varchar(191),
+    hash varchar(191),
+    size bigint,
+    content_type varchar(255),
+    created_at datetime(3),
+    CONSTRAINT uni_file_objects_storage_key UNIQUE (storage_key)
+);
+CREATE INDEX idx_file_objects_hash ON file_objects (hash);
+
+CREATE TABLE file_references (
+    id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    file_object_id bigint unsigned,
+    owner_type varchar(191),
+    owner_id varchar(191),
+    created_at datetime(3),
+    updated_at datetime(3),
+    CONSTRAINT fk_file_references_file_object FOREIGN KEY (file_object_id) REFERENCES file_objects (id)
+);
+CREATE INDEX idx_file_references_file_object_id ON file_references (file_object_id);
+CREATE UNIQUE INDEX idx_file_reference_owner ON file_references (owner_type, owner_id);
@@ -0,0 +1,21 @@
+CREATE TABLE student_documents (
+    id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    student_id bigint unsigned,
+    document_type varchar(191),
+    title varchar(255),
+    description text,
+    document_number varchar(255),
+    issued_date datetime(3),
+    expiry_date datetime(3),
+    file_name varchar(255),
+    content_type varchar(255),
+    size bigint,
+    file_object_id bigint unsigned,
+    uploaded_by varchar(255),
+    created_at datetime(3),
+    updated_at datetime(3),
+    CONSTRAINT fk_student_documents_student FOREIGN KEY (student_id) REFERENCES students (id),
+    CONSTRAINT fk_student_documents_file_object FOREIGN KEY (file_object_id) REFERENCES file_objects (id)
+);
+CREATE INDEX idx_student_documents_student_id ON student_documents (student_id);
+CREATE INDEX idx_student_documents_document_type ON student_documents (document_type);
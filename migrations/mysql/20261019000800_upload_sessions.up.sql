@@ -0,0 +1,27 @@
+CREATE TABLE upload_sessions (
+    id varchar(64) PRIMARY KEY,
+    file_name varchar(255),
+    content_type varchar(255),
+    size bigint,
+    upload_offset bigint,
+    checksum varchar(255),
+    status varchar(191),
+    storage_key varchar(191),
+    expires_at datetime(3),
+    created_at datetime(3),
+    updated_at datetime(3)
+);
+CREATE INDEX idx_upload_sessions_status ON upload_sessions (status);
+CREATE INDEX idx_upload_sessions_expires_at ON upload_sessions (expires_at);
+
+CREATE TABLE upload_chunks (
+    id bigint unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    upload_session_id varchar(64),
+    chunk_offset bigint,
+    size bigint,
+    checksum varchar(255),
+    storage_key varchar(191),
+    created_at datetime(3),
+    CONSTRAINT fk_upload_sessions_chunks FOREIGN KEY (upload_session_id) REFERENCES upload_sessions (id)
+);
+CREATE UNIQUE INDEX idx_upload_chunk_offset ON upload_chunks (upload_session_id, chunk_offset);
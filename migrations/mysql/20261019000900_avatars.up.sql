@@ -0,0 +1,2 @@
+ALTER TABLE teachers ADD COLUMN image varchar(255);
+ALTER TABLE users ADD COLUMN image varchar(255);
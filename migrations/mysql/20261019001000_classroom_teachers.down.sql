@@ -0,0 +1,2 @@
+DROP INDEX idx_classrooms_teacher_id ON classrooms;
+ALTER TABLE classrooms DROP COLUMN teacher_id;
@@ -0,0 +1,57 @@
+-- Tables that existed before migrations, IF NOT EXISTS keeps databases built by AutoMigrate intact
+CREATE TABLE IF NOT EXISTS students (
+    id bigserial PRIMARY KEY,
+    student_id text,
+    firstname text,
+    lastname text,
+    phone text,
+    email text,
+    password text,
+    birthday timestamptz,
+    gender text,
+    status bigint,
+    image text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    token text,
+    CONSTRAINT uni_students_phone UNIQUE (phone)
+);
+
+CREATE TABLE IF NOT EXISTS classrooms (
+    id bigserial PRIMARY KEY,
+    class_name text,
+    class_year bigint,
+    subject_name text
+);
+
+CREATE TABLE IF NOT EXISTS student_classrooms (
+    id bigserial PRIMARY KEY,
+    student_id bigint,
+    classroom_id bigint,
+    CONSTRAINT fk_student_classrooms_student FOREIGN KEY (student_id) REFERENCES students (id),
+    CONSTRAINT fk_student_classrooms_classroom FOREIGN KEY (classroom_id) REFERENCES classrooms (id)
+);
+
+CREATE TABLE IF NOT EXISTS teachers (
+    id bigserial PRIMARY KEY,
+    phone text,
+    firstname text,
+    lastname text,
+    password text,
+    token text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT uni_teachers_phone UNIQUE (phone)
+);
+
+CREATE TABLE IF NOT EXISTS users (
+    id bigserial PRIMARY KEY,
+    email text,
+    password text,
+    token text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT uni_users_email UNIQUE (email),
+    CONSTRAINT uni_users_password UNIQUE (password)
+);
@@ -0,0 +1,24 @@
+CREATE TABLE classroom_sessions (
+    id bigserial PRIMARY KEY,
+    classroom_id bigint,
+    session_date timestamptz,
+    topic text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT fk_classroom_sessions_classroom FOREIGN KEY (classroom_id) REFERENCES classrooms (id)
+);
+CREATE INDEX idx_classroom_sessions_classroom_id ON classroom_sessions (classroom_id);
+
+CREATE TABLE attendances (
+    id bigserial PRIMARY KEY,
+    classroom_session_id bigint,
+    student_id bigint,
+    status text,
+    note text,
+    marked_by bigint,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT fk_attendances_classroom_session FOREIGN KEY (classroom_session_id) REFERENCES classroom_sessions (id),
+    CONSTRAINT fk_attendances_student FOREIGN KEY (student_id) REFERENCES students (id)
+);
+CREATE UNIQUE INDEX idx_attendance_session_student ON attendances (classroom_session_id, student_id);
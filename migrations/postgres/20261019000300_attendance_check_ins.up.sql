@@ -0,0 +1,8 @@
+CREATE TABLE attendance_check_ins (
+    id bigserial PRIMARY KEY,
+    classroom_session_id bigint,
+    student_id bigint,
+    token_id text,
+    created_at timestamptz
+);
+CREATE UNIQUE INDEX idx_check_in_session_student ON attendance_check_ins (classroom_session_id, student_id);
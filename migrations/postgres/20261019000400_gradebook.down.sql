@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS final_grades;
+DROP TABLE IF EXISTS assessment_scores;
+DROP TABLE IF EXISTS assessments;
+DROP TABLE IF EXISTS terms;
+DROP TABLE IF EXISTS grade_boundaries;
+DROP TABLE IF EXISTS grading_scales;
@@ -0,0 +1,73 @@
+CREATE TABLE grading_scales (
+    id bigserial PRIMARY KEY,
+    name text,
+    is_default boolean,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT uni_grading_scales_name UNIQUE (name)
+);
+
+CREATE TABLE grade_boundaries (
+    id bigserial PRIMARY KEY,
+    grading_scale_id bigint,
+    letter text,
+    min_percentage double precision,
+    grade_point double precision,
+    CONSTRAINT fk_grading_scales_grades FOREIGN KEY (grading_scale_id) REFERENCES grading_scales (id)
+);
+CREATE INDEX idx_grade_boundaries_grading_scale_id ON grade_boundaries (grading_scale_id);
+
+CREATE TABLE terms (
+    id bigserial PRIMARY KEY,
+    name text,
+    start_date timestamptz,
+    end_date timestamptz,
+    grading_scale_id bigint,
+    finalized_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT uni_terms_name UNIQUE (name)
+);
+
+CREATE TABLE assessments (
+    id bigserial PRIMARY KEY,
+    classroom_id bigint,
+    term_id bigint,
+    title text,
+    type text,
+    weight double precision,
+    max_score double precision,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT fk_assessments_classroom FOREIGN KEY (classroom_id) REFERENCES classrooms (id),
+    CONSTRAINT fk_assessments_term FOREIGN KEY (term_id) REFERENCES terms (id)
+);
+CREATE INDEX idx_assessments_classroom_id ON assessments (classroom_id);
+CREATE INDEX idx_assessments_term_id ON assessments (term_id);
+
+CREATE TABLE assessment_scores (
+    id bigserial PRIMARY KEY,
+    assessment_id bigint,
+    student_id bigint,
+    score double precision,
+    graded_by bigint,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT fk_assessment_scores_assessment FOREIGN KEY (assessment_id) REFERENCES assessments (id)
+);
+CREATE UNIQUE INDEX idx_score_assessment_student ON assessment_scores (assessment_id, student_id);
+
+CREATE TABLE final_grades (
+    id bigserial PRIMARY KEY,
+    student_id bigint,
+    classroom_id bigint,
+    term_id bigint,
+    percentage double precision,
+    letter text,
+    grade_point double precision,
+    created_at timestamptz,
+    CONSTRAINT fk_final_grades_student FOREIGN KEY (student_id) REFERENCES students (id),
+    CONSTRAINT fk_final_grades_classroom FOREIGN KEY (classroom_id) REFERENCES classrooms (id),
+    CONSTRAINT fk_final_grades_term FOREIGN KEY (term_id) REFERENCES terms (id)
+);
+CREATE UNIQUE INDEX idx_final_grade_student_classroom_term ON final_grades (student_id, classroom_id, term_id);
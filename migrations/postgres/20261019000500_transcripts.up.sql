@@ -0,0 +1,14 @@
+ALTER TABLE classrooms ADD COLUMN IF NOT EXISTS credits bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE transcripts (
+    id bigserial PRIMARY KEY,
+    serial_no text,
+    student_id bigint,
+    digest text,
+    signature text,
+    issued_at timestamptz,
+    CONSTRAINT uni_transcripts_serial_no UNIQUE (serial_no),
+    CONSTRAINT fk_transcripts_student FOREIGN KEY (student_id) REFERENCES students (id)
+);
+CREATE INDEX idx_transcripts_student_id ON transcripts (student_id);
+CREATE INDEX idx_transcripts_digest ON transcripts (digest);
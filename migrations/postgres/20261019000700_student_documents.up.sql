@@ -0,0 +1,21 @@
+CREATE TABLE student_documents (
+    id bigserial PRIMARY KEY,
+    student_id bigint,
+    document_type text,
+    title text,
+    description text,
+    document_number text,
+    issued_date timestamptz,
+    expiry_date timestamptz,
+    file_name text,
+    content_type text,
+    size bigint,
+    file_object_id bigint,
+    uploaded_by text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT fk_student_documents_student FOREIGN KEY (student_id) REFERENCES students (id),
+    CONSTRAINT fk_student_documents_file_object FOREIGN KEY (file_object_id) REFERENCES file_objects (id)
+);
+CREATE INDEX idx_student_documents_student_id ON student_documents (student_id);
+CREATE INDEX idx_student_documents_document_type ON student_documents (document_type);
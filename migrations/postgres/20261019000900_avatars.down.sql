@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN IF EXISTS image;
+ALTER TABLE teachers DROP COLUMN IF EXISTS image;
@@ -0,0 +1,2 @@
+ALTER TABLE teachers ADD COLUMN IF NOT EXISTS image text;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS image text;
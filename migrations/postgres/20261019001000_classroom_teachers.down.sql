@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_classrooms_teacher_id;
+ALTER TABLE classrooms DROP COLUMN IF EXISTS teacher_id;
@@ -0,0 +1,2 @@
+ALTER TABLE classrooms ADD COLUMN IF NOT EXISTS teacher_id bigint;
+CREATE INDEX IF NOT EXISTS idx_classrooms_teacher_id ON classrooms (teacher_id);
@@ -0,0 +1,57 @@
+-- Tables that existed before migrations, IF NOT EXISTS keeps databases built by AutoMigrate intact
+CREATE TABLE IF NOT EXISTS students (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    student_id text,
+    firstname text,
+    lastname text,
+    phone text,
+    email text,
+    password text,
+    birthday datetime,
+    gender text,
+    status integer,
+    image text,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    token text,
+    CONSTRAINT uni_students_phone UNIQUE (phone)
+);
+
+CREATE TABLE IF NOT EXISTS classrooms (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    class_name text,
+    class_year integer,
+    subject_name text
+);
+
+CREATE TABLE IF NOT EXISTS student_classrooms (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    student_id integer,
+    classroom_id integer,
+    CONSTRAINT fk_student_classrooms_student FOREIGN KEY (student_id) REFERENCES students (id),
+    CONSTRAINT fk_student_classrooms_classroom FOREIGN KEY (classroom_id) REFERENCES classrooms (id)
+);
+
+CREATE TABLE IF NOT EXISTS teachers (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    phone text,
+    firstname text,
+    lastname text,
+    password text,
+    token text,
+    created_at datetime,
+    updated_at datetime,
+    CONSTRAINT uni_teachers_phone UNIQUE (phone)
+);
+
+CREATE TABLE IF NOT EXISTS users (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    email text,
+    password text,
+    token text,
+    created_at datetime,
+    updated_at datetime,
+    CONSTRAINT uni_users_email UNIQUE (email),
+    CONSTRAINT uni_users_password UNIQUE (password)
+);
@@ -0,0 +1,24 @@
+CREATE TABLE classroom_sessions (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    classroom_id integer,
+    session_date datetime,
+    topic text,
+    created_at datetime,
+    updated_at datetime,
+    CONSTRAINT fk_classroom_sessions_classroom FOREIGN KEY (classroom_id) REFERENCES classrooms (id)
+);
+CREATE INDEX idx_classroom_sessions_classroom_id ON classroom_sessions (classroom_id);
+
+CREATE TABLE attendances (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    classroom_session_id integer,
+    student_id integer,
+    status text,
+    note text,
+    marked_by integer,
+    created_at datetime,
+    updated_at datetime,
+    CONSTRAINT fk_attendances_classroom_session FOREIGN KEY (classroom_session_id) REFERENCES classroom_sessions (id),
+    CONSTRAINT fk_attendances_student FOREIGN KEY (student_id) REFERENCES students (id)
+);
+CREATE UNIQUE INDEX idx_attendance_session_student ON attendances (classroom_session_id, student_id);
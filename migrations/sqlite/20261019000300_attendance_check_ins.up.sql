@@ -0,0 +1,8 @@
+CREATE TABLE attendance_check_ins (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    classroom_session_id integer,
+    student_id integer,
+    token_id text,
+    created_at datetime
+);
+CREATE UNIQUE INDEX idx_check_in_session_student ON attendance_check_ins (classroom_session_id, student_id);
@@ -0,0 +1,73 @@
+CREATE TABLE grading_scales (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    name text,
+    is_default numeric,
+    created_at datetime,
+    updated_at datetime,
+    CONSTRAINT uni_grading_scales_name UNIQUE (name)
+);
+
+CREATE TABLE grade_boundaries (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    grading_scale_id integer,
+    letter text,
+    min_percentage real,
+    grade_point real,
+    CONSTRAINT fk_grading_scales_grades FOREIGN KEY (grading_scale_id) REFERENCES grading_scales (id)
+);
+CREATE INDEX idx_grade_boundaries_grading_scale_id ON grade_boundaries (grading_scale_id);
+
+CREATE TABLE terms (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    name text,
+    start_date datetime,
+    end_date datetime,
+    grading_scale_id integer,
+    finalized_at datetime,
+    created_at datetime,
+    updated_at datetime,
+    CONSTRAINT uni_terms_name UNIQUE (name)
+);
+
+CREATE TABLE assessments (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    classroom_id integer,
+    term_id integer,
+    title text,
+    type text,
+    weight real,
+    max_score real,
+    created_at datetime,
+    updated_at datetime,
+    CONSTRAINT fk_assessments_classroom FOREIGN KEY (classroom_id) REFERENCES classrooms (id),
+    CONSTRAINT fk_assessments_term FOREIGN KEY (term_id) REFERENCES terms (id)
+);
+CREATE INDEX idx_assessments_classroom_id ON assessments (classroom_id);
+CREATE INDEX idx_assessments_term_id ON assessments (term_id);
+
+CREATE TABLE assessment_scores (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    assessment_id integer,
+    student_id integer,
+    score real,
+    graded_by integer,
+    created_at datetime,
+    updated_at datetime,
+    CONSTRAINT fk_assessment_scores_assessment FOREIGN KEY (assessment_id) REFERENCES assessments (id)
+);
+CREATE UNIQUE INDEX idx_score_assessment_student ON assessment_scores (assessment_id, student_id);
+
+CREATE TABLE final_grades (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    student_id integer,
+    classroom_id integer,
+    term_id integer,
+    percentage real,
+    letter text,
+    grade_point real,
+    created_at datetime,
+    CONSTRAINT fk_final_grades_student FOREIGN KEY (student_id) REFERENCES students (id),
+    CONSTRAINT fk_final_grades_classroom FOREIGN KEY (classroom_id) REFERENCES classrooms (id),
+    CONSTRAINT fk_final_grades_term FOREIGN KEY (term_id) REFERENCES terms (id)
+);
+CREATE UNIQUE INDEX idx_final_grade_student_classroom_term ON final_grades (student_id, classroom_id, term_id);
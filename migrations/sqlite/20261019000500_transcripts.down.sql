@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS transcripts;
+ALTER TABLE classrooms DROP COLUMN credits;
@@ -0,0 +1,22 @@
+CREATE TABLE file_objects (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    storage_key text,
+    hash text,
+    size integer,
+    content_type text,
+    created_at datetime,
+    CONSTRAINT uni_file_objects_storage_key UNIQUE (storage_key)
+);
+CREATE INDEX idx_file_objects_hash ON file_objects (hash);
+
+CREATE TABLE file_references (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    file_object_id integer,
+    owner_type text,
+    owner_id text,
+    created_at datetime,
+    updated_at datetime,
+    CONSTRAINT fk_file_references_file_object FOREIGN KEY (file_object_id) REFERENCES file_objects (id)
+);
+CREATE INDEX idx_file_references_file_object_id ON file_references (file_object_id);
+CREATE UNIQUE INDEX idx_file_reference_owner ON file_references (owner_type, owner_id);
@@ -0,0 +1,21 @@
+CREATE TABLE student_documents (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    student_id integer,
+    document_type text,
+    title text,
+    description text,
+    document_number text,
+    issued_date datetime,
+    expiry_date datetime,
+    file_name text,
+    content_type text,
+    size integer,
+    file_object_id integer,
+    uploaded_by text,
+    created_at datetime,
+    updated_at datetime,
+    CONSTRAINT fk_student_documents_student FOREIGN KEY (student_id) REFERENCES students (id),
+    CONSTRAINT fk_student_documents_file_object FOREIGN KEY (file_object_id) REFERENCES file_objects (id)
+);
+CREATE INDEX idx_student_documents_student_id ON student_documents (student_id);
+CREATE INDEX idx_student_documents_document_type ON student_documents (document_type);
@@ -0,0 +1,27 @@
+CREATE TABLE upload_sessions (
+    id text PRIMARY KEY,
+    file_name text,
+    content_type text,
+    size integer,
+    upload_offset integer,
+    checksum text,
+    status text,
+    storage_key text,
+    expires_at datetime,
+    created_at datetime,
+    updated_at datetime
+);
+CREATE INDEX idx_upload_sessions_status ON upload_sessions (status);
+CREATE INDEX idx_upload_sessions_expires_at ON upload_sessions (expires_at);
+
+CREATE TABLE upload_chunks (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    upload_session_id text,
+    chunk_offset integer,
+    size integer,
+    checksum text,
+    storage_key text,
+    created_at datetime,
+    CONSTRAINT fk_upload_sessions_chunks FOREIGN KEY (upload_session_id) REFERENCES upload_sessions (id)
+);
+CREATE UNIQUE INDEX idx_upload_chunk_offset ON upload_chunks (upload_session_id, chunk_offset);
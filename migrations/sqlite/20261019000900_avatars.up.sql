@@ -0,0 +1,2 @@
+ALTER TABLE teachers ADD COLUMN image text;
+ALTER TABLE users ADD COLUMN image text;
@@ -0,0 +1,2 @@
+ALTER TABLE classrooms ADD COLUMN teacher_id integer;
+CREATE INDEX idx_classrooms_teacher_id ON classrooms (teacher_id);
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+const (
+	AttendancePresent = "present"
+	AttendanceAbsent  = "absent"
+	AttendanceLate    = "late"
+	AttendanceExcused = "excused"
+)
+
+type ClassroomSession struct {
+	ID          uint
+	ClassroomID uint `gorm:"index"`
+	Classroom   Classroom
+	SessionDate time.Time
+	Topic       string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type Attendance struct {
+	ID                 uint
+	ClassroomSessionID uint `gorm:"uniqueIndex:idx_attendance_session_student"`
+	StudentID          uint `gorm:"uniqueIndex:idx_attendance_session_student"`
+	ClassroomSession   ClassroomSession
+	Student            Student
+	Status             string
+	Note               string
+	MarkedBy           uint
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// AttendanceCheckIn records a student's QR self check-in and the code they used
+type AttendanceCheckIn struct {
+	ID                 uint
+	ClassroomSessionID uint `gorm:"uniqueIndex:idx_check_in_session_student"`
+	StudentID          uint `gorm:"uniqueIndex:idx_check_in_session_student"`
+	TokenID            string
+	CreatedAt          time.Time
+}
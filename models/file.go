@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Owner types of a FileReference
+const (
+	FileOwnerStudentImage    = "student_image"
+	FileOwnerTeacherPhoto    = "teacher_photo"
+	FileOwnerUserPhoto       = "user_photo"
+	FileOwnerStudentDocument = "student_document"
+)
+
+// FileObject is a stored file addressed by the SHA-256 of its content, so identical
+// uploads share one object
+type FileObject struct {
+	ID          uint
+	StorageKey  string `gorm:"unique"`
+	Hash        string `gorm:"index"`
+	Size        int64
+	ContentType string
+	CreatedAt   time.Time
+}
+
+// FileReference records which row points at a stored file, an object without
+// references is garbage
+type FileReference struct {
+	ID           uint
+	FileObjectID uint `gorm:"index"`
+	FileObject   FileObject
+	OwnerType    string `gorm:"uniqueIndex:idx_file_reference_owner"`
+	OwnerID      string `gorm:"uniqueIndex:idx_file_reference_owner"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
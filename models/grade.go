@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+const (
+	AssessmentExam       = "exam"
+	AssessmentQuiz       = "quiz"
+	AssessmentAssignment = "assignment"
+)
+
+type Term struct {
+	ID             uint
+	Name           string `gorm:"unique"`
+	StartDate      time.Time
+	EndDate        time.Time
+	GradingScaleID uint
+	FinalizedAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type GradingScale struct {
+	ID        uint
+	Name      string `gorm:"unique"`
+	IsDefault bool
+	Grades    []GradeBoundary
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// GradeBoundary maps every percentage from MinPercentage up to the next boundary to a letter grade
+type GradeBoundary struct {
+	ID             uint
+	GradingScaleID uint `gorm:"index"`
+	Letter         string
+	MinPercentage  float64
+	GradePoint     float64
+}
+
+type Assessment struct {
+	ID          uint
+	ClassroomID uint `gorm:"index"`
+	TermID      uint `gorm:"index"`
+	Classroom   Classroom
+	Term        Term
+	Title       string
+	Type        string
+	Weight      float64
+	MaxScore    float64
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type AssessmentScore struct {
+	ID           uint
+	AssessmentID uint `gorm:"uniqueIndex:idx_score_assessment_student"`
+	StudentID    uint `gorm:"uniqueIndex:idx_score_assessment_student"`
+	Assessment   Assessment
+	Score        float64
+	GradedBy     uint
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// FinalGrade is the locked result written when a term is finalized
+type FinalGrade struct {
+	ID          uint
+	StudentID   uint `gorm:"uniqueIndex:idx_final_grade_student_classroom_term"`
+	ClassroomID uint `gorm:"uniqueIndex:idx_final_grade_student_classroom_term"`
+	TermID      uint `gorm:"uniqueIndex:idx_final_grade_student_classroom_term"`
+	Student     Student
+	Classroom   Classroom
+	Term        Term
+	Percentage  float64
+	Letter      string
+	GradePoint  float64
+	CreatedAt   time.Time
+}
@@ -42,6 +42,9 @@ type Classroom struct {
 	ClassName   string `json:"className"`
 	ClassYear   int    `json:"class_year"`
 	SubjectName string `json:"subject_name"`
+	Credits     int    `json:"credits"`
+	// TeacherID is the teacher who teaches the classroom and shows its check-in codes
+	TeacherID *uint `json:"teacher_id"`
 }
 
 type StudentClassroom struct {
@@ -59,6 +62,7 @@ type Teacher struct {
 	Lastname  string
 	Password  string
 	Token     string
+	Image     string
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
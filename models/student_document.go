@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Document types a student can attach
+const (
+	DocumentBirthCertificate = "birth_certificate"
+	DocumentIDScan           = "id_scan"
+	DocumentTranscript       = "transcript"
+	DocumentMedicalForm      = "medical_form"
+)
+
+// StudentDocument is a file attached to a student record, the content lives in storage
+type StudentDocument struct {
+	ID             uint
+	StudentID      uint `gorm:"index"`
+	Student        Student
+	DocumentType   string `gorm:"index"`
+	Title          string
+	Description    string
+	DocumentNumber string
+	IssuedDate     *time.Time
+	ExpiryDate     *time.Time
+	FileName       string
+	ContentType    string
+	Size           int64
+	FileObjectID   uint
+	FileObject     FileObject
+	UploadedBy     string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
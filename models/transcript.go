@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Transcript is the issuing record of a generated transcript PDF, used to verify copies later
+type Transcript struct {
+	ID        uint
+	SerialNo  string `gorm:"unique"`
+	StudentID uint   `gorm:"index"`
+	Student   Student
+	Digest    string `gorm:"index"`
+	Signature string
+	IssuedAt  time.Time
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Upload session statuses
+const (
+	UploadPending   = "pending"
+	UploadCompleted = "completed"
+)
+
+// UploadSession is a resumable upload, chunks are appended at Offset until it reaches
+// Size and are then assembled into one stored file at StorageKey
+type UploadSession struct {
+	ID          string `gorm:"primaryKey"`
+	FileName    string
+	ContentType string
+	Size        int64
+	Offset      int64 `gorm:"column:upload_offset"`
+	// Checksum is the optional SHA-256 hex of the whole file, verified on assembly
+	Checksum   string
+	Status     string `gorm:"index"`
+	StorageKey string
+	Chunks     []UploadChunk
+	ExpiresAt  time.Time `gorm:"index"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// UploadChunk is one received chunk, Checksum is the SHA-256 hex of its content
+type UploadChunk struct {
+	ID              uint
+	UploadSessionID string `gorm:"uniqueIndex:idx_upload_chunk_offset"`
+	Offset          int64  `gorm:"column:chunk_offset;uniqueIndex:idx_upload_chunk_offset"`
+	Size            int64
+	Checksum        string
+	StorageKey      string
+	CreatedAt       time.Time
+}
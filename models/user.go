@@ -8,6 +8,7 @@ type User struct {
 	Email     string `gorm:"unique"`
 	Password  string `gorm:"unique"`
 	Token     string
+	Image     string
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -0,0 +1,231 @@
+package openapi
+
+import (
+	"go_starter/config"
+	"go_starter/responses"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const description = `Every JSON response is an envelope {status, data, error, meta, request_id}, send
+X-Response-Format: legacy for the shapes of the handlers before it. A client accepting
+application/problem+json gets the bare problem of an error. Messages are translated into
+the language of the lang query, the lang cookie or Accept-Language.`
+
+var pathParameter = regexp.MustCompile(`:([A-Za-z0-9_]+)\??|\*`)
+
+// documentPath turns the fiber path of a route into the path of its document entry
+func documentPath(route string) string {
+	return pathParameter.ReplaceAllStringFunc(route, func(parameter string) string {
+		return "{" + parameterName(parameter) + "}"
+	})
+}
+
+func parameterName(parameter string) string {
+	if parameter == "*" {
+		return "path"
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(parameter, ":"), "?")
+}
+
+// NewDocument generates the document of operations
+func NewDocument(operations ...[]Operation) *Document {
+	document := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       config.GetEnv("app.institution_name", "CEIT") + " API",
+			Description: description,
+			Version:     "1",
+		},
+		Servers: []Server{{URL: config.GetEnv("app.public_url", "http://localhost:"+config.Env("app.port"))}},
+		Paths:   map[string]PathItem{},
+	}
+	schemas := newSchemas()
+	schemas.component(reflect.TypeOf(responses.Envelope{}))
+	schemas.component(reflect.TypeOf(responses.Problem{}))
+
+	tags := map[string]bool{}
+	for _, list := range operations {
+		for _, operation := range list {
+			route := documentPath(operation.Path)
+			if document.Paths[route] == nil {
+				document.Paths[route] = PathItem{}
+			}
+			document.Paths[route][strings.ToLower(operation.Method)] = schemas.operation(operation)
+			for _, tag := range operation.Tags {
+				if !tags[tag] {
+					tags[tag] = true
+					document.Tags = append(document.Tags, Tag{Name: tag})
+				}
+			}
+		}
+	}
+	document.Components.Schemas = schemas.components
+	return document
+}
+
+func (s *schemas) operation(operation Operation) *PathOperation {
+	result := &PathOperation{
+		OperationID: operationID(operation),
+		Summary:     operation.Summary,
+		Tags:        operation.Tags,
+		Deprecated:  operation.Deprecated,
+		Responses: map[string]Response{
+			"default": {
+				Description: "Error",
+				Content: map[string]MediaType{
+					JSON:                       {Schema: s.of(reflect.TypeOf(responses.Envelope{}))},
+					"application/problem+json": {Schema: s.of(reflect.TypeOf(responses.Problem{}))},
+				},
+			},
+		},
+	}
+
+	// the request fields read from the path and the query, the rest is the body
+	var request reflect.Type
+	if operation.Request != nil {
+		request = indirect(reflect.TypeOf(operation.Request))
+	}
+	inPath := map[string]bool{}
+	for _, match := range pathParameter.FindAllString(operation.Path, -1) {
+		name := parameterName(match)
+		inPath[name] = true
+		schema := &Schema{Type: "string"}
+		for _, source := range []interface{}{operation.Request, operation.Params} {
+			if field, ok := taggedField(source, "params", name); ok {
+				schema = s.of(field.Type)
+				break
+			}
+		}
+		result.Parameters = append(result.Parameters, Parameter{Name: name, In: "path", Required: true, Schema: schema})
+	}
+	if request != nil {
+		for _, field := range fields(request) {
+			name := strings.Split(field.Tag.Get("query"), ",")[0]
+			if name == "" || name == "-" {
+				continue
+			}
+			required, schema := constrain(s.of(field.Type), field.Type, field.Tag.Get("validate"))
+			result.Parameters = append(result.Parameters, Parameter{Name: name, In: "query", Required: required, Schema: schema})
+		}
+	}
+	result.RequestBody = s.requestBody(operation, request, inPath)
+
+	status := operation.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := Response{Description: http.StatusText(status)}
+	switch {
+	case len(operation.Produces) > 0:
+		success.Content = map[string]MediaType{}
+		for _, contentType := range operation.Produces {
+			schema := &Schema{Type: "string", Format: "binary"}
+			switch contentType {
+			case JSON:
+				schema = &Schema{Type: "object"}
+			case Text, HTML:
+				schema.Format = ""
+			}
+			success.Content[contentType] = MediaType{Schema: schema}
+		}
+	case operation.Response != nil:
+		success.Content = map[string]MediaType{JSON: {Schema: s.envelope(operation)}}
+	}
+	result.Responses[strconv.Itoa(status)] = success
+	return result
+}
+
+func (s *schemas) requestBody(operation Operation, request reflect.Type, inPath map[string]bool) *RequestBody {
+	consumes := operation.Consumes
+	if consumes == "" {
+		consumes = JSON
+	}
+	if consumes != JSON && consumes != Multipart {
+		return &RequestBody{Required: true, Content: map[string]MediaType{consumes: {Schema: &Schema{Type: "string", Format: "binary"}}}}
+	}
+
+	tag := "json"
+	if consumes == Multipart {
+		tag = "form"
+	}
+	skipped := false
+	skip := func(field reflect.StructField) bool {
+		name, _ := field.Tag.Lookup("params")
+		_, query := field.Tag.Lookup("query")
+		if inPath[name] || query {
+			skipped = true
+			return true
+		}
+		return false
+	}
+	var schema *Schema
+	if request != nil {
+		schema = s.object(request, tag, skip)
+	} else {
+		schema = &Schema{Type: "object", Properties: map[string]*Schema{}}
+	}
+	for _, file := range operation.Files {
+		schema.Properties[file] = &Schema{Type: "string", Format: "binary"}
+	}
+	if len(schema.Properties) == 0 {
+		return nil
+	}
+	// refer to the request type when the body is all of it
+	if tag == "json" && !skipped && request.Name() != "" {
+		schema = s.of(request)
+	}
+	return &RequestBody{Required: true, Content: map[string]MediaType{consumes: {Schema: schema}}}
+}
+
+// envelope is the schema of the envelope answering the response and meta of operation
+func (s *schemas) envelope(operation Operation) *Schema {
+	body := &Schema{Type: "object", Properties: map[string]*Schema{
+		"data": s.of(reflect.TypeOf(operation.Response)),
+	}}
+	if len(operation.Meta) > 0 {
+		meta := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		for key, value := range operation.Meta {
+			meta.Properties[key] = s.of(reflect.TypeOf(value))
+			meta.Required = append(meta.Required, key)
+		}
+		sort.Strings(meta.Required)
+		body.Properties["meta"] = meta
+	}
+	return &Schema{AllOf: []*Schema{s.of(reflect.TypeOf(responses.Envelope{})), body}}
+}
+
+// operationID is e.g. get_api_v1_students_studentId for GET /api/v1/students/:studentId
+func operationID(operation Operation) string {
+	words := []string{strings.ToLower(operation.Method)}
+	for _, segment := range strings.Split(operation.Path, "/") {
+		segment = strings.NewReplacer(":", "", "?", "", "*", "path", "-", "_").Replace(segment)
+		if segment != "" {
+			words = append(words, segment)
+		}
+	}
+	return strings.Join(words, "_")
+}
+
+func taggedField(value interface{}, tag, name string) (reflect.StructField, bool) {
+	if value == nil {
+		return reflect.StructField{}, false
+	}
+	for _, field := range fields(indirect(reflect.TypeOf(value))) {
+		if field.Tag.Get(tag) == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
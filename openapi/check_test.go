@@ -0,0 +1,128 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Check fails when a route of app is missing from document or document has an
+// operation no route serves, a route registered around Router drifts from the document.
+// It is exported for the tests of the route sets in openapi_test.
+func Check(app *fiber.App, document *Document) error {
+	routes := app.GetRoutes(true)
+	served := map[string]bool{}
+	for _, route := range routes {
+		served[strings.ToLower(route.Method)+" "+documentPath(route.Path)] = true
+	}
+	var problems []string
+	for _, route := range routes {
+		path := documentPath(route.Path)
+		method := strings.ToLower(route.Method)
+		if document.Paths[path][method] != nil {
+			continue
+		}
+		// fiber answers HEAD with every GET route, the GET is checked on its own
+		if route.Method == fiber.MethodHead && served["get "+path] {
+			continue
+		}
+		problems = append(problems, fmt.Sprintf("%s %s: route is not documented", route.Method, route.Path))
+	}
+	for path, item := range document.Paths {
+		for method := range item {
+			if !served[method+" "+path] {
+				problems = append(problems, fmt.Sprintf("%s %s: no route serves the operation", strings.ToUpper(method), path))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("openapi document out of date:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+func TestRouterDocumentsRoutes(t *testing.T) {
+	app := fiber.New()
+	router := NewRouter(app)
+	handler := func(ctx *fiber.Ctx) error { return nil }
+	router.Register(fiber.MethodGet, "/files/*", Operation{Summary: "Get a file"}, handler)
+
+	web := router.Group("web/", handler)
+	web.Deprecated = true
+	web.Register(fiber.MethodPost, "students", Operation{Summary: "List students"}, handler)
+	web.Register(fiber.MethodHead, "uploads/:id", Operation{Summary: "Get an offset"}, handler)
+
+	// A route registered again by a later version is documented once
+	v1 := router.Group("api").Group("v1")
+	v1.Register(fiber.MethodGet, "users/:id", Operation{Summary: "Get a user"}, handler)
+	v1.Register(fiber.MethodGet, "users/:id", Operation{Summary: "Get a user again"}, handler)
+	Install(router)
+
+	document := NewDocument(router.Operations())
+	if err := Check(app, document); err != nil {
+		t.Fatal(err)
+	}
+	students := document.Paths["/web/students"]["post"]
+	if students == nil || !students.Deprecated {
+		t.Fatalf("/web/students = %+v, want a deprecated operation", students)
+	}
+	if user := document.Paths["/api/v1/users/{id}"]["get"]; user == nil || user.Summary != "Get a user" {
+		t.Fatalf("/api/v1/users/{id} = %+v, want the first registration", user)
+	}
+	if files := document.Paths["/files/{path}"]["get"]; files == nil || files.Deprecated {
+		t.Fatalf("/files/{path} = %+v, want an operation", files)
+	}
+}
+
+func TestCheckFindsUndocumentedRoutes(t *testing.T) {
+	app := fiber.New()
+	router := NewRouter(app)
+	router.Register(fiber.MethodGet, "/documented", Operation{Summary: "Documented"}, func(ctx *fiber.Ctx) error { return nil })
+	app.Get("/undocumented", func(ctx *fiber.Ctx) error { return nil })
+	if err := Check(app, NewDocument(router.Operations())); err == nil || !strings.Contains(err.Error(), "GET /undocumented: route is not documented") {
+		t.Fatalf("check = %v, want the undocumented route", err)
+	}
+}
+
+func TestRouterWithRunsHandlersOfItsRoutesOnly(t *testing.T) {
+	app := fiber.New()
+	router := NewRouter(app)
+	marked := func(ctx *fiber.Ctx) error {
+		ctx.Set("Deprecation", "true")
+		return ctx.Next()
+	}
+	handler := func(ctx *fiber.Ctx) error { return nil }
+
+	web := router.Group("web/")
+	legacy := web.With(marked)
+	legacy.Deprecated = true
+	legacy.Register(fiber.MethodGet, "students", Operation{Summary: "List students"}, handler)
+	web.Register(fiber.MethodGet, "sessions", Operation{Summary: "List sessions"}, handler)
+	web.Register(fiber.MethodPost, "sessions", Operation{Summary: "Open a session", Status: http.StatusCreated}, handler)
+
+	for path, want := range map[string]string{"/web/students": "true", "/web/sessions": ""} {
+		response, err := app.Test(httptest.NewRequest(fiber.MethodGet, path, nil))
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		if got := response.Header.Get("Deprecation"); got != want {
+			t.Errorf("GET %s Deprecation = %q, want %q", path, got, want)
+		}
+	}
+	document := NewDocument(router.Operations())
+	if students := document.Paths["/web/students"]["get"]; !students.Deprecated {
+		t.Error("/web/students is not deprecated")
+	}
+	if sessions := document.Paths["/web/sessions"]["get"]; sessions.Deprecated {
+		t.Error("/web/sessions is deprecated")
+	}
+	if _, ok := document.Paths["/web/sessions"]["post"].Responses["201"]; !ok {
+		t.Error("POST /web/sessions does not document 201")
+	}
+}
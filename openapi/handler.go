@@ -0,0 +1,49 @@
+package openapi
+
+import (
+	_ "embed"
+	"io/fs"
+	"path"
+
+	"github.com/gofiber/fiber/v2"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// docsPage renders /openapi.json with the Swagger UI served under /docs
+//
+//go:embed docs.html
+var docsPage []byte
+
+// IDParam types the :id of the routes reading it with ParamsInt
+type IDParam struct {
+	ID uint `params:"id"`
+}
+
+// Install serves the document of the routes of router at /openapi.json and its Swagger
+// UI page at /docs, after the last route of router is registered
+func Install(router *Router) {
+	system := []string{"system"}
+	var document *Document
+	router.Register(fiber.MethodGet, "/openapi.json", Operation{Summary: "This document", Tags: system, Produces: []string{JSON}}, func(ctx *fiber.Ctx) error {
+		return ctx.JSON(document)
+	})
+	router.Register(fiber.MethodGet, "/docs", Operation{Summary: "Browse this document", Tags: system, Produces: []string{HTML}}, func(ctx *fiber.Ctx) error {
+		ctx.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+		return ctx.Send(docsPage)
+	})
+	// Swagger UI is embedded, the page works without reaching a CDN
+	router.Register(fiber.MethodGet, "/docs/*", Operation{Summary: "Files of the Swagger UI of /docs", Tags: system, Produces: []string{Binary}}, swaggerUI)
+	document = NewDocument(router.Operations())
+}
+
+// swaggerUI answers the file of the embedded Swagger UI named by the path
+func swaggerUI(ctx *fiber.Ctx) error {
+	name := ctx.Params("*")
+	file, err := fs.ReadFile(swaggerFiles.FS, name)
+	if err != nil {
+		return fiber.ErrNotFound
+	}
+	ctx.Type(path.Ext(name))
+	ctx.Set(fiber.HeaderCacheControl, "public, max-age=86400")
+	return ctx.Send(file)
+}
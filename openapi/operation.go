@@ -0,0 +1,50 @@
+package openapi
+
+// Content types of request and response bodies other than JSON
+const (
+	JSON      = "application/json"
+	Multipart = "multipart/form-data"
+	PDF       = "application/pdf"
+	PNG       = "image/png"
+	Binary    = "application/octet-stream"
+	Text      = "text/plain"
+	HTML      = "text/html"
+)
+
+// Operation describes a route for the document. Its schemas come from the request
+// and response types and the validate rules of their fields.
+type Operation struct {
+	// Method and Path are those the route is registered with, Router.Register sets them.
+	// The ":name" and "*" parameters of Path become path parameters of the document.
+	Method  string
+	Path    string
+	Summary string
+	Tags    []string
+	// Request is a value of the request type, nil for none. Its fields tagged params
+	// are path parameters, those tagged query are query parameters and the rest the body.
+	Request interface{}
+	// Params types the path parameters the request does not carry, they are strings
+	// otherwise
+	Params interface{}
+	// Consumes is the content type of the body, JSON unless set. A multipart body is
+	// read from the form (or json) tags of Request, []byte fields are files.
+	Consumes string
+	// Files are the names of optional file fields of a multipart body Request has no
+	// field for
+	Files []string
+	// Response is a value of the data of the envelope, nil for a response without a
+	// body, see Message for those answering a message
+	Response interface{}
+	// Meta is the meta of the envelope with a value of each of its keys
+	Meta map[string]interface{}
+	// Produces are the content types of a response that is not an envelope, e.g. a file
+	Produces []string
+	// Status is the status of a success, 200 unless set
+	Status     int
+	Deprecated bool
+}
+
+// Message is the data of a response that is only a message
+type Message struct {
+	Message string `json:"message"`
+}
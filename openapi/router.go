@@ -0,0 +1,78 @@
+package openapi
+
+import (
+	"path"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Router registers routes on a fiber router and documents each with the operation it
+// is registered with, so the document cannot miss a route or keep one no longer served
+type Router struct {
+	router fiber.Router
+	// prefix is the path of router, fiber does not tell the path of a group
+	prefix string
+	// Deprecated marks the operations of the routes registered on the router
+	Deprecated bool
+	// handlers run before the handlers of each route registered on the router
+	handlers []fiber.Handler
+	routes   *routes
+}
+
+// routes are the operations of a router and its groups
+type routes struct {
+	operations []Operation
+	documented map[string]bool
+}
+
+// NewRouter documents the routes registered on app
+func NewRouter(app *fiber.App) *Router {
+	return &Router{
+		router: app,
+		prefix: "/",
+		routes: &routes{documented: map[string]bool{}},
+	}
+}
+
+// Group is a router for the routes under prefix, behind handlers, that documents them
+// with the routes of r
+func (r *Router) Group(prefix string, handlers ...fiber.Handler) *Router {
+	return &Router{
+		router:     r.router.Group(prefix, handlers...),
+		prefix:     path.Join(r.prefix, prefix),
+		Deprecated: r.Deprecated,
+		handlers:   r.handlers,
+		routes:     r.routes,
+	}
+}
+
+// With is a router for the routes of r that run handlers before their own. Unlike the
+// handlers of a Group, fiber runs for every route under its prefix, they run only for
+// the routes registered on the router With returns.
+func (r *Router) With(handlers ...fiber.Handler) *Router {
+	with := *r
+	with.handlers = append(append([]fiber.Handler{}, r.handlers...), handlers...)
+	return &with
+}
+
+// Register serves handlers, the last one answering, at method and route and documents
+// them with operation. A route registered again is answered by its first registration
+// like fiber does and documented once.
+func (r *Router) Register(method, route string, operation Operation, handlers ...fiber.Handler) {
+	r.router.Add(method, route, append(append([]fiber.Handler{}, r.handlers...), handlers...)...)
+
+	operation.Method = method
+	operation.Path = path.Join(r.prefix, route)
+	operation.Deprecated = operation.Deprecated || r.Deprecated
+	key := operation.Method + " " + operation.Path
+	if r.routes.documented[key] {
+		return
+	}
+	r.routes.documented[key] = true
+	r.routes.operations = append(r.routes.operations, operation)
+}
+
+// Operations documents the routes registered so far
+func (r *Router) Operations() []Operation {
+	return r.routes.operations
+}
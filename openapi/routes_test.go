@@ -0,0 +1,58 @@
+package openapi_test
+
+import (
+	"go_starter/controllers"
+	"go_starter/controllers/api"
+	"go_starter/controllers/web"
+	"go_starter/openapi"
+	api2 "go_starter/routes/api"
+	web2 "go_starter/routes/web"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TestRoutesDocumented serves the web and API routes like main does and requires the
+// document to cover every route, the controllers are never called
+func TestRoutesDocumented(t *testing.T) {
+	app := fiber.New()
+	router := openapi.NewRouter(app)
+	studentController := controllers.NewCustomerController(nil)
+	userController := controllers.NewUserController(nil)
+	photoController := controllers.NewPhotoController(nil)
+	web2.NewWebRoutes(
+		web.NewController(nil),
+		studentController,
+		userController,
+		controllers.NewAttendanceController(nil),
+		controllers.NewGradeController(nil),
+		controllers.NewTranscriptController(nil),
+		controllers.NewIDCardController(nil),
+		controllers.NewStudentDocumentController(nil),
+		controllers.NewUploadController(nil),
+		photoController,
+	).Install(router)
+	api2.NewApiRoutes(
+		api.NewControllerApi(nil),
+		studentController,
+		userController,
+		photoController,
+		controllers.NewGraphQLController(nil),
+	).Install(router)
+	openapi.Install(router)
+
+	document := openapi.NewDocument(router.Operations())
+	if err := openapi.Check(app, document); err != nil {
+		t.Fatal(err)
+	}
+	// Only the web routes /api/v1 serves too are deprecated
+	if students := document.Paths["/web/students"]["get"]; !students.Deprecated {
+		t.Error("/web/students is not deprecated")
+	}
+	if checkIn := document.Paths["/web/check-in"]["post"]; checkIn.Deprecated {
+		t.Error("/web/check-in is deprecated, /api/v1 does not serve it")
+	}
+	if _, ok := document.Paths["/api/v1/students"]["post"].Responses["201"]; !ok {
+		t.Error("POST /api/v1/students does not answer 201")
+	}
+}
@@ -0,0 +1,257 @@
+package openapi
+
+import (
+	"go_starter/validation"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemas generates the schemas of Go types, a named struct once as a component the
+// others refer to
+type schemas struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+func newSchemas() *schemas {
+	return &schemas{components: map[string]*Schema{}, names: map[reflect.Type]string{}}
+}
+
+func (s *schemas) of(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Ptr:
+		schema := s.of(t.Elem())
+		if schema.Ref != "" {
+			return &Schema{AllOf: []*Schema{schema}, Nullable: true}
+		}
+		schema.Nullable = true
+		return schema
+	case t.Kind() == reflect.Struct && t.Name() != "":
+		return &Schema{Ref: "#/components/schemas/" + s.component(t)}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return s.object(t, "json", nil)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// encoding/json writes bytes as base64
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: s.of(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.of(t.Elem())}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	}
+	// interface{}, any value
+	return &Schema{}
+}
+
+// component names the schema of t, the package is only added to tell apart two
+// types of the same name
+func (s *schemas) component(t reflect.Type) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	if _, taken := s.components[name]; taken {
+		name = path.Base(t.PkgPath()) + "." + name
+	}
+	s.names[t] = name
+	// registered before its fields so that a type referring to itself ends
+	s.components[name] = &Schema{}
+	*s.components[name] = *s.object(t, "json", nil)
+	return name
+}
+
+// object is the schema of the fields of struct t named by their tag, without those
+// skip reports. Multipart forms read files into []byte fields.
+func (s *schemas) object(t reflect.Type, tag string, skip func(field reflect.StructField) bool) *Schema {
+	object := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for _, field := range fields(t) {
+		name := fieldName(field, tag)
+		if name == "" || (skip != nil && skip(field)) {
+			continue
+		}
+		var schema *Schema
+		if tag == "form" && isBytes(field.Type) {
+			schema = &Schema{Type: "string", Format: "binary"}
+		} else {
+			schema = s.of(field.Type)
+		}
+		required, schema := constrain(schema, field.Type, field.Tag.Get("validate"))
+		if required {
+			object.Required = append(object.Required, name)
+		}
+		object.Properties[name] = schema
+	}
+	return object
+}
+
+// fields lists the exported fields of struct t, those of embedded structs included
+func fields(t reflect.Type) []reflect.StructField {
+	var list []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			list = append(list, fields(field.Type)...)
+			continue
+		}
+		if field.IsExported() {
+			list = append(list, field)
+		}
+	}
+	return list
+}
+
+// fieldName is the name of field in a body, empty when it is not read from one. A
+// form falls back to the json name like the handlers building their requests by hand.
+func fieldName(field reflect.StructField, tag string) string {
+	for _, key := range []string{tag, "json"} {
+		value, ok := field.Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+		name := strings.Split(value, ",")[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+func isBytes(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+// constrain applies the validate rules of a field of type t to its schema and reports
+// whether the field is required. The rules after dive apply to the items.
+func constrain(schema *Schema, t reflect.Type, rules string) (bool, *Schema) {
+	if rules == "" {
+		return false, schema
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	list := strings.Split(rules, ",")
+	var items []string
+	for i, rule := range list {
+		if rule == "dive" {
+			list, items = list[:i], list[i+1:]
+			break
+		}
+	}
+	if len(items) > 0 && schema.Items != nil {
+		_, schema.Items = constrain(schema.Items, t.Elem(), strings.Join(items, ","))
+	}
+
+	required := false
+	for _, rule := range list {
+		tag, param, _ := strings.Cut(rule, "=")
+		if tag == "required" {
+			required = true
+			continue
+		}
+		if tag == "omitempty" || tag == "dive" {
+			continue
+		}
+		// a $ref takes no siblings, the rules go next to it
+		if schema.Ref != "" {
+			schema = &Schema{AllOf: []*Schema{schema}}
+		}
+		applyRule(schema, t, tag, param)
+	}
+	return required, schema
+}
+
+func applyRule(schema *Schema, t reflect.Type, tag, param string) {
+	if pattern, ok := validation.Pattern(tag); ok {
+		schema.Pattern = pattern
+		return
+	}
+	number, _ := strconv.ParseFloat(param, 64)
+	size, _ := strconv.Atoi(param)
+	kind := t.Kind()
+	switch tag {
+	case "oneof":
+		for _, value := range strings.Fields(param) {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil && kind != reflect.String {
+				schema.Enum = append(schema.Enum, parsed)
+			} else {
+				schema.Enum = append(schema.Enum, value)
+			}
+		}
+	case "email":
+		schema.Format = "email"
+	case "url", "uri":
+		schema.Format = "uri"
+	case "uuid", "uuid4":
+		schema.Format = "uuid"
+	case "len":
+		setSize(schema, kind, &size, &size)
+	case "min", "gte", "gt":
+		if tag == "gt" {
+			size++
+		}
+		if isNumber(kind) {
+			schema.Minimum, schema.ExclusiveMinimum = &number, tag == "gt"
+		} else {
+			setSize(schema, kind, &size, nil)
+		}
+	case "max", "lte", "lt":
+		if tag == "lt" {
+			size--
+		}
+		if isNumber(kind) {
+			schema.Maximum, schema.ExclusiveMaximum = &number, tag == "lt"
+		} else {
+			setSize(schema, kind, nil, &size)
+		}
+	}
+}
+
+func setSize(schema *Schema, kind reflect.Kind, min, max *int) {
+	if kind == reflect.String {
+		schema.MinLength, schema.MaxLength = pick(schema.MinLength, min), pick(schema.MaxLength, max)
+	} else {
+		schema.MinItems, schema.MaxItems = pick(schema.MinItems, min), pick(schema.MaxItems, max)
+	}
+}
+
+func pick(current, value *int) *int {
+	if value != nil {
+		return value
+	}
+	return current
+}
+
+func isNumber(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
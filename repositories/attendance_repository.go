@@ -0,0 +1,191 @@
+package repositories
+
+import (
+	"context"
+	"go_starter/logs"
+	"go_starter/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AttendanceRepository interface {
+	//session
+	CreateClassroomSessionRepository(ctx context.Context, request *models.ClassroomSession) error
+	GetClassroomSessionByIdRepository(ctx context.Context, id uint) (*models.ClassroomSession, error)
+	GetClassroomSessionsByClassroomIDRepository(ctx context.Context, classroomID uint) ([]models.ClassroomSession, error)
+
+	//enrollment
+	GetEnrolledStudentsRepository(ctx context.Context, classroomID uint) ([]models.Student, error)
+	CheckStudentEnrolledRepository(ctx context.Context, classroomID, studentID uint) (bool, error)
+
+	//attendance
+	SaveAttendancesRepository(ctx context.Context, request []models.Attendance) error
+	GetAttendancesBySessionIDRepository(ctx context.Context, sessionID uint) ([]models.Attendance, error)
+	GetAttendancesByClassroomIDRepository(ctx context.Context, classroomID uint) ([]models.Attendance, error)
+	GetAttendancesByStudentIDRepository(ctx context.Context, studentID uint) ([]models.Attendance, error)
+
+	//check-in
+	// CheckInRepository records request and marks its student present unless the student
+	// already has attendance for the session, and returns that attendance. Check-ins of
+	// a session run one at a time, it returns nil when the code of request was used
+	// maxUses times already.
+	CheckInRepository(ctx context.Context, request *models.AttendanceCheckIn, maxUses int) (*models.Attendance, error)
+	CheckStudentCheckedInRepository(ctx context.Context, sessionID, studentID uint) (bool, error)
+}
+
+type attendanceRepository struct{ db *gorm.DB }
+
+func (a attendanceRepository) CreateClassroomSessionRepository(ctx context.Context, request *models.ClassroomSession) error {
+	if err := a.db.WithContext(ctx).Create(request).Error; err != nil {
+		logs.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (a attendanceRepository) GetClassroomSessionByIdRepository(ctx context.Context, id uint) (*models.ClassroomSession, error) {
+	var model models.ClassroomSession
+	if err := a.db.WithContext(ctx).Preload("Classroom").First(&model, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &model, nil
+}
+
+func (a attendanceRepository) GetClassroomSessionsByClassroomIDRepository(ctx context.Context, classroomID uint) ([]models.ClassroomSession, error) {
+	var model []models.ClassroomSession
+	err := a.db.WithContext(ctx).Where("classroom_id = ?", classroomID).Order("session_date").Find(&model).Error
+	if err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (a attendanceRepository) GetEnrolledStudentsRepository(ctx context.Context, classroomID uint) ([]models.Student, error) {
+	var model []models.Student
+	err := a.db.WithContext(ctx).Joins("JOIN student_classrooms ON student_classrooms.student_id = students.id").
+		Where("student_classrooms.classroom_id = ?", classroomID).
+		Find(&model).Error
+	if err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (a attendanceRepository) CheckStudentEnrolledRepository(ctx context.Context, classroomID, studentID uint) (bool, error) {
+	var count int64
+	query := a.db.WithContext(ctx).Model(&models.StudentClassroom{}).
+		Where("classroom_id = ? AND student_id = ?", classroomID, studentID).
+		Count(&count)
+	if query.Error != nil {
+		return false, query.Error
+	}
+	return count > 0, nil
+}
+
+func (a attendanceRepository) SaveAttendancesRepository(ctx context.Context, request []models.Attendance) error {
+	if len(request) == 0 {
+		return nil
+	}
+	// Marking the same student twice for a session overwrites the earlier status
+	query := a.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "classroom_session_id"}, {Name: "student_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "note", "marked_by", "updated_at"}),
+	}).Create(&request)
+	if query.Error != nil {
+		logs.Error(query.Error)
+		return query.Error
+	}
+	return nil
+}
+
+func (a attendanceRepository) GetAttendancesBySessionIDRepository(ctx context.Context, sessionID uint) ([]models.Attendance, error) {
+	var model []models.Attendance
+	err := a.db.WithContext(ctx).Preload("Student").Where("classroom_session_id = ?", sessionID).Find(&model).Error
+	if err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (a attendanceRepository) GetAttendancesByClassroomIDRepository(ctx context.Context, classroomID uint) ([]models.Attendance, error) {
+	var model []models.Attendance
+	err := a.db.WithContext(ctx).Preload("Student").Preload("ClassroomSession").
+		Joins("JOIN classroom_sessions ON classroom_sessions.id = attendances.classroom_session_id").
+		Where("classroom_sessions.classroom_id = ?", classroomID).
+		Find(&model).Error
+	if err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (a attendanceRepository) GetAttendancesByStudentIDRepository(ctx context.Context, studentID uint) ([]models.Attendance, error) {
+	var model []models.Attendance
+	err := a.db.WithContext(ctx).Preload("ClassroomSession.Classroom").Where("student_id = ?", studentID).Find(&model).Error
+	if err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (a attendanceRepository) CheckInRepository(ctx context.Context, request *models.AttendanceCheckIn, maxUses int) (*models.Attendance, error) {
+	var attendance *models.Attendance
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Writing the session row holds its lock until commit, so the uses counted
+		// below cannot change before this check-in is recorded
+		query := tx.Model(&models.ClassroomSession{}).Where("id = ?", request.ClassroomSessionID).
+			Update("updated_at", gorm.Expr("updated_at"))
+		if query.Error != nil {
+			return query.Error
+		}
+		if maxUses > 0 {
+			var uses int64
+			query = tx.Model(&models.AttendanceCheckIn{}).
+				Where("classroom_session_id = ? AND token_id = ?", request.ClassroomSessionID, request.TokenID).
+				Count(&uses)
+			if query.Error != nil {
+				return query.Error
+			}
+			if uses >= int64(maxUses) {
+				return nil
+			}
+		}
+		if err := tx.Create(request).Error; err != nil {
+			return err
+		}
+		// A status the teacher marked stays, the check-in only fills a missing one
+		present := models.Attendance{
+			ClassroomSessionID: request.ClassroomSessionID,
+			StudentID:          request.StudentID,
+			Status:             models.AttendancePresent,
+			Note:               "qr check-in",
+		}
+		query = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&present)
+		if query.Error != nil {
+			return query.Error
+		}
+		attendance = &models.Attendance{}
+		return tx.Where("classroom_session_id = ? AND student_id = ?", request.ClassroomSessionID, request.StudentID).
+			First(attendance).Error
+	})
+	if err != nil {
+		logs.Error(err)
+		return nil, err
+	}
+	return attendance, nil
+}
+
+func (a attendanceRepository) CheckStudentCheckedInRepository(ctx context.Context, sessionID, studentID uint) (bool, error) {
+	var count int64
+	query := a.db.WithContext(ctx).Model(&models.AttendanceCheckIn{}).
+		Where("classroom_session_id = ? AND student_id = ?", sessionID, studentID).
+		Count(&count)
+	if query.Error != nil {
+		return false, query.Error
+	}
+	return count > 0, nil
+}
+
+func NewAttendanceRepository(db *gorm.DB) AttendanceRepository {
+	return &attendanceRepository{db: db}
+}
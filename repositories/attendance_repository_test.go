@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"go_starter/errs"
+	"go_starter/models"
+	"testing"
+	"time"
+)
+
+func TestCheckInRepository(t *testing.T) {
+	db := openTestDatabase(t)
+	classroom := models.Classroom{ClassName: "CE1", ClassYear: 2024, SubjectName: "Networks", Credits: 3}
+	create(t, db, &classroom)
+	session := models.ClassroomSession{ClassroomID: classroom.ID, SessionDate: time.Now(), Topic: "Routing"}
+	students := []models.Student{
+		{StudentID: "STU001", Phone: "2055550001"},
+		{StudentID: "STU002", Phone: "2055550002"},
+		{StudentID: "STU003", Phone: "2055550003"},
+	}
+	create(t, db, &session, &students)
+	// The teacher already excused the third student
+	create(t, db, &models.Attendance{ClassroomSessionID: session.ID, StudentID: students[2].ID, Status: models.AttendanceExcused, MarkedBy: 1})
+
+	repository := NewAttendanceRepository(db)
+	ctx := context.Background()
+	checkIn := func(student models.Student, tokenID string) (*models.Attendance, error) {
+		return repository.CheckInRepository(ctx, &models.AttendanceCheckIn{
+			ClassroomSessionID: session.ID,
+			StudentID:          student.ID,
+			TokenID:            tokenID,
+		}, 1)
+	}
+
+	attendance, err := checkIn(students[0], "code-1")
+	if err != nil || attendance == nil || attendance.Status != models.AttendancePresent {
+		t.Fatalf("first check-in = %+v, %v; want present", attendance, err)
+	}
+
+	// A code is used up after max uses, a classmate has to scan the next one
+	if attendance, err = checkIn(students[1], "code-1"); err != nil || attendance != nil {
+		t.Fatalf("reused code = %+v, %v; want nil", attendance, err)
+	}
+	if attendance, err = checkIn(students[1], "code-2"); err != nil || attendance == nil || attendance.Status != models.AttendancePresent {
+		t.Fatalf("fresh code = %+v, %v; want present", attendance, err)
+	}
+
+	// A status the teacher marked is kept
+	if attendance, err = checkIn(students[2], "code-3"); err != nil || attendance == nil || attendance.Status != models.AttendanceExcused {
+		t.Fatalf("excused check-in = %+v, %v; want excused", attendance, err)
+	}
+
+	// A second check-in of the same student is translated from the unique violation
+	if _, err = checkIn(students[0], "code-4"); errs.From(err).Code != errs.CodeAlreadyCheckedIn {
+		t.Fatalf("second check-in = %v, want %s", err, errs.CodeAlreadyCheckedIn)
+	}
+
+	var count int64
+	db.Model(&models.Attendance{}).Where("classroom_session_id = ?", session.ID).Count(&count)
+	if count != 3 {
+		t.Fatalf("attendances = %d, want 3", count)
+	}
+}
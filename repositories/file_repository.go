@@ -0,0 +1,212 @@
+package repositories
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"go_starter/logs"
+	"go_starter/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type FileRepository interface {
+	// GetPhotoRepository returns the stored photo of a student, teacher or user and
+	// whether the owner exists
+	GetPhotoRepository(ctx context.Context, ownerType, ownerID string) (string, bool, error)
+	// SavePhotoRepository points the owner at object, write runs inside the same
+	// transaction so a failing write rolls the database back. It returns false when
+	// the owner does not exist.
+	SavePhotoRepository(ctx context.Context, ownerType, ownerID string, object models.FileObject, write func() error) (bool, error)
+	DeleteFileReferenceRepository(ctx context.Context, ownerType, ownerID string) error
+	// ReleaseFileObjectRepository deletes the object row when nothing references it and
+	// runs remove to delete the stored file while the row is still locked, so an upload of
+	// the same content waits and writes the file again. It reports whether it released.
+	ReleaseFileObjectRepository(ctx context.Context, key string, remove func() error) (bool, error)
+
+	//gc
+	GetFileObjectsRepository(ctx context.Context) ([]models.FileObject, error)
+	GetUnreferencedFileObjectsRepository(ctx context.Context, createdBefore time.Time) ([]models.FileObject, error)
+	GetStudentImageKeysRepository(ctx context.Context) ([]string, error)
+}
+
+type fileRepository struct{ db *gorm.DB }
+
+func (f fileRepository) GetPhotoRepository(ctx context.Context, ownerType, ownerID string) (string, bool, error) {
+	owner, err := photoOwner(f.db.WithContext(ctx), ownerType, ownerID)
+	if err != nil {
+		return "", false, err
+	}
+	var images []string
+	if err = owner.Pluck("image", &images).Error; err != nil {
+		return "", false, err
+	}
+	if len(images) == 0 {
+		return "", false, nil
+	}
+	return images[0], true, nil
+}
+
+func (f fileRepository) SavePhotoRepository(ctx context.Context, ownerType, ownerID string, object models.FileObject, write func() error) (bool, error) {
+	found := false
+	err := f.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		owner, err := photoOwner(tx, ownerType, ownerID)
+		if err != nil {
+			return err
+		}
+		query := owner.Update("image", object.StorageKey)
+		if query.Error != nil {
+			logs.Error(query.Error)
+			return query.Error
+		}
+		if query.RowsAffected == 0 {
+			return nil
+		}
+		found = true
+		reference := models.FileReference{OwnerType: ownerType, OwnerID: ownerID}
+		if err = saveFileReference(tx, &reference, &object); err != nil {
+			return err
+		}
+		// Write last so a failed write rolls everything back. A failed commit after it
+		// leaves an unreferenced file that the storage gc removes.
+		return write()
+	})
+	return found, err
+}
+
+// photoOwner selects the row whose image column holds the owner's photo, students are
+// addressed by student ID and teachers and users by primary key
+func photoOwner(db *gorm.DB, ownerType, ownerID string) (*gorm.DB, error) {
+	switch ownerType {
+	case models.FileOwnerStudentImage:
+		return db.Model(&models.Student{}).Where("student_id = ?", ownerID), nil
+	case models.FileOwnerTeacherPhoto:
+		return db.Model(&models.Teacher{}).Where("id = ?", ownerID), nil
+	case models.FileOwnerUserPhoto:
+		return db.Model(&models.User{}).Where("id = ?", ownerID), nil
+	default:
+		return nil, errors.Errorf("unknown photo owner type %q", ownerType)
+	}
+}
+
+// saveFileObject creates the object unless identical content is already stored and
+// loads its ID. The conflict updates the existing row rather than skipping it, so the
+// row stays locked until commit and ReleaseFileObjectRepository cannot remove its file
+// in between.
+func saveFileObject(tx *gorm.DB, object *models.FileObject) error {
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "storage_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"storage_key"}),
+	}).Create(object).Error
+	if err != nil {
+		logs.Error(err)
+		return err
+	}
+	var stored models.FileObject
+	if err = tx.Where("storage_key = ?", object.StorageKey).First(&stored).Error; err != nil {
+		return err
+	}
+	*object = stored
+	return nil
+}
+
+// saveFileReference saves the object and points the reference's owner at it
+func saveFileReference(tx *gorm.DB, reference *models.FileReference, object *models.FileObject) error {
+	if object.ID == 0 {
+		if err := saveFileObject(tx, object); err != nil {
+			return err
+		}
+	}
+	reference.FileObjectID = object.ID
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "owner_type"}, {Name: "owner_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"file_object_id", "updated_at"}),
+	}).Create(reference).Error
+	if err != nil {
+		logs.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (f fileRepository) DeleteFileReferenceRepository(ctx context.Context, ownerType, ownerID string) error {
+	query := f.db.WithContext(ctx).Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).Delete(&models.FileReference{})
+	if query.Error != nil {
+		logs.Error(query.Error)
+		return query.Error
+	}
+	return nil
+}
+
+func (f fileRepository) ReleaseFileObjectRepository(ctx context.Context, key string, remove func() error) (bool, error) {
+	released := false
+	err := f.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Writing the row holds its lock until commit, saveFileObject of the same key
+		// waits for it
+		query := tx.Model(&models.FileObject{}).Where("storage_key = ?", key).
+			Update("storage_key", gorm.Expr("storage_key"))
+		if query.Error != nil {
+			logs.Error(query.Error)
+			return query.Error
+		}
+		if query.RowsAffected == 0 {
+			// Files written before reference tracking have no row and a single owner
+			released = true
+			return remove()
+		}
+		var object models.FileObject
+		if err := tx.Where("storage_key = ?", key).First(&object).Error; err != nil {
+			return err
+		}
+		var references int64
+		if err := tx.Model(&models.FileReference{}).Where("file_object_id = ?", object.ID).Count(&references).Error; err != nil {
+			return err
+		}
+		if references > 0 {
+			return nil
+		}
+		if err := tx.Delete(&object).Error; err != nil {
+			logs.Error(err)
+			return err
+		}
+		released = true
+		// Remove last so a failed delete keeps the row for the storage gc
+		return remove()
+	})
+	if err != nil {
+		return false, err
+	}
+	return released, nil
+}
+
+func (f fileRepository) GetFileObjectsRepository(ctx context.Context) ([]models.FileObject, error) {
+	var model []models.FileObject
+	if err := f.db.WithContext(ctx).Find(&model).Error; err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (f fileRepository) GetUnreferencedFileObjectsRepository(ctx context.Context, createdBefore time.Time) ([]models.FileObject, error) {
+	var model []models.FileObject
+	err := f.db.WithContext(ctx).Where("created_at < ? AND NOT EXISTS (?)", createdBefore,
+		f.db.WithContext(ctx).Model(&models.FileReference{}).Select("1").Where("file_references.file_object_id = file_objects.id"),
+	).Find(&model).Error
+	if err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (f fileRepository) GetStudentImageKeysRepository(ctx context.Context) ([]string, error) {
+	var images []string
+	err := f.db.WithContext(ctx).Model(&models.Student{}).Where("image <> ''").Pluck("image", &images).Error
+	if err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+func NewFileRepository(db *gorm.DB) FileRepository {
+	return &fileRepository{db: db}
+}
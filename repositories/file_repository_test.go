@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"go_starter/models"
+	"testing"
+)
+
+func TestReleaseFileObjectRepository(t *testing.T) {
+	db := openTestDatabase(t)
+	students := []models.Student{
+		{StudentID: "STU001", Phone: "2055550001"},
+		{StudentID: "STU002", Phone: "2055550002"},
+	}
+	create(t, db, &students)
+	repository := NewFileRepository(db)
+	ctx := context.Background()
+	const key = "ceit/2024/images/ab/ab12.jpg"
+	object := models.FileObject{StorageKey: key, Hash: "ab12", Size: 4, ContentType: "image/jpeg"}
+
+	// Identical content saved for two owners shares one object
+	for _, student := range students {
+		found, err := repository.SavePhotoRepository(ctx, models.FileOwnerStudentImage, student.StudentID, object, func() error { return nil })
+		if err != nil || !found {
+			t.Fatalf("save photo of %s = %v, %v", student.StudentID, found, err)
+		}
+	}
+	var objects int64
+	db.Model(&models.FileObject{}).Count(&objects)
+	if objects != 1 {
+		t.Fatalf("objects = %d, want 1", objects)
+	}
+
+	removed := 0
+	remove := func() error {
+		removed++
+		return nil
+	}
+	released, err := repository.ReleaseFileObjectRepository(ctx, key, remove)
+	if err != nil || released || removed != 0 {
+		t.Fatalf("release of a referenced object = %v, %v, removed %d times", released, err, removed)
+	}
+
+	for _, student := range students {
+		if err = repository.DeleteFileReferenceRepository(ctx, models.FileOwnerStudentImage, student.StudentID); err != nil {
+			t.Fatalf("delete reference: %v", err)
+		}
+	}
+
+	// A failed storage delete rolls the release back, the row stays for the storage gc
+	released, err = repository.ReleaseFileObjectRepository(ctx, key, func() error { return errors.New("storage down") })
+	if err == nil || released {
+		t.Fatalf("release with failing remove = %v, %v", released, err)
+	}
+	db.Model(&models.FileObject{}).Count(&objects)
+	if objects != 1 {
+		t.Fatalf("objects after failed release = %d, want 1", objects)
+	}
+
+	released, err = repository.ReleaseFileObjectRepository(ctx, key, remove)
+	if err != nil || !released || removed != 1 {
+		t.Fatalf("release = %v, %v, removed %d times", released, err, removed)
+	}
+	db.Model(&models.FileObject{}).Count(&objects)
+	if objects != 0 {
+		t.Fatalf("objects after release = %d, want 0", objects)
+	}
+}
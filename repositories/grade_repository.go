@@ -0,0 +1,245 @@
+package repositories
+
+import (
+	"context"
+	"go_starter/errs"
+	"go_starter/logs"
+	"go_starter/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type GradeRepository interface {
+	//term
+	CreateTermRepository(ctx context.Context, request *models.Term) error
+	GetTermByIdRepository(ctx context.Context, id uint) (*models.Term, error)
+	LockTermRepository(ctx context.Context, id uint) (*models.Term, error)
+	FinalizeTermRepository(ctx context.Context, term *models.Term, grades []models.FinalGrade) error
+
+	//grading scale
+	CreateGradingScaleRepository(ctx context.Context, request *models.GradingScale) error
+	GetGradingScalesRepository(ctx context.Context) ([]models.GradingScale, error)
+	GetGradingScaleByIdRepository(ctx context.Context, id uint) (*models.GradingScale, error)
+	GetDefaultGradingScaleRepository(ctx context.Context) (*models.GradingScale, error)
+
+	//assessment
+	CreateAssessmentRepository(ctx context.Context, request *models.Assessment) error
+	GetAssessmentByIdRepository(ctx context.Context, id uint) (*models.Assessment, error)
+	GetAssessmentsRepository(ctx context.Context, classroomID, termID uint) ([]models.Assessment, error)
+	GetAssessmentsByTermIDRepository(ctx context.Context, termID uint) ([]models.Assessment, error)
+
+	//score
+	SaveAssessmentScoresRepository(ctx context.Context, termID uint, request []models.AssessmentScore) error
+	GetScoresByAssessmentIDsRepository(ctx context.Context, assessmentIDs []uint) ([]models.AssessmentScore, error)
+
+	//final grade
+	GetFinalGradesRepository(ctx context.Context, classroomID, termID uint) ([]models.FinalGrade, error)
+	GetFinalGradesByStudentIDRepository(ctx context.Context, studentID uint) ([]models.FinalGrade, error)
+}
+
+type gradeRepository struct{ db *gorm.DB }
+
+func (g gradeRepository) CreateTermRepository(ctx context.Context, request *models.Term) error {
+	if err := g.db.WithContext(ctx).Create(request).Error; err != nil {
+		logs.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (g gradeRepository) GetTermByIdRepository(ctx context.Context, id uint) (*models.Term, error) {
+	var model models.Term
+	if err := g.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &model, nil
+}
+
+// LockTermRepository reads a term and locks its row until the transaction it runs in
+// ends, run it through a unit of work. Scores of the term wait for the lock, so grades
+// computed under it are final.
+func (g gradeRepository) LockTermRepository(ctx context.Context, id uint) (*models.Term, error) {
+	return lockTerm(g.db.WithContext(ctx), id)
+}
+
+func (g gradeRepository) FinalizeTermRepository(ctx context.Context, term *models.Term, grades []models.FinalGrade) error {
+	// Grades and the finalized flag are written together so a term is never half locked
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The flag first, it locks the term and the loser of a concurrent finalize stops
+		// here instead of on the unique index of final_grades
+		finalizedAt := time.Now()
+		query := tx.Model(&models.Term{}).Where("id = ? AND finalized_at IS NULL", term.ID).Update("finalized_at", finalizedAt)
+		if query.Error != nil {
+			logs.Error(query.Error)
+			return query.Error
+		}
+		if query.RowsAffected == 0 {
+			// finalized by a concurrent request
+			return errs.New(errs.CodeTermFinalized)
+		}
+		if len(grades) > 0 {
+			if err := tx.Create(&grades).Error; err != nil {
+				logs.Error(err)
+				return err
+			}
+		}
+		term.FinalizedAt = &finalizedAt
+		return nil
+	})
+}
+
+// lockTerm locks the row of a term with a write that changes nothing, portable across
+// the drivers, and reads it under the lock
+func lockTerm(tx *gorm.DB, id uint) (*models.Term, error) {
+	query := tx.Model(&models.Term{}).Where("id = ?", id).UpdateColumn("finalized_at", gorm.Expr("finalized_at"))
+	if query.Error != nil {
+		logs.Error(query.Error)
+		return nil, query.Error
+	}
+	var term models.Term
+	if err := tx.First(&term, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &term, nil
+}
+
+func (g gradeRepository) CreateGradingScaleRepository(ctx context.Context, request *models.GradingScale) error {
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if request.IsDefault {
+			if err := tx.Model(&models.GradingScale{}).Where("is_default = ?", true).Update("is_default", false).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(request).Error; err != nil {
+			logs.Error(err)
+			return err
+		}
+		return nil
+	})
+}
+
+func (g gradeRepository) GetGradingScalesRepository(ctx context.Context) ([]models.GradingScale, error) {
+	var model []models.GradingScale
+	if err := g.db.WithContext(ctx).Preload("Grades").Find(&model).Error; err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (g gradeRepository) GetGradingScaleByIdRepository(ctx context.Context, id uint) (*models.GradingScale, error) {
+	var model models.GradingScale
+	if err := g.db.WithContext(ctx).Preload("Grades").First(&model, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &model, nil
+}
+
+func (g gradeRepository) GetDefaultGradingScaleRepository(ctx context.Context) (*models.GradingScale, error) {
+	var model models.GradingScale
+	query := g.db.WithContext(ctx).Preload("Grades").Where("is_default = ?", true).Limit(1).Find(&model)
+	if query.Error != nil {
+		return nil, query.Error
+	}
+	if query.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &model, nil
+}
+
+func (g gradeRepository) CreateAssessmentRepository(ctx context.Context, request *models.Assessment) error {
+	if err := g.db.WithContext(ctx).Create(request).Error; err != nil {
+		logs.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (g gradeRepository) GetAssessmentByIdRepository(ctx context.Context, id uint) (*models.Assessment, error) {
+	var model models.Assessment
+	if err := g.db.WithContext(ctx).Preload("Term").First(&model, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &model, nil
+}
+
+func (g gradeRepository) GetAssessmentsRepository(ctx context.Context, classroomID, termID uint) ([]models.Assessment, error) {
+	var model []models.Assessment
+	err := g.db.WithContext(ctx).Where("classroom_id = ? AND term_id = ?", classroomID, termID).Order("id").Find(&model).Error
+	if err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (g gradeRepository) GetAssessmentsByTermIDRepository(ctx context.Context, termID uint) ([]models.Assessment, error) {
+	var model []models.Assessment
+	if err := g.db.WithContext(ctx).Where("term_id = ?", termID).Order("id").Find(&model).Error; err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (g gradeRepository) SaveAssessmentScoresRepository(ctx context.Context, termID uint, request []models.AssessmentScore) error {
+	if len(request) == 0 {
+		return nil
+	}
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Checked under the lock of the term, a finalize in flight is waited for
+		term, err := lockTerm(tx, termID)
+		if err != nil {
+			return err
+		}
+		if term.FinalizedAt != nil {
+			return errs.New(errs.CodeTermFinalized)
+		}
+		// Re-entering a score for the same student replaces the earlier one
+		query := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "assessment_id"}, {Name: "student_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"score", "graded_by", "updated_at"}),
+		}).Create(&request)
+		if query.Error != nil {
+			logs.Error(query.Error)
+			return query.Error
+		}
+		return nil
+	})
+}
+
+func (g gradeRepository) GetScoresByAssessmentIDsRepository(ctx context.Context, assessmentIDs []uint) ([]models.AssessmentScore, error) {
+	var model []models.AssessmentScore
+	if len(assessmentIDs) == 0 {
+		return model, nil
+	}
+	if err := g.db.WithContext(ctx).Where("assessment_id IN ?", assessmentIDs).Find(&model).Error; err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (g gradeRepository) GetFinalGradesRepository(ctx context.Context, classroomID, termID uint) ([]models.FinalGrade, error) {
+	var model []models.FinalGrade
+	err := g.db.WithContext(ctx).Preload("Student").
+		Where("classroom_id = ? AND term_id = ?", classroomID, termID).
+		Find(&model).Error
+	if err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (g gradeRepository) GetFinalGradesByStudentIDRepository(ctx context.Context, studentID uint) ([]models.FinalGrade, error) {
+	var model []models.FinalGrade
+	err := g.db.WithContext(ctx).Preload("Classroom").Preload("Term").
+		Where("student_id = ?", studentID).
+		Order("term_id").
+		Find(&model).Error
+	if err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func NewGradeRepository(db *gorm.DB) GradeRepository {
+	return &gradeRepository{db: db}
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+	"go_starter/errs"
+	"go_starter/models"
+	"testing"
+	"time"
+)
+
+func TestFinalizeTermRepository(t *testing.T) {
+	db := openTestDatabase(t)
+	classroom := models.Classroom{ClassName: "CE1", ClassYear: 2024, SubjectName: "Networks", Credits: 3}
+	student := models.Student{StudentID: "STU001", Phone: "2055550001"}
+	term := models.Term{Name: "2024/1", StartDate: time.Now(), EndDate: time.Now().AddDate(0, 4, 0)}
+	create(t, db, &classroom, &student, &term)
+	assessment := models.Assessment{ClassroomID: classroom.ID, TermID: term.ID, Title: "Midterm", Weight: 100, MaxScore: 50}
+	create(t, db, &assessment)
+
+	repository := NewGradeRepository(db)
+	ctx := context.Background()
+	score := func(value float64) []models.AssessmentScore {
+		return []models.AssessmentScore{{AssessmentID: assessment.ID, StudentID: student.ID, Score: value, GradedBy: 1}}
+	}
+	if err := repository.SaveAssessmentScoresRepository(ctx, term.ID, score(40)); err != nil {
+		t.Fatalf("save scores: %v", err)
+	}
+
+	grades := []models.FinalGrade{{StudentID: student.ID, ClassroomID: classroom.ID, TermID: term.ID, Percentage: 80, Letter: "A", GradePoint: 4}}
+	if err := repository.FinalizeTermRepository(ctx, &term, grades); err != nil || term.FinalizedAt == nil {
+		t.Fatalf("finalize = %v, finalized at %v", err, term.FinalizedAt)
+	}
+
+	// The loser of a concurrent finalize stops at the flag, not on the unique index of
+	// final_grades
+	stale := models.Term{ID: term.ID}
+	if err := repository.FinalizeTermRepository(ctx, &stale, grades); errs.From(err).Code != errs.CodeTermFinalized {
+		t.Fatalf("second finalize = %v, want %s", err, errs.CodeTermFinalized)
+	}
+
+	// Scores are checked against the term under its lock, not against a term read before
+	if err := repository.SaveAssessmentScoresRepository(ctx, term.ID, score(10)); errs.From(err).Code != errs.CodeTermFinalized {
+		t.Fatalf("save scores after finalize = %v, want %s", err, errs.CodeTermFinalized)
+	}
+	var saved models.AssessmentScore
+	db.First(&saved, "assessment_id = ?", assessment.ID)
+	if saved.Score != 40 {
+		t.Fatalf("score = %v, want 40", saved.Score)
+	}
+}
@@ -13,7 +13,5 @@ type repository struct {
 }
 
 func NewRepository(db *gorm.DB) Repository {
-	//db.Migrator().DropTable(Table{})
-	//db.AutoMigrate(Table{})
 	return &repository{db: db}
 }
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"go_starter/logs"
+	"go_starter/models"
+	"gorm.io/gorm"
+	"strconv"
+)
+
+type StudentDocumentRepository interface {
+	// CreateStudentDocumentRepository saves the document and its file reference, write runs
+	// inside the same transaction so a failing write rolls the database back
+	CreateStudentDocumentRepository(ctx context.Context, request *models.StudentDocument, object models.FileObject, write func() error) error
+	GetStudentDocumentsRepository(ctx context.Context, studentID uint) ([]models.StudentDocument, error)
+	GetStudentDocumentByIDRepository(ctx context.Context, studentID, documentID uint) (*models.StudentDocument, error)
+	DeleteStudentDocumentRepository(ctx context.Context, request *models.StudentDocument) error
+}
+
+type studentDocumentRepository struct{ db *gorm.DB }
+
+func (s studentDocumentRepository) CreateStudentDocumentRepository(ctx context.Context, request *models.StudentDocument, object models.FileObject, write func() error) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := saveFileObject(tx, &object); err != nil {
+			return err
+		}
+		request.FileObjectID = object.ID
+		if err := tx.Omit("Student", "FileObject").Create(request).Error; err != nil {
+			logs.Error(err)
+			return err
+		}
+		reference := models.FileReference{
+			OwnerType: models.FileOwnerStudentDocument,
+			OwnerID:   strconv.FormatUint(uint64(request.ID), 10),
+		}
+		if err := saveFileReference(tx, &reference, &object); err != nil {
+			return err
+		}
+		request.FileObject = object
+		// Write last so a failed write rolls everything back
+		return write()
+	})
+}
+
+func (s studentDocumentRepository) GetStudentDocumentsRepository(ctx context.Context, studentID uint) ([]models.StudentDocument, error) {
+	var model []models.StudentDocument
+	err := s.db.WithContext(ctx).Preload("FileObject").Where("student_id = ?", studentID).Order("created_at DESC").Find(&model).Error
+	if err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (s studentDocumentRepository) GetStudentDocumentByIDRepository(ctx context.Context, studentID, documentID uint) (*models.StudentDocument, error) {
+	var model models.StudentDocument
+	query := s.db.WithContext(ctx).Preload("FileObject").Where("id = ? AND student_id = ?", documentID, studentID).Limit(1).Find(&model)
+	if query.Error != nil {
+		return nil, query.Error
+	}
+	if query.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &model, nil
+}
+
+func (s studentDocumentRepository) DeleteStudentDocumentRepository(ctx context.Context, request *models.StudentDocument) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("owner_type = ? AND owner_id = ?", models.FileOwnerStudentDocument, strconv.FormatUint(uint64(request.ID), 10)).
+			Delete(&models.FileReference{}).Error
+		if err != nil {
+			logs.Error(err)
+			return err
+		}
+		if err = tx.Delete(&models.StudentDocument{}, request.ID).Error; err != nil {
+			logs.Error(err)
+			return err
+		}
+		return nil
+	})
+}
+
+func NewStudentDocumentRepository(db *gorm.DB) StudentDocumentRepository {
+	return &studentDocumentRepository{db: db}
+}
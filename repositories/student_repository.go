@@ -1,7 +1,9 @@
 package repositories
 
 import (
+	"context"
 	"github.com/pkg/errors"
+	"go_starter/errs"
 	"go_starter/logs"
 	"go_starter/models"
 	"gorm.io/gorm"
@@ -9,38 +11,45 @@ import (
 )
 
 type StudentRepository interface {
-	GetStudentClassroomByClassroomIDRepository(classroomID uint) ([]models.StudentClassroom, error)
+	GetStudentClassroomByClassroomIDRepository(ctx context.Context, classroomID uint) ([]models.StudentClassroom, error)
+
+	//classrooms, the batch lookups load a relation for many parents in one query
+	GetClassroomsRepository(ctx context.Context) ([]models.Classroom, error)
+	GetClassroomByIDRepository(ctx context.Context, id uint) (*models.Classroom, error)
+	GetStudentClassroomsByClassroomIDsRepository(ctx context.Context, classroomIDs []uint) ([]models.StudentClassroom, error)
+	GetStudentClassroomsByStudentIDsRepository(ctx context.Context, studentIDs []uint) ([]models.StudentClassroom, error)
 
 	//
-	GetTeacherByPhoneRepository(phone string) (*models.Teacher, error)
-	GetStudentByPhoneRepository(phone string) (*models.Student, error)
+	GetTeacherByPhoneRepository(ctx context.Context, phone string) (*models.Teacher, error)
+	GetTeacherByIDRepository(ctx context.Context, id uint) (*models.Teacher, error)
+	GetStudentByPhoneRepository(ctx context.Context, phone string) (*models.Student, error)
 
 	//
-	SignUpForTeacherRepository(request models.Teacher) (*models.Teacher, error)
-	SignUpForStudentRepository(request models.Student) (*models.Student, error)
+	SignUpForTeacherRepository(ctx context.Context, request models.Teacher) (*models.Teacher, error)
+	SignUpForStudentRepository(ctx context.Context, request models.Student) (*models.Student, error)
 
 	//
-	GetStudentsRepository() ([]models.Student, error)
-	GetStudentByIdRepository(id int) (*models.Student, error)
-	GetStudentByStudentIdRepository(studentID string) (*models.Student, error)
-	CreateStudentRepository(request *models.Student) error
-	UpdateStudentRepository(request *models.Student) error
-	DeleteStudentByStudentIDRepository(studentID string) error
+	GetStudentsRepository(ctx context.Context) ([]models.Student, error)
+	GetStudentByIdRepository(ctx context.Context, id int) (*models.Student, error)
+	GetStudentByStudentIdRepository(ctx context.Context, studentID string) (*models.Student, error)
+	CreateStudentRepository(ctx context.Context, request *models.Student) error
+	UpdateStudentRepository(ctx context.Context, request *models.Student) error
+	DeleteStudentByStudentIDRepository(ctx context.Context, studentID string) error
 
 	//
-	CheckTeacherPhoneAlreadyHas(phone string) (bool, error)
-	CheckStudentPhoneAlreadyHas(phone string) (bool, error)
-	CheckStudentIDAlreadyHas(studentID string) (bool, error)
+	CheckTeacherPhoneAlreadyHas(ctx context.Context, phone string) (bool, error)
+	CheckStudentPhoneAlreadyHas(ctx context.Context, phone string) (bool, error)
+	CheckStudentIDAlreadyHas(ctx context.Context, studentID string) (bool, error)
 
 	//image
-	GetStudentImageRepository(studentID string) (string, error)
-	UpdateStudentImageRepository(request *models.Student) error
-	DeleteStudentImageRepository(studentID string) error
+	GetStudentImageRepository(ctx context.Context, studentID string) (string, error)
+	UpdateStudentImageRepository(ctx context.Context, request *models.Student) error
+	DeleteStudentImageRepository(ctx context.Context, studentID string) error
 }
 
 type studentRepository struct{ db *gorm.DB }
 
-func (s studentRepository) GetStudentClassroomByClassroomIDRepository(classroomID uint) ([]models.StudentClassroom, error) {
+func (s studentRepository) GetStudentClassroomByClassroomIDRepository(ctx context.Context, classroomID uint) ([]models.StudentClassroom, error) {
 	//var model []models.StudentClassroom
 	//err := s.db.Where("id", classroomID).Find(&model).Error
 	//if err != nil {
@@ -51,7 +60,7 @@ func (s studentRepository) GetStudentClassroomByClassroomIDRepository(classroomI
 	var studentClassrooms []models.StudentClassroom
 
 	// Join query to fetch StudentClassroom with related Student and Classroom
-	err := s.db.Preload("Student").Preload("Classroom").
+	err := s.db.WithContext(ctx).Preload("Student").Preload("Classroom").
 		Where("classroom_id = ?", classroomID).
 		Find(&studentClassrooms).Error
 
@@ -61,34 +70,96 @@ func (s studentRepository) GetStudentClassroomByClassroomIDRepository(classroomI
 	return studentClassrooms, nil
 }
 
+func (s studentRepository) GetClassroomsRepository(ctx context.Context) ([]models.Classroom, error) {
+	var classrooms []models.Classroom
+	if err := s.db.WithContext(ctx).Order("id").Find(&classrooms).Error; err != nil {
+		return nil, err
+	}
+	return classrooms, nil
+}
+
+func (s studentRepository) GetClassroomByIDRepository(ctx context.Context, id uint) (*models.Classroom, error) {
+	var classroom models.Classroom
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&classroom).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errs.New(errs.CodeClassroomNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &classroom, nil
+}
+
+func (s studentRepository) GetStudentClassroomsByClassroomIDsRepository(ctx context.Context, classroomIDs []uint) ([]models.StudentClassroom, error) {
+	var studentClassrooms []models.StudentClassroom
+	err := s.db.WithContext(ctx).Preload("Student").
+		Where("classroom_id IN ?", classroomIDs).
+		Order("id").
+		Find(&studentClassrooms).Error
+	if err != nil {
+		return nil, err
+	}
+	return studentClassrooms, nil
+}
+
+func (s studentRepository) GetStudentClassroomsByStudentIDsRepository(ctx context.Context, studentIDs []uint) ([]models.StudentClassroom, error) {
+	var studentClassrooms []models.StudentClassroom
+	err := s.db.WithContext(ctx).Preload("Classroom").
+		Where("student_id IN ?", studentIDs).
+		Order("id").
+		Find(&studentClassrooms).Error
+	if err != nil {
+		return nil, err
+	}
+	return studentClassrooms, nil
+}
+
 //-----------------------------------------new---------------------------------------------------//
 
-func (s studentRepository) GetTeacherByPhoneRepository(phone string) (*models.Teacher, error) {
+func (s studentRepository) GetTeacherByPhoneRepository(ctx context.Context, phone string) (*models.Teacher, error) {
+	var model models.Teacher
+	query := s.db.WithContext(ctx).First(&model, "phone = ?", phone)
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if query.Error != nil {
+		return nil, query.Error
+	}
+	return &model, nil
+}
+
+func (s studentRepository) GetTeacherByIDRepository(ctx context.Context, id uint) (*models.Teacher, error) {
 	var model models.Teacher
-	query := s.db.First(&model, "phone = ?", phone)
+	query := s.db.WithContext(ctx).Limit(1).Find(&model, "id = ?", id)
 	if query.Error != nil {
+		return nil, query.Error
+	}
+	if query.RowsAffected == 0 {
 		return nil, nil
 	}
 	return &model, nil
 }
 
-func (s studentRepository) GetStudentByPhoneRepository(phone string) (*models.Student, error) {
+func (s studentRepository) GetStudentByPhoneRepository(ctx context.Context, phone string) (*models.Student, error) {
 	var model models.Student
-	query := s.db.First(&model, "phone = ?", phone)
-	if query.Error != nil {
+	query := s.db.WithContext(ctx).First(&model, "phone = ?", phone)
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
+	if query.Error != nil {
+		return nil, query.Error
+	}
 	return &model, nil
 }
 
-func (s studentRepository) SignUpForTeacherRepository(request models.Teacher) (*models.Teacher, error) {
+func (s studentRepository) SignUpForTeacherRepository(ctx context.Context, request models.Teacher) (*models.Teacher, error) {
 	request = models.Teacher{
 		ID:       request.ID,
 		Phone:    request.Phone,
 		Password: request.Password,
 		Token:    request.Token,
 	}
-	create := s.db.Create(&request)
+	create := s.db.WithContext(ctx).Create(&request)
 	if create.Error != nil {
 		logs.Error(create.Error)
 		return nil, create.Error
@@ -96,7 +167,7 @@ func (s studentRepository) SignUpForTeacherRepository(request models.Teacher) (*
 	return &request, nil
 }
 
-func (s studentRepository) SignUpForStudentRepository(request models.Student) (*models.Student, error) {
+func (s studentRepository) SignUpForStudentRepository(ctx context.Context, request models.Student) (*models.Student, error) {
 	//select specific that you want to insert
 	request = models.Student{
 		ID:       request.ID,
@@ -104,7 +175,7 @@ func (s studentRepository) SignUpForStudentRepository(request models.Student) (*
 		Password: request.Password,
 		Token:    request.Token,
 	}
-	create := s.db.Create(&request)
+	create := s.db.WithContext(ctx).Create(&request)
 	if create.Error != nil {
 		logs.Error(create.Error)
 		return nil, create.Error
@@ -114,99 +185,97 @@ func (s studentRepository) SignUpForStudentRepository(request models.Student) (*
 
 //---------------------------------------------------------------------------------------//
 
-func (s studentRepository) GetStudentImageRepository(studentID string) (string, error) {
-	var image string
-	query := s.db.Raw("SELECT image FROM students WHERE student_id = ?", studentID).Scan(&image)
+func (s studentRepository) GetStudentImageRepository(ctx context.Context, studentID string) (string, error) {
+	var model models.Student
+	query := s.db.WithContext(ctx).Select("image").Where("student_id = ?", studentID).Limit(1).Find(&model)
 	if query.Error != nil {
 		return "", query.Error
 	}
-	return image, nil
+	return model.Image, nil
 }
 
-func (s studentRepository) UpdateStudentImageRepository(request *models.Student) error {
-	query := s.db.Model(&models.Student{}).Where("student_id = ?", request.StudentID).Update("image", request.Image)
+func (s studentRepository) UpdateStudentImageRepository(ctx context.Context, request *models.Student) error {
+	query := s.db.WithContext(ctx).Model(&models.Student{}).Where("student_id = ?", request.StudentID).Update("image", request.Image)
 	if query.Error != nil {
 		return nil
 	}
 	return nil
 }
 
-func (s studentRepository) DeleteStudentImageRepository(studentID string) error {
-	query := s.db.Model(&models.Student{}).Where("student_id = ?", studentID).Update("image", nil)
+func (s studentRepository) DeleteStudentImageRepository(ctx context.Context, studentID string) error {
+	query := s.db.WithContext(ctx).Model(&models.Student{}).Where("student_id = ?", studentID).Update("image", nil)
 	if query.Error != nil {
 		return query.Error
 	}
 	return nil
 }
 
-func (s studentRepository) GetStudentByIdRepository(id int) (*models.Student, error) {
+func (s studentRepository) GetStudentByIdRepository(ctx context.Context, id int) (*models.Student, error) {
 	var model models.Student
 
-	// Execute raw SQL query
-	query := s.db.Raw("SELECT * FROM students WHERE id = ?", id).Scan(&model).Error
+	query := s.db.WithContext(ctx).Where("id = ?", id).Limit(1).Find(&model).Error
 	if query != nil {
 		return nil, query
 	}
 	return &model, nil
 }
 
-func (s studentRepository) CheckStudentIDAlreadyHas(studentID string) (bool, error) {
+func (s studentRepository) CheckStudentIDAlreadyHas(ctx context.Context, studentID string) (bool, error) {
 	var count int64
 	// Convert studentID to uppercase for comparison
 	upperStudentID := strings.ToUpper(studentID)
 	// Perform a case-insensitive comparison
-	query := s.db.Model(&models.Student{}).Where("UPPER(student_id) = ?", upperStudentID).Count(&count)
+	query := s.db.WithContext(ctx).Model(&models.Student{}).Where("UPPER(student_id) = ?", upperStudentID).Count(&count)
 	if query.Error != nil {
 		return false, query.Error
 	}
 	return count > 0, nil // Return true if count is greater than 0, indicating student ID exists
 }
 
-func (s studentRepository) CheckTeacherPhoneAlreadyHas(phone string) (bool, error) {
+func (s studentRepository) CheckTeacherPhoneAlreadyHas(ctx context.Context, phone string) (bool, error) {
 	var count int64
-	query := s.db.Model(&models.Teacher{}).Where("phone = ?", phone).Count(&count)
+	query := s.db.WithContext(ctx).Model(&models.Teacher{}).Where("phone = ?", phone).Count(&count)
 	if query.Error != nil {
 		return false, query.Error
 	}
 	return count > 0, nil
 }
 
-func (s studentRepository) CheckStudentPhoneAlreadyHas(phone string) (bool, error) {
+func (s studentRepository) CheckStudentPhoneAlreadyHas(ctx context.Context, phone string) (bool, error) {
 	var count int64
-	query := s.db.Model(&models.Student{}).Where("phone = ?", phone).Count(&count)
+	query := s.db.WithContext(ctx).Model(&models.Student{}).Where("phone = ?", phone).Count(&count)
 	if query.Error != nil {
 		return false, query.Error
 	}
 	return count > 0, nil
 }
 
-func (s studentRepository) GetStudentsRepository() ([]models.Student, error) {
+func (s studentRepository) GetStudentsRepository(ctx context.Context) ([]models.Student, error) {
 	var model []models.Student
-	query := s.db.Find(&model).Error
+	query := s.db.WithContext(ctx).Find(&model).Error
 	if query != nil {
 		return nil, query
 	}
 	return model, nil
 }
 
-func (s studentRepository) GetStudentByStudentIdRepository(studentID string) (*models.Student, error) {
+func (s studentRepository) GetStudentByStudentIdRepository(ctx context.Context, studentID string) (*models.Student, error) {
 	var model models.Student
 
-	// Execute raw SQL query
-	query := s.db.Raw("SELECT * FROM students WHERE student_id = ?", studentID).Scan(&model).Error
+	query := s.db.WithContext(ctx).Where("student_id = ?", studentID).Limit(1).Find(&model).Error
 	if query != nil {
 		return nil, query
 	}
 	return &model, nil
 }
 
-func (s studentRepository) CreateStudentRepository(model *models.Student) error {
-	if err := s.db.Create(model).Error; err != nil {
+func (s studentRepository) CreateStudentRepository(ctx context.Context, model *models.Student) error {
+	if err := s.db.WithContext(ctx).Create(model).Error; err != nil {
 		return err
 	}
 	return nil
 }
-func (s studentRepository) UpdateStudentRepository(request *models.Student) error {
+func (s studentRepository) UpdateStudentRepository(ctx context.Context, request *models.Student) error {
 	// raw function no check data
 	//query := s.db.Model(&models.Student{}).Where("student_id =?", request.StudentID).Updates(request)
 	//if query.Error != nil {
@@ -215,17 +284,17 @@ func (s studentRepository) UpdateStudentRepository(request *models.Student) erro
 	//return nil
 
 	// add check student_id on database
-	query := s.db.Model(&models.Student{}).Where("student_id = ?", request.StudentID).Updates(request)
+	query := s.db.WithContext(ctx).Model(&models.Student{}).Where("student_id = ?", request.StudentID).Updates(request)
 	if query.Error != nil {
 		return query.Error
 	}
 	if query.RowsAffected == 0 {
-		return errors.New("no student_id found")
+		return errs.New(errs.CodeStudentNotFound)
 	}
 	return nil
 }
 
-func (s studentRepository) DeleteStudentByStudentIDRepository(studentID string) error {
+func (s studentRepository) DeleteStudentByStudentIDRepository(ctx context.Context, studentID string) error {
 	// raw function no check data
 	//	query := models.Student{StudentID: studentID}
 	//	if err := s.db.Where("student_id = ?", studentID).Delete(&query).Error; err != nil {
@@ -235,23 +304,20 @@ func (s studentRepository) DeleteStudentByStudentIDRepository(studentID string)
 
 	// add check student_id on database
 	var count int64
-	if err := s.db.Model(&models.Student{}).Where("student_id = ?", studentID).Count(&count).Error; err != nil {
+	if err := s.db.WithContext(ctx).Model(&models.Student{}).Where("student_id = ?", studentID).Count(&count).Error; err != nil {
 		return err
 	}
 	if count == 0 {
-		return errors.New("student not found")
+		return errs.New(errs.CodeStudentNotFound)
 	}
 
 	// Delete the student
-	if err := s.db.Where("student_id = ?", studentID).Delete(&models.Student{}).Error; err != nil {
+	if err := s.db.WithContext(ctx).Where("student_id = ?", studentID).Delete(&models.Student{}).Error; err != nil {
 		return err
 	}
 	return nil
 }
 
 func NewStudentRepository(db *gorm.DB) StudentRepository {
-	//db.Migrator().DropTable(models.Student{})
-	//db.AutoMigrate(models.Classroom{})
-	//db.AutoMigrate(models.StudentClassroom{})
 	return &studentRepository{db: db}
 }
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"context"
+	"go_starter/logs"
+	"go_starter/models"
+	"gorm.io/gorm"
+)
+
+type TranscriptRepository interface {
+	CreateTranscriptRepository(ctx context.Context, request *models.Transcript) error
+	GetTranscriptBySerialNoRepository(ctx context.Context, serialNo string) (*models.Transcript, error)
+	GetTranscriptByDigestRepository(ctx context.Context, digest string) (*models.Transcript, error)
+}
+
+type transcriptRepository struct{ db *gorm.DB }
+
+func (t transcriptRepository) CreateTranscriptRepository(ctx context.Context, request *models.Transcript) error {
+	if err := t.db.WithContext(ctx).Create(request).Error; err != nil {
+		logs.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (t transcriptRepository) GetTranscriptBySerialNoRepository(ctx context.Context, serialNo string) (*models.Transcript, error) {
+	var model models.Transcript
+	if err := t.db.WithContext(ctx).Preload("Student").First(&model, "serial_no = ?", serialNo).Error; err != nil {
+		return nil, err
+	}
+	return &model, nil
+}
+
+func (t transcriptRepository) GetTranscriptByDigestRepository(ctx context.Context, digest string) (*models.Transcript, error) {
+	var model models.Transcript
+	if err := t.db.WithContext(ctx).Preload("Student").First(&model, "digest = ?", digest).Error; err != nil {
+		return nil, err
+	}
+	return &model, nil
+}
+
+func NewTranscriptRepository(db *gorm.DB) TranscriptRepository {
+	return &transcriptRepository{db: db}
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"go_starter/logs"
+	"gorm.io/gorm"
+)
+
+// TransactionRepositories are the repositories a unit of work hands to its function,
+// every call made through them runs in the same transaction
+type TransactionRepositories struct {
+	Student StudentRepository
+	User    UserRepository
+	File    FileRepository
+	Grade   GradeRepository
+}
+
+type UnitOfWork interface {
+	// Do runs fn in one database transaction bound to ctx. It commits when fn returns
+	// nil and rolls back when fn returns an error, panics or ctx is cancelled. Repository
+	// methods opening their own transaction nest inside it as savepoints.
+	Do(ctx context.Context, fn func(repositories TransactionRepositories) error) error
+}
+
+type unitOfWork struct {
+	db *gorm.DB
+}
+
+func (u unitOfWork) Do(ctx context.Context, fn func(repositories TransactionRepositories) error) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
+		// A panic rolls the transaction back and reaches the caller as an error
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err = fmt.Errorf("transaction rolled back after panic: %v", recovered)
+				logs.Error(err)
+			}
+		}()
+		return fn(TransactionRepositories{
+			Student: NewStudentRepository(tx),
+			User:    NewUserRepository(tx),
+			File:    NewFileRepository(tx),
+			Grade:   NewGradeRepository(tx),
+		})
+	})
+}
+
+func NewUnitOfWork(db *gorm.DB) UnitOfWork {
+	return &unitOfWork{db: db}
+}
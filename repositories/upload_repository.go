@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"context"
+	"go_starter/logs"
+	"go_starter/models"
+	"gorm.io/gorm"
+	"time"
+)
+
+type UploadRepository interface {
+	CreateUploadSessionRepository(ctx context.Context, request *models.UploadSession) error
+	GetUploadSessionRepository(ctx context.Context, id string) (*models.UploadSession, error)
+	// AppendUploadChunkRepository records the chunk and moves the offset past it, it
+	// returns false when another request already moved the offset
+	AppendUploadChunkRepository(ctx context.Context, request *models.UploadChunk, expiresAt time.Time) (bool, error)
+	CompleteUploadSessionRepository(ctx context.Context, id, storageKey string) error
+	DeleteUploadSessionRepository(ctx context.Context, id string) error
+	GetExpiredUploadSessionsRepository(ctx context.Context, now time.Time) ([]models.UploadSession, error)
+}
+
+type uploadRepository struct{ db *gorm.DB }
+
+func (u uploadRepository) CreateUploadSessionRepository(ctx context.Context, request *models.UploadSession) error {
+	if err := u.db.WithContext(ctx).Create(request).Error; err != nil {
+		logs.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (u uploadRepository) GetUploadSessionRepository(ctx context.Context, id string) (*models.UploadSession, error) {
+	var model models.UploadSession
+	query := u.db.WithContext(ctx).Preload("Chunks", func(db *gorm.DB) *gorm.DB {
+		return db.Order("chunk_offset")
+	}).Where("id = ?", id).Limit(1).Find(&model)
+	if query.Error != nil {
+		return nil, query.Error
+	}
+	if query.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &model, nil
+}
+
+func (u uploadRepository) AppendUploadChunkRepository(ctx context.Context, request *models.UploadChunk, expiresAt time.Time) (bool, error) {
+	appended := false
+	err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Only the request that still sees the expected offset may move it
+		query := tx.Model(&models.UploadSession{}).
+			Where("id = ? AND upload_offset = ? AND status = ?", request.UploadSessionID, request.Offset, models.UploadPending).
+			Updates(map[string]interface{}{
+				"upload_offset": gorm.Expr("upload_offset + ?", request.Size),
+				"expires_at":    expiresAt,
+				"updated_at":    time.Now(),
+			})
+		if query.Error != nil {
+			logs.Error(query.Error)
+			return query.Error
+		}
+		if query.RowsAffected == 0 {
+			return nil
+		}
+		if err := tx.Create(request).Error; err != nil {
+			logs.Error(err)
+			return err
+		}
+		appended = true
+		return nil
+	})
+	return appended, err
+}
+
+func (u uploadRepository) CompleteUploadSessionRepository(ctx context.Context, id, storageKey string) error {
+	query := u.db.WithContext(ctx).Model(&models.UploadSession{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":      models.UploadCompleted,
+		"storage_key": storageKey,
+	})
+	if query.Error != nil {
+		logs.Error(query.Error)
+		return query.Error
+	}
+	return nil
+}
+
+func (u uploadRepository) DeleteUploadSessionRepository(ctx context.Context, id string) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("upload_session_id = ?", id).Delete(&models.UploadChunk{}).Error; err != nil {
+			logs.Error(err)
+			return err
+		}
+		if err := tx.Where("id = ?", id).Delete(&models.UploadSession{}).Error; err != nil {
+			logs.Error(err)
+			return err
+		}
+		return nil
+	})
+}
+
+func (u uploadRepository) GetExpiredUploadSessionsRepository(ctx context.Context, now time.Time) ([]models.UploadSession, error) {
+	var model []models.UploadSession
+	if err := u.db.WithContext(ctx).Preload("Chunks").Where("expires_at < ?", now).Find(&model).Error; err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func NewUploadRepository(db *gorm.DB) UploadRepository {
+	return &uploadRepository{db: db}
+}
@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+	"go_starter/errs"
 	"go_starter/logs"
 	"go_starter/models"
 
@@ -10,30 +12,30 @@ import (
 
 type UserRepository interface {
 	//Login
-	SignUpUserRepository(request models.User) (*models.User, error)
+	SignUpUserRepository(ctx context.Context, request models.User) (*models.User, error)
 
 	//CRUD
-	CreateUserRepository(request *models.User) error
-	GetAllUserRepository() ([]models.User, error)
-	GetByIdUserRepository(id uint) (*models.User, error)
-	GetByPhoneRepository(phone string) (*models.User, error)
-	GetByEmailRepository(email string) (*models.User, error)
-	UpdateUserRepository(request *models.User) error
-	DeleteUserRepository(id uint) error
+	CreateUserRepository(ctx context.Context, request *models.User) error
+	GetAllUserRepository(ctx context.Context) ([]models.User, error)
+	GetByIdUserRepository(ctx context.Context, id uint) (*models.User, error)
+	GetByPhoneRepository(ctx context.Context, phone string) (*models.User, error)
+	GetByEmailRepository(ctx context.Context, email string) (*models.User, error)
+	UpdateUserRepository(ctx context.Context, request *models.User) error
+	DeleteUserRepository(ctx context.Context, id uint) error
 
 	//Check UserName and Check Phone
-	CheckEmailAlreadyHas(request models.User) (*models.User, error)
+	CheckEmailAlreadyHas(ctx context.Context, request models.User) (*models.User, error)
 	//CheckPhoneAlreadyHas(phone string) (bool, error)
 }
 
 type userRepository struct{ db *gorm.DB }
 
 // CheckEmailAlreadyHas implements UserRepository.
-func (u *userRepository) CheckEmailAlreadyHas(request models.User) (*models.User, error) {
+func (u *userRepository) CheckEmailAlreadyHas(ctx context.Context, request models.User) (*models.User, error) {
 
 	var model models.User
-	result := u.db.Where("email = ?", request.Email).First(&model)
-	if result.Error != nil && result.Error != gorm.ErrRecordNotFound {
+	result := u.db.WithContext(ctx).Where("email = ?", request.Email).First(&model)
+	if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, result.Error
 	}
 	if result.RowsAffected > 0 {
@@ -55,9 +57,9 @@ func (u *userRepository) CheckEmailAlreadyHas(request models.User) (*models.User
 // }
 
 // SignUpUserRepository implements UserRepository.
-func (u *userRepository) SignUpUserRepository(request models.User) (*models.User, error) {
+func (u *userRepository) SignUpUserRepository(ctx context.Context, request models.User) (*models.User, error) {
 
-	create := u.db.Create(&request)
+	create := u.db.WithContext(ctx).Create(&request)
 	if create.Error != nil {
 		logs.Error(create.Error)
 		return nil, create.Error
@@ -66,20 +68,20 @@ func (u *userRepository) SignUpUserRepository(request models.User) (*models.User
 }
 
 // CreateUserRepository implements UserRepository.
-func (u *userRepository) CreateUserRepository(request *models.User) error {
+func (u *userRepository) CreateUserRepository(ctx context.Context, request *models.User) error {
 
-	if err := u.db.Create(request).Error; err != nil {
+	if err := u.db.WithContext(ctx).Create(request).Error; err != nil {
 		return err
 	}
 	return nil
 }
 
 // DeleteUserRepository implements UserRepository.
-func (u *userRepository) DeleteUserRepository(id uint) error {
+func (u *userRepository) DeleteUserRepository(ctx context.Context, id uint) error {
 
 	query := models.User{ID: id}
 
-	if err := u.db.Where("id = ?", id).Delete(&query).Error; err != nil {
+	if err := u.db.WithContext(ctx).Where("id = ?", id).Delete(&query).Error; err != nil {
 		return err
 	}
 
@@ -87,10 +89,10 @@ func (u *userRepository) DeleteUserRepository(id uint) error {
 }
 
 // GetAllUserRepository implements UserRepository.
-func (u *userRepository) GetAllUserRepository() ([]models.User, error) {
+func (u *userRepository) GetAllUserRepository(ctx context.Context) ([]models.User, error) {
 
 	var model []models.User
-	query := u.db.Find(&model).Error
+	query := u.db.WithContext(ctx).Find(&model).Error
 	if query != nil {
 		return nil, query
 	}
@@ -98,55 +100,59 @@ func (u *userRepository) GetAllUserRepository() ([]models.User, error) {
 }
 
 // GetByIdUserRepository implements UserRepository.
-func (u *userRepository) GetByIdUserRepository(id uint) (*models.User, error) {
+func (u *userRepository) GetByIdUserRepository(ctx context.Context, id uint) (*models.User, error) {
 
 	var model models.User
-	if err := u.db.Where("id =?", id).First(&model).Error; err != nil {
+	if err := u.db.WithContext(ctx).Where("id =?", id).First(&model).Error; err != nil {
 		return nil, err
 	}
 	return &model, nil
 }
 
 // GetByUserNameRepository implements UserRepository.
-func (u *userRepository) GetByPhoneRepository(phone string) (*models.User, error) {
+func (u *userRepository) GetByPhoneRepository(ctx context.Context, phone string) (*models.User, error) {
 
 	var model models.User
-	query := u.db.First(&model, "phone =?", phone)
+	query := u.db.WithContext(ctx).First(&model, "phone =?", phone)
 
-	if query.Error != nil {
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
+	if query.Error != nil {
+		return nil, query.Error
+	}
 	return &model, nil
 }
 
 // GetByEmailRepository implements UserRepository.
-func (u *userRepository) GetByEmailRepository(email string) (*models.User, error) {
+func (u *userRepository) GetByEmailRepository(ctx context.Context, email string) (*models.User, error) {
 
 	var model models.User
-	query := u.db.First(&model, "email =?", email)
+	query := u.db.WithContext(ctx).First(&model, "email =?", email)
 
-	if query.Error != nil {
+	if errors.Is(query.Error, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
+	if query.Error != nil {
+		return nil, query.Error
+	}
 	return &model, nil
 }
 
 // UpdateUserRepository implements UserRepository.
-func (u *userRepository) UpdateUserRepository(request *models.User) error {
+func (u *userRepository) UpdateUserRepository(ctx context.Context, request *models.User) error {
 
-	query := u.db.Model(&models.User{}).Where("id = ?", request.ID).Updates(request)
+	query := u.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", request.ID).Updates(request)
 
 	if query.Error != nil {
 		return query.Error
 	}
 	if query.RowsAffected == 0 {
-		return errors.New("not id found")
+		return errs.New(errs.CodeUserNotFound)
 	}
 	return nil
 }
 
 func NewUserRepository(db *gorm.DB) UserRepository {
-	//db.Migrator().DropTable(&models.User{})
-	//db.AutoMigrate(&models.User{})
 	return &userRepository{db: db}
 }
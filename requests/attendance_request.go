@@ -0,0 +1,48 @@
+package requests
+
+type ClassroomSessionRequest struct {
+	ClassroomID uint   `json:"classroom_id" validate:"required"`
+	SessionDate string `json:"session_date" validate:"required,date"`
+	Topic       string `json:"topic"`
+}
+
+type ClassroomSessionIDRequest struct {
+	SessionID uint `json:"session_id" validate:"required"`
+}
+
+// CheckInCodeRequest mints the check-in code of a session for the teacher the access
+// token was issued to
+type CheckInCodeRequest struct {
+	SessionID   uint   `json:"session_id" validate:"required"`
+	AccessToken string `json:"-" validate:"required"`
+}
+
+type AttendanceRecordRequest struct {
+	StudentID uint   `json:"student_id" validate:"required"`
+	Status    string `json:"status" validate:"required,oneof=present absent late excused"`
+	Note      string `json:"note"`
+}
+
+// BulkAttendanceRequest marks a whole session at once. Enrolled students missing
+// from Records are marked with DefaultStatus when it is set. The marks are recorded
+// as the teacher the access token was issued to.
+type BulkAttendanceRequest struct {
+	SessionID     uint                      `json:"session_id" validate:"required"`
+	DefaultStatus string                    `json:"default_status" validate:"omitempty,oneof=present absent late excused"`
+	Records       []AttendanceRecordRequest `json:"records" validate:"dive"`
+	AccessToken   string                    `json:"-" validate:"required"`
+}
+
+type StudentAttendanceRequest struct {
+	StudentID uint `json:"student_id" validate:"required"`
+}
+
+type AttendanceAlertRequest struct {
+	ClassroomID uint    `json:"classroom_id" validate:"required"`
+	Threshold   float64 `json:"threshold" validate:"omitempty,gt=0,lte=100"`
+}
+
+type CheckInRequest struct {
+	Code        string `json:"code" validate:"required"`
+	AccessToken string `json:"-" validate:"required"`
+}
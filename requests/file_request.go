@@ -0,0 +1,26 @@
+package requests
+
+import "time"
+
+// ImageRequest is authorized either by a signed URL (expires, signature) or a Bearer access token
+type ImageRequest struct {
+	Key         string `json:"-"`
+	Width       int    `query:"w"`
+	Expires     string `query:"expires"`
+	Signature   string `query:"signature"`
+	AccessToken string `json:"-"`
+}
+
+// StorageGCRequest reconciles stored files against the database, files younger than
+// GracePeriod are kept because their upload may still be committing
+type StorageGCRequest struct {
+	DryRun      bool
+	GracePeriod time.Duration
+}
+
+// PhotoRequest uploads the photo of a student, teacher or user, see models.FileOwner*
+type PhotoRequest struct {
+	OwnerType string `json:"-" validate:"required"`
+	OwnerID   string `json:"-" validate:"required"`
+	Image     []byte `json:"-" validate:"required"`
+}
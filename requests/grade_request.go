@@ -0,0 +1,55 @@
+package requests
+
+type TermRequest struct {
+	Name           string `json:"name" validate:"required"`
+	StartDate      string `json:"start_date" validate:"required,date"`
+	EndDate        string `json:"end_date" validate:"required,date"`
+	GradingScaleID uint   `json:"grading_scale_id"`
+}
+
+type TermIDRequest struct {
+	TermID uint `json:"term_id" validate:"required"`
+}
+
+type GradeBoundaryRequest struct {
+	Letter        string  `json:"letter" validate:"required"`
+	MinPercentage float64 `json:"min_percentage" validate:"gte=0,lte=100"`
+	GradePoint    float64 `json:"grade_point" validate:"gte=0"`
+}
+
+type GradingScaleRequest struct {
+	Name      string                 `json:"name" validate:"required"`
+	IsDefault bool                   `json:"is_default"`
+	Grades    []GradeBoundaryRequest `json:"grades" validate:"required,min=1,dive"`
+}
+
+type AssessmentRequest struct {
+	ClassroomID uint    `json:"classroom_id" validate:"required"`
+	TermID      uint    `json:"term_id" validate:"required"`
+	Title       string  `json:"title" validate:"required"`
+	Type        string  `json:"type" validate:"required,oneof=exam quiz assignment"`
+	Weight      float64 `json:"weight" validate:"required,gt=0"`
+	MaxScore    float64 `json:"max_score" validate:"required,gt=0"`
+}
+
+type ClassroomTermRequest struct {
+	ClassroomID uint `json:"classroom_id" validate:"required"`
+	TermID      uint `json:"term_id" validate:"required"`
+}
+
+type ScoreRequest struct {
+	StudentID uint    `json:"student_id" validate:"required"`
+	Score     float64 `json:"score" validate:"gte=0"`
+}
+
+// BulkScoreRequest enters the scores of an assessment as the teacher the access token
+// was issued to
+type BulkScoreRequest struct {
+	AssessmentID uint           `json:"assessment_id" validate:"required"`
+	Scores       []ScoreRequest `json:"scores" validate:"required,min=1,dive"`
+	AccessToken  string         `json:"-" validate:"required"`
+}
+
+type StudentGradeRequest struct {
+	StudentID uint `json:"student_id" validate:"required"`
+}
@@ -0,0 +1,14 @@
+package requests
+
+type IDCardRequest struct {
+	StudentID string `json:"student_id" validate:"required,student_id"`
+	TermID    uint   `json:"term_id"`
+	Format    string `json:"format" validate:"omitempty,oneof=png pdf"`
+	Barcode   string `json:"barcode" validate:"omitempty,oneof=code128 qr"`
+}
+
+type ClassroomIDCardRequest struct {
+	ClassroomID uint   `json:"classroom_id" validate:"required"`
+	TermID      uint   `json:"term_id"`
+	Barcode     string `json:"barcode" validate:"omitempty,oneof=code128 qr"`
+}
@@ -0,0 +1,5 @@
+package requests
+
+type LanguageRequest struct {
+	Language string `json:"language" validate:"required,oneof=en lo th"`
+}
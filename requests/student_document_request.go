@@ -0,0 +1,22 @@
+package requests
+
+// StudentDocumentRequest is a multipart upload, the file itself is in the "document" field
+// or, for large files, in the completed resumable upload named by upload_id
+type StudentDocumentRequest struct {
+	StudentID      uint   `json:"-" form:"-" params:"id" validate:"required"`
+	DocumentType   string `json:"document_type" form:"document_type" validate:"required"`
+	Title          string `json:"title" form:"title"`
+	Description    string `json:"description" form:"description"`
+	DocumentNumber string `json:"document_number" form:"document_number"`
+	IssuedDate     string `json:"issued_date" form:"issued_date" validate:"omitempty,date"`
+	ExpiryDate     string `json:"expiry_date" form:"expiry_date" validate:"omitempty,date"`
+	UploadedBy     string `json:"uploaded_by" form:"uploaded_by"`
+	UploadID       string `json:"upload_id" form:"upload_id"`
+	FileName       string `json:"-" form:"-"`
+	Content        []byte `json:"-" form:"-"`
+}
+
+type StudentDocumentIDRequest struct {
+	StudentID  uint `json:"student_id" params:"id" validate:"required"`
+	DocumentID uint `json:"document_id" params:"document_id" validate:"required"`
+}
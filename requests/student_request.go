@@ -3,18 +3,20 @@ package requests
 //CRUD RestAPI Request Data for Create, Update, Delete
 
 type ClassroomIDRequest struct {
-	ClassroomID int `json:"classroom_id" validate:"required"`
+	ClassroomID int `json:"classroom_id" params:"classroomId" validate:"required"`
 }
 
 type SigUpRequest struct {
-	Phone    string `json:"phone" validate:"required,min=9,max=10"`
+	Phone    string `json:"phone" validate:"required,lao_phone"`
 	Password string `json:"password" validate:"required"`
 	UserType string `json:"user_type" validate:"required"`
 	//Token    string `json:"token"`
 }
 
+// SignInRequest only requires the phone, the format rules apply where a phone is set
+// so an account registered before them can still sign in
 type SignInRequest struct {
-	Phone    string `json:"phone" validate:"required,min=9,max=10"`
+	Phone    string `json:"phone" validate:"required"`
 	Password string `json:"password" validate:"required"`
 	UserType string `json:"user_type" validate:"required"`
 	//Token    string `json:"token"`
@@ -38,22 +40,22 @@ type DeleteCustomerRequest struct {
 }
 
 type StudentIdRequest struct {
-	StudentID string `json:"student_id"`
+	StudentID string `json:"student_id" params:"studentId" validate:"required,student_id"`
 }
 
 type StudentRequest struct {
-	StudentID string `json:"student_id"`
+	StudentID string `json:"student_id" params:"studentId" validate:"required,student_id"`
 	Firstname string `json:"firstname" `
 	Lastname  string `json:"lastname"`
-	Phone     string `json:"phone" validate:"required,min=9,max=10"`
+	Phone     string `json:"phone" validate:"required,lao_phone"`
 	Email     string `json:"email"`
 	Password  string `json:"password"`
-	Birthday  string `json:"birthday"`
+	Birthday  string `json:"birthday" validate:"omitempty,date"`
 	Gender    string `json:"gender"`
 	Status    int    `json:"status"`
 }
 
 type StudentImageRequest struct {
-	StudentID string `json:"student_id" validate:"required"`
+	StudentID string `json:"student_id" params:"studentId" validate:"required,student_id"`
 	Image     []byte `json:"image" validate:"required"`
 }
@@ -0,0 +1,13 @@
+package requests
+
+type TranscriptRequest struct {
+	StudentID uint `json:"student_id" validate:"required"`
+}
+
+type TranscriptSerialRequest struct {
+	SerialNo string `json:"serial_no" params:"serial" validate:"required"`
+}
+
+type TranscriptDocumentRequest struct {
+	Document []byte `json:"document" form:"transcript" validate:"required"`
+}
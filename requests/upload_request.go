@@ -0,0 +1,22 @@
+package requests
+
+type UploadSessionRequest struct {
+	FileName    string `json:"file_name" validate:"required"`
+	Size        int64  `json:"size" validate:"required,gt=0"`
+	ContentType string `json:"content_type"`
+	// Checksum is the optional SHA-256 hex of the whole file, checked once it is assembled
+	Checksum string `json:"checksum"`
+}
+
+// UploadChunkRequest is read from a PATCH, the offset and checksum come from the
+// Upload-Offset and Upload-Checksum ("sha256 <base64 digest>") headers
+type UploadChunkRequest struct {
+	UploadID string `json:"-" params:"id" validate:"required"`
+	Offset   int64  `json:"-" validate:"gte=0"`
+	Checksum string `json:"-"`
+	Content  []byte `json:"-" validate:"required"`
+}
+
+type UploadIDRequest struct {
+	UploadID string `json:"upload_id" params:"id" validate:"required"`
+}
@@ -22,12 +22,12 @@ type CreateUserRequest struct {
 	Email string `json:"email" validate:"required"`
 }
 type UpdateUserRequest struct {
-	ID    uint   `json:"id" validate:"required"`
+	ID    uint   `json:"id" params:"id" validate:"required"`
 	Email string `json:"email" validate:"required"`
 	Name  string `json:"name" `
 }
 type DeleteUserRequest struct {
-	ID uint `json:"id" validate:"required"`
+	ID uint `json:"id" params:"id" validate:"required"`
 }
 type UserIdRequest struct {
 	ID uint `json:"id" validate:"required"`
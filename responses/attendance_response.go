@@ -0,0 +1,91 @@
+package responses
+
+type ClassroomSessionResponse struct {
+	ID          uint   `json:"id"`
+	ClassroomID uint   `json:"classroom_id"`
+	SessionDate string `json:"session_date"`
+	Topic       string `json:"topic"`
+}
+
+type AttendanceResponse struct {
+	StudentID uint   `json:"student_id"`
+	StudentNo string `json:"student_no"`
+	Firstname string `json:"firstname"`
+	Lastname  string `json:"lastname"`
+	Status    string `json:"status"`
+	Note      string `json:"note"`
+}
+
+type SessionAttendanceResponse struct {
+	Session    ClassroomSessionResponse `json:"session"`
+	Attendance []AttendanceResponse     `json:"attendance"`
+}
+
+type AttendanceSummary struct {
+	TotalSessions  int     `json:"total_sessions"`
+	Present        int     `json:"present"`
+	Absent         int     `json:"absent"`
+	Late           int     `json:"late"`
+	Excused        int     `json:"excused"`
+	AttendanceRate float64 `json:"attendance_rate"`
+	AbsenceRate    float64 `json:"absence_rate"`
+}
+
+type ClassroomAttendance struct {
+	ClassroomID uint              `json:"classroom_id"`
+	ClassName   string            `json:"className"`
+	Summary     AttendanceSummary `json:"summary"`
+}
+
+type StudentAttendanceSummaryResponse struct {
+	StudentID  uint                  `json:"student_id"`
+	Summary    AttendanceSummary     `json:"summary"`
+	Classrooms []ClassroomAttendance `json:"classrooms"`
+}
+
+type StudentAttendance struct {
+	StudentID uint              `json:"student_id"`
+	StudentNo string            `json:"student_no"`
+	Firstname string            `json:"firstname"`
+	Lastname  string            `json:"lastname"`
+	Summary   AttendanceSummary `json:"summary"`
+	Alert     bool              `json:"alert"`
+}
+
+type ClassroomAttendanceSummaryResponse struct {
+	ClassroomID   uint                `json:"classroom_id"`
+	TotalSessions int                 `json:"total_sessions"`
+	Summary       AttendanceSummary   `json:"summary"`
+	Students      []StudentAttendance `json:"students"`
+}
+
+type AttendanceAlertResponse struct {
+	StudentID   uint    `json:"student_id"`
+	StudentNo   string  `json:"student_no"`
+	Firstname   string  `json:"firstname"`
+	Lastname    string  `json:"lastname"`
+	ClassroomID uint    `json:"classroom_id"`
+	AbsenceRate float64 `json:"absence_rate"`
+	Threshold   float64 `json:"threshold"`
+}
+
+type BulkAttendanceResponse struct {
+	Message string                    `json:"message"`
+	Marked  int                       `json:"marked"`
+	Alerts  []AttendanceAlertResponse `json:"alerts"`
+}
+
+type CheckInCodeResponse struct {
+	SessionID uint   `json:"session_id"`
+	Code      string `json:"code"`
+	QRCode    string `json:"qr_code"`
+	ExpiresAt string `json:"expires_at"`
+	RefreshIn int    `json:"refresh_in"`
+}
+
+type CheckInResponse struct {
+	SessionID   uint   `json:"session_id"`
+	StudentID   uint   `json:"student_id"`
+	Status      string `json:"status"`
+	CheckedInAt string `json:"checked_in_at"`
+}
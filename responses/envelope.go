@@ -0,0 +1,29 @@
+package responses
+
+import "go_starter/errs"
+
+// Envelope is the body of every JSON response. Data is set on success, Error on
+// failure, RequestID matches the X-Request-ID header to find the request in the logs.
+type Envelope struct {
+	Status    bool        `json:"status"`
+	Data      interface{} `json:"data,omitempty"`
+	Error     *Problem    `json:"error,omitempty"`
+	Meta      Meta        `json:"meta,omitempty"`
+	RequestID string      `json:"request_id,omitempty"`
+}
+
+// Meta holds what describes the data rather than being part of it, e.g. the count of
+// a list or the access token issued with a sign in
+type Meta map[string]interface{}
+
+// Problem is an RFC 7807 problem details body, answered on its own to clients that
+// accept application/problem+json and as the error of an Envelope to the others
+type Problem struct {
+	Type     string              `json:"type"`
+	Title    string              `json:"title"`
+	Status   int                 `json:"status"`
+	Detail   string              `json:"detail,omitempty"`
+	Instance string              `json:"instance,omitempty"`
+	Code     errs.Code           `json:"code"`
+	Errors   map[string][]string `json:"errors,omitempty"`
+}
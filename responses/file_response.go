@@ -0,0 +1,16 @@
+package responses
+
+type FileResponse struct {
+	FileName     string `json:"file_name"`
+	ContentType  string `json:"content_type"`
+	CacheControl string `json:"-"`
+	Content      []byte `json:"-"`
+}
+
+type StorageGCResponse struct {
+	DryRun         bool     `json:"dry_run"`
+	Scanned        int      `json:"scanned"`
+	DeletedObjects []string `json:"deleted_objects"`
+	DeletedFiles   []string `json:"deleted_files"`
+	MissingFiles   []string `json:"missing_files"`
+}
@@ -0,0 +1,76 @@
+package responses
+
+type TermResponse struct {
+	ID             uint   `json:"id"`
+	Name           string `json:"name"`
+	StartDate      string `json:"start_date"`
+	EndDate        string `json:"end_date"`
+	GradingScaleID uint   `json:"grading_scale_id"`
+	Finalized      bool   `json:"finalized"`
+	FinalizedAt    string `json:"finalized_at"`
+}
+
+type GradeBoundary struct {
+	Letter        string  `json:"letter"`
+	MinPercentage float64 `json:"min_percentage"`
+	GradePoint    float64 `json:"grade_point"`
+}
+
+type GradingScaleResponse struct {
+	ID        uint            `json:"id"`
+	Name      string          `json:"name"`
+	IsDefault bool            `json:"is_default"`
+	Grades    []GradeBoundary `json:"grades"`
+}
+
+type AssessmentResponse struct {
+	ID          uint    `json:"id"`
+	ClassroomID uint    `json:"classroom_id"`
+	TermID      uint    `json:"term_id"`
+	Title       string  `json:"title"`
+	Type        string  `json:"type"`
+	Weight      float64 `json:"weight"`
+	MaxScore    float64 `json:"max_score"`
+}
+
+type AssessmentScore struct {
+	AssessmentID uint    `json:"assessment_id"`
+	Score        float64 `json:"score"`
+	MaxScore     float64 `json:"max_score"`
+	Weight       float64 `json:"weight"`
+	Graded       bool    `json:"graded"`
+}
+
+type StudentGrade struct {
+	StudentID  uint              `json:"student_id"`
+	StudentNo  string            `json:"student_no"`
+	Firstname  string            `json:"firstname"`
+	Lastname   string            `json:"lastname"`
+	Scores     []AssessmentScore `json:"scores,omitempty"`
+	Percentage float64           `json:"percentage"`
+	Letter     string            `json:"letter"`
+	GradePoint float64           `json:"grade_point"`
+}
+
+type ClassroomGradesResponse struct {
+	ClassroomID uint           `json:"classroom_id"`
+	TermID      uint           `json:"term_id"`
+	Locked      bool           `json:"locked"`
+	Grades      []StudentGrade `json:"grades"`
+}
+
+type FinalGradeResponse struct {
+	ClassroomID uint    `json:"classroom_id"`
+	ClassName   string  `json:"className"`
+	SubjectName string  `json:"subject_name"`
+	TermID      uint    `json:"term_id"`
+	TermName    string  `json:"term_name"`
+	Percentage  float64 `json:"percentage"`
+	Letter      string  `json:"letter"`
+	GradePoint  float64 `json:"grade_point"`
+}
+
+type StudentGradesResponse struct {
+	StudentID uint                 `json:"student_id"`
+	Grades    []FinalGradeResponse `json:"grades"`
+}
@@ -0,0 +1,25 @@
+package responses
+
+type StudentDocumentResponse struct {
+	ID             uint   `json:"id"`
+	StudentID      uint   `json:"student_id"`
+	DocumentType   string `json:"document_type"`
+	Title          string `json:"title"`
+	Description    string `json:"description"`
+	DocumentNumber string `json:"document_number"`
+	IssuedDate     string `json:"issued_date"`
+	ExpiryDate     string `json:"expiry_date"`
+	FileName       string `json:"file_name"`
+	ContentType    string `json:"content_type"`
+	Size           int64  `json:"size"`
+	Hash           string `json:"hash"`
+	UploadedBy     string `json:"uploaded_by"`
+	CreatedAt      string `json:"created_at"`
+}
+
+type StudentDocumentTypeResponse struct {
+	Type         string   `json:"type"`
+	Label        string   `json:"label"`
+	ContentTypes []string `json:"content_types"`
+	MaxSize      int64    `json:"max_size"`
+}
@@ -26,6 +26,14 @@ type StudentClassroomResponse struct {
 	Student     []Student `json:"student"`
 }
 
+type ClassroomResponse struct {
+	ID          uint   `json:"id"`
+	ClassName   string `json:"className"`
+	ClassYear   int    `json:"class_year"`
+	SubjectName string `json:"subject_name"`
+	Credits     int    `json:"credits"`
+}
+
 type Student struct {
 	StudentID string `json:"student_id"`
 	Firstname string `json:"firstname"`
@@ -43,8 +51,25 @@ type StudentResponse struct {
 	Gender    string `json:"gender"`
 	Status    int    `json:"status"`
 	Image     string `json:"image"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	// ImageURL is signed and expires, append &w=<width> to resize
+	ImageURL string `json:"image_url,omitempty"`
+	// ImageVariants maps a variant name (thumb, w320, ...) to its signed URL
+	ImageVariants map[string]string `json:"image_variants,omitempty"`
+	CreatedAt     string            `json:"created_at"`
+	UpdatedAt     string            `json:"updated_at"`
+}
+
+type TeacherResponse struct {
+	ID        uint   `json:"id"`
+	Phone     string `json:"phone"`
+	Firstname string `json:"firstname"`
+	Lastname  string `json:"lastname"`
+	// ImageURL is signed and expires, append &w=<width> to resize
+	ImageURL string `json:"image_url,omitempty"`
+	// ImageVariants maps a variant name (thumb, w320, ...) to its signed URL
+	ImageVariants map[string]string `json:"image_variants,omitempty"`
+	CreatedAt     string            `json:"created_at"`
+	UpdatedAt     string            `json:"updated_at"`
 }
 
 type MessageResponse struct {
@@ -0,0 +1,20 @@
+package responses
+
+type TranscriptFileResponse struct {
+	SerialNo string `json:"serial_no"`
+	FileName string `json:"file_name"`
+	Content  []byte `json:"-"`
+}
+
+type TranscriptVerificationResponse struct {
+	Valid     bool   `json:"valid"`
+	Message   string `json:"message"`
+	SerialNo  string `json:"serial_no"`
+	StudentNo string `json:"student_no"`
+	Firstname string `json:"firstname"`
+	Lastname  string `json:"lastname"`
+	IssuedAt  string `json:"issued_at"`
+	Digest    string `json:"digest"`
+	Signature string `json:"signature"`
+	PublicKey string `json:"public_key"`
+}
@@ -0,0 +1,15 @@
+package responses
+
+type UploadSessionResponse struct {
+	ID        string `json:"id"`
+	FileName  string `json:"file_name"`
+	Size      int64  `json:"size"`
+	Offset    int64  `json:"offset"`
+	ChunkSize int64  `json:"chunk_size"`
+	Completed bool   `json:"completed"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+type ExpiredUploadsResponse struct {
+	Expired []string `json:"expired"`
+}
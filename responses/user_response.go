@@ -1,11 +1,15 @@
 package responses
 
 type UserResponse struct {
-	ID        uint   `json:"id"`
-	Name      string `json:"name"`
-	Email     string `json:"email"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	// ImageURL is signed and expires, append &w=<width> to resize
+	ImageURL string `json:"image_url,omitempty"`
+	// ImageVariants maps a variant name (thumb, w320, ...) to its signed URL
+	ImageVariants map[string]string `json:"image_variants,omitempty"`
+	CreatedAt     string            `json:"created_at"`
+	UpdatedAt     string            `json:"updated_at"`
 }
 type MessageUserResponse struct {
 	Message string `json:"message"`
@@ -1,9 +1,14 @@
 package api
 
 import (
+	"fmt"
 	"go_starter/controllers"
 	"go_starter/controllers/api"
+	"go_starter/openapi"
+	"go_starter/requests"
+	"go_starter/responses"
 	"go_starter/routes"
+	"net/http"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -12,26 +17,98 @@ type apiRoutes struct {
 	controllerApi     api.ControllerApi
 	studentController controllers.StudentController
 	userController    controllers.UserController
+	photoController   controllers.PhotoController
+	graphQLController controllers.GraphQLController
 }
 
-func (a apiRoutes) Install(app *fiber.App) {
-	route := app.Group("api/", func(ctx *fiber.Ctx) error {
-		return ctx.Next()
-	})
-	route.Post("hello", a.controllerApi.StartController)
-	//route.Post("customer", a.studentController.CreateCustomer)
-	//route.Get("customer/:id", a.studentController.GetCustomer)
-	route.Get("student", a.studentController.GetStudentController)
-	//route.Put("/customer/:id", a.studentController.UpdateCustomer)
-	//route.Delete("/customer/:id", a.studentController.DeleteCustomer)
-	route.Post("get-all-user", a.userController.GetAllUserController)
+// photoForm is the multipart form of a photo upload, its owner is in the path
+type photoForm struct {
+	Image []byte `form:"image" validate:"required"`
 }
 
-func NewApiRoutes(controllerApi api.ControllerApi, customerApi controllers.StudentController, userApi controllers.UserController) routes.Routes {
+// versions register the routes of each API version, oldest first. A version registers
+// only the routes it adds or changes and answers the rest with the routes of the
+// versions before it, so /api/v2 starts as a copy of /api/v1: append an installV2 and
+// register the handlers that differ there.
+func (a apiRoutes) versions() []func(route *openapi.Router) {
+	return []func(route *openapi.Router){
+		a.installV1,
+	}
+}
+
+// Install serves every version of the API under /api/v<n>
+func (a apiRoutes) Install(router *openapi.Router) {
+	versions := a.versions()
+
+	route := router.Group("api")
+	for version := range versions {
+		versionRoute := route.Group(fmt.Sprintf("v%d", version+1))
+		// fiber answers with the first matching route, the newest version registers first
+		for previous := version; previous >= 0; previous-- {
+			versions[previous](versionRoute)
+		}
+	}
+}
+
+func (a apiRoutes) installV1(route *openapi.Router) {
+	var (
+		auth       = []string{"auth"}
+		students   = []string{"students"}
+		teachers   = []string{"teachers"}
+		classrooms = []string{"classrooms"}
+		users      = []string{"users"}
+	)
+	created := controllers.Created()
+	route.Register(fiber.MethodGet, "hello", openapi.Operation{Summary: "Check the API answers", Tags: []string{"system"}, Response: openapi.Message{}}, a.controllerApi.StartController)
+
+	//auth
+	route.Register(fiber.MethodPost, "auth/signup", openapi.Operation{Summary: "Sign up a student or teacher", Tags: auth, Request: requests.SigUpRequest{}, Response: responses.SignUpResponse{}, Status: http.StatusCreated}, created, a.studentController.SignUpController)
+	route.Register(fiber.MethodPost, "auth/signin", openapi.Operation{Summary: "Sign in a student or teacher", Tags: auth, Request: requests.SignInRequest{}, Response: responses.SignInResponse{}}, a.studentController.SignInController)
+	route.Register(fiber.MethodPost, "auth/login", openapi.Operation{Summary: "Log in a user", Tags: auth, Request: requests.LoginRequest{}, Response: responses.ResponseLogin{}, Meta: responses.Meta{"access_token": ""}}, a.userController.LoginController)
+	route.Register(fiber.MethodPost, "auth/sign-in", openapi.Operation{Summary: "Sign in a user", Tags: auth, Request: requests.SignInUserRequest{}, Response: responses.SignInUserResponse{}}, a.userController.SignInUserController)
+
+	//students
+	route.Register(fiber.MethodGet, "students", openapi.Operation{Summary: "List students", Tags: students, Response: []responses.StudentResponse{}, Meta: responses.Meta{"count": 0}}, a.studentController.GetStudentController)
+	route.Register(fiber.MethodPost, "students", openapi.Operation{Summary: "Create a student", Tags: students, Request: requests.StudentRequest{}, Response: openapi.Message{}, Status: http.StatusCreated}, created, a.studentController.CreateStudentController)
+	route.Register(fiber.MethodGet, "students/:studentId", openapi.Operation{Summary: "Get a student", Tags: students, Request: requests.StudentIdRequest{}, Response: responses.StudentResponse{}}, a.studentController.GetStudentByStudentIDControllerV2)
+	route.Register(fiber.MethodPut, "students/:studentId", openapi.Operation{Summary: "Update a student", Tags: students, Request: requests.StudentRequest{}, Response: openapi.Message{}}, a.studentController.UpdateStudentController)
+	route.Register(fiber.MethodPatch, "students/:studentId", openapi.Operation{Summary: "Update a student", Tags: students, Request: requests.StudentRequest{}, Response: openapi.Message{}}, a.studentController.UpdateStudentController)
+	route.Register(fiber.MethodDelete, "students/:studentId", openapi.Operation{Summary: "Delete a student", Tags: students, Request: requests.StudentIdRequest{}, Response: openapi.Message{}}, a.studentController.DeleteStudentByIDController)
+	route.Register(fiber.MethodPut, "students/:studentId/image", openapi.Operation{Summary: "Upload the photo of a student", Tags: students, Request: requests.StudentImageRequest{}, Consumes: openapi.Multipart, Response: openapi.Message{}}, a.studentController.UploadStudentImageController)
+
+	//teachers
+	route.Register(fiber.MethodGet, "teachers/:id", openapi.Operation{Summary: "Get a teacher", Tags: teachers, Params: openapi.IDParam{}, Response: responses.TeacherResponse{}}, a.studentController.GetTeacherByIDController)
+	route.Register(fiber.MethodPut, "teachers/:id/image", openapi.Operation{Summary: "Upload the photo of a teacher", Tags: teachers, Request: photoForm{}, Consumes: openapi.Multipart, Response: openapi.Message{}}, a.photoController.UploadTeacherPhotoController)
+
+	//classrooms
+	route.Register(fiber.MethodGet, "classrooms/:classroomId/students", openapi.Operation{Summary: "List the students of a classroom", Tags: classrooms, Request: requests.ClassroomIDRequest{}, Response: responses.StudentClassroomResponse{}}, a.studentController.GetStudentClassroomByClassroomIDController)
+
+	//users
+	route.Register(fiber.MethodGet, "users", openapi.Operation{Summary: "List users", Tags: users, Response: []responses.UserResponse{}, Meta: responses.Meta{"count": 0}}, a.userController.GetAllUserController)
+	route.Register(fiber.MethodGet, "users/:id", openapi.Operation{Summary: "Get a user", Tags: users, Params: openapi.IDParam{}, Response: responses.UserResponse{}}, a.userController.GetUserByIdController)
+	route.Register(fiber.MethodPut, "users/:id", openapi.Operation{Summary: "Update a user", Tags: users, Request: requests.UpdateUserRequest{}, Response: openapi.Message{}}, a.userController.UpdateUserController)
+	route.Register(fiber.MethodPatch, "users/:id", openapi.Operation{Summary: "Update a user", Tags: users, Request: requests.UpdateUserRequest{}, Response: openapi.Message{}}, a.userController.UpdateUserController)
+	route.Register(fiber.MethodDelete, "users/:id", openapi.Operation{Summary: "Delete a user", Tags: users, Request: requests.DeleteUserRequest{}, Response: openapi.Message{}}, a.userController.DeleteUserController)
+	route.Register(fiber.MethodPut, "users/:id/image", openapi.Operation{Summary: "Upload the photo of a user", Tags: users, Request: photoForm{}, Consumes: openapi.Multipart, Response: openapi.Message{}}, a.photoController.UploadUserPhotoController)
+
+	//graphql
+	route.Register(fiber.MethodPost, "graphql", openapi.Operation{Summary: "Query students, classrooms, teachers and users with GraphQL", Tags: []string{"graphql"}, Request: requests.GraphQLRequest{}, Produces: []string{openapi.JSON}}, a.graphQLController.ExecuteGraphQLController)
+}
+
+func NewApiRoutes(
+	controllerApi api.ControllerApi,
+	studentController controllers.StudentController,
+	userController controllers.UserController,
+	photoController controllers.PhotoController,
+	graphQLController controllers.GraphQLController,
+	// controller
+) routes.Routes {
 	return &apiRoutes{
 		controllerApi:     controllerApi,
-		studentController: customerApi,
-		userController:    userApi,
+		studentController: studentController,
+		userController:    userController,
+		photoController:   photoController,
+		graphQLController: graphQLController,
 		//controller
 	}
 }
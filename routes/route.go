@@ -1,7 +1,10 @@
 package routes
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"go_starter/openapi"
+)
 
 type Routes interface {
-	Install(app *fiber.App)
+	// Install registers the routes on router, which documents them for /openapi.json
+	Install(router *openapi.Router)
 }
@@ -3,49 +3,135 @@ package web
 import (
 	"go_starter/controllers"
 	"go_starter/controllers/web"
+	"go_starter/openapi"
+	"go_starter/requests"
+	"go_starter/responses"
 	"go_starter/routes"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type webRoutes struct {
-	controller        web.Controller
-	studentController controllers.StudentController
-	userController    controllers.UserController
+	controller           web.Controller
+	studentController    controllers.StudentController
+	userController       controllers.UserController
+	attendanceController controllers.AttendanceController
+	gradeController      controllers.GradeController
+	transcriptController controllers.TranscriptController
+	idCardController     controllers.IDCardController
+	documentController   controllers.StudentDocumentController
+	uploadController     controllers.UploadController
+	photoController      controllers.PhotoController
 }
 
-func (w webRoutes) Install(app *fiber.App) {
-	route := app.Group("web/", func(ctx *fiber.Ctx) error {
-		return ctx.Next()
-	})
-	route.Post("hello", w.controller.StartController)
-	route.Get("students", w.studentController.GetStudentController)
-	route.Get("student/:id", w.studentController.GetStudentByIDController)
-	route.Get("student", w.studentController.GetStudentByStudentIDControllerV2)
-	route.Post("create-student", w.studentController.CreateStudentController)
-	route.Put("update-student", w.studentController.UpdateStudentController)
-	route.Delete("delete-student", w.studentController.DeleteStudentByIDController)
+// The photo uploads of the web routes name their owner in the form
+type teacherPhotoForm struct {
+	TeacherID string `form:"teacher_id" validate:"required"`
+	Image     []byte `form:"image" validate:"required"`
+}
+
+type userPhotoForm struct {
+	UserID string `form:"user_id" validate:"required"`
+	Image  []byte `form:"image" validate:"required"`
+}
+
+// Install registers the web routes, those /api/v1 serves too are deprecated
+func (w webRoutes) Install(router *openapi.Router) {
+	route := router.Group("web/")
+	// Superseded by /api/v1, kept for clients not migrated yet
+	legacy := route.With(controllers.Deprecated("/api/v1"))
+	legacy.Deprecated = true
+	var (
+		students    = []string{"students"}
+		teachers    = []string{"teachers"}
+		auth        = []string{"auth"}
+		users       = []string{"users"}
+		attendance  = []string{"attendance"}
+		gradebook   = []string{"gradebook"}
+		transcripts = []string{"transcripts"}
+		idCards     = []string{"id cards"}
+		documents   = []string{"student documents"}
+		uploads     = []string{"uploads"}
+	)
+	// Rendering a PDF for a whole classroom or term takes longer than a plain request
+	reportTimeout := controllers.RequestTimeout("app.report_timeout_seconds", 120)
+
+	legacy.Register(fiber.MethodPost, "hello", openapi.Operation{Summary: "Check the web routes answer", Tags: []string{"system"}, Response: openapi.Message{}}, w.controller.StartController)
+	legacy.Register(fiber.MethodGet, "students", openapi.Operation{Summary: "List students", Tags: students, Response: []responses.StudentResponse{}, Meta: responses.Meta{"count": 0}}, w.studentController.GetStudentController)
+	route.Register(fiber.MethodGet, "student/:id", openapi.Operation{Summary: "Get a student by id", Tags: students, Params: openapi.IDParam{}, Response: responses.StudentResponse{}}, w.studentController.GetStudentByIDController)
+	legacy.Register(fiber.MethodGet, "student", openapi.Operation{Summary: "Get a student by student id", Tags: students, Request: requests.StudentIdRequest{}, Response: responses.StudentResponse{}}, w.studentController.GetStudentByStudentIDControllerV2)
+	legacy.Register(fiber.MethodGet, "teacher/:id", openapi.Operation{Summary: "Get a teacher", Tags: teachers, Params: openapi.IDParam{}, Response: responses.TeacherResponse{}}, w.studentController.GetTeacherByIDController)
+	legacy.Register(fiber.MethodPost, "create-student", openapi.Operation{Summary: "Create a student", Tags: students, Request: requests.StudentRequest{}, Response: openapi.Message{}}, w.studentController.CreateStudentController)
+	legacy.Register(fiber.MethodPut, "update-student", openapi.Operation{Summary: "Update a student", Tags: students, Request: requests.StudentRequest{}, Response: openapi.Message{}}, w.studentController.UpdateStudentController)
+	legacy.Register(fiber.MethodDelete, "delete-student", openapi.Operation{Summary: "Delete a student", Tags: students, Request: requests.StudentIdRequest{}, Response: openapi.Message{}}, w.studentController.DeleteStudentByIDController)
 
 	//image
-	route.Post("update-image", w.studentController.UploadStudentImageController)
+	legacy.Register(fiber.MethodPost, "update-image", openapi.Operation{Summary: "Upload the photo of a student", Tags: students, Request: requests.StudentImageRequest{}, Consumes: openapi.Multipart, Response: openapi.Message{}}, w.studentController.UploadStudentImageController)
+	legacy.Register(fiber.MethodPost, "update-teacher-image", openapi.Operation{Summary: "Upload the photo of a teacher", Tags: teachers, Request: teacherPhotoForm{}, Consumes: openapi.Multipart, Response: openapi.Message{}}, w.photoController.UploadTeacherPhotoController)
+	legacy.Register(fiber.MethodPost, "update-user-image", openapi.Operation{Summary: "Upload the photo of a user", Tags: users, Request: userPhotoForm{}, Consumes: openapi.Multipart, Response: openapi.Message{}}, w.photoController.UploadUserPhotoController)
 
-	route.Post("signup", w.studentController.SignUpController)
-	route.Post("signin", w.studentController.SignInController)
-	route.Post("student-classroom", w.studentController.GetStudentClassroomByClassroomIDController)
+	legacy.Register(fiber.MethodPost, "signup", openapi.Operation{Summary: "Sign up a student or teacher", Tags: auth, Request: requests.SigUpRequest{}, Response: responses.SignUpResponse{}}, w.studentController.SignUpController)
+	legacy.Register(fiber.MethodPost, "signin", openapi.Operation{Summary: "Sign in a student or teacher", Tags: auth, Request: requests.SignInRequest{}, Response: responses.SignInResponse{}}, w.studentController.SignInController)
+	legacy.Register(fiber.MethodPost, "student-classroom", openapi.Operation{Summary: "List the students of a classroom", Tags: students, Request: requests.ClassroomIDRequest{}, Response: responses.StudentClassroomResponse{}}, w.studentController.GetStudentClassroomByClassroomIDController)
 
 	// User LogIn and User CRUD
 
 	//LogIn
-	route.Post("login", w.userController.LoginController)
+	legacy.Register(fiber.MethodPost, "login", openapi.Operation{Summary: "Log in a user", Tags: auth, Request: requests.LoginRequest{}, Response: responses.ResponseLogin{}, Meta: responses.Meta{"access_token": ""}}, w.userController.LoginController)
 	//route.Post("sign-up", w.userController.SignUpUserController)
-	route.Post("sign-in", w.userController.SignInUserController)
+	legacy.Register(fiber.MethodPost, "sign-in", openapi.Operation{Summary: "Sign in a user", Tags: auth, Request: requests.SignInUserRequest{}, Response: responses.SignInUserResponse{}}, w.userController.SignInUserController)
 
 	//CRUD
-	route.Post("get-all-user", w.userController.GetAllUserController)
-	route.Post("get-by-id/:id", w.userController.GetUserByIdController)
+	legacy.Register(fiber.MethodPost, "get-all-user", openapi.Operation{Summary: "List users", Tags: users, Response: []responses.UserResponse{}, Meta: responses.Meta{"count": 0}}, w.userController.GetAllUserController)
+	legacy.Register(fiber.MethodPost, "get-by-id/:id", openapi.Operation{Summary: "Get a user", Tags: users, Params: openapi.IDParam{}, Response: responses.UserResponse{}}, w.userController.GetUserByIdController)
 	//route.Post("create-user", w.userController.CreateUserController)
-	route.Post("update-user", w.userController.UpdateUserController)
-	route.Post("delete-user", w.userController.DeleteUserController)
+	legacy.Register(fiber.MethodPost, "update-user", openapi.Operation{Summary: "Update a user", Tags: users, Request: requests.UpdateUserRequest{}, Response: openapi.Message{}}, w.userController.UpdateUserController)
+	legacy.Register(fiber.MethodPost, "delete-user", openapi.Operation{Summary: "Delete a user", Tags: users, Request: requests.DeleteUserRequest{}, Response: openapi.Message{}}, w.userController.DeleteUserController)
+
+	//attendance
+	route.Register(fiber.MethodPost, "create-session", openapi.Operation{Summary: "Open a classroom session", Tags: attendance, Request: requests.ClassroomSessionRequest{}, Response: responses.ClassroomSessionResponse{}}, w.attendanceController.CreateClassroomSessionController)
+	route.Register(fiber.MethodPost, "classroom-sessions", openapi.Operation{Summary: "List the sessions of a classroom", Tags: attendance, Request: requests.ClassroomIDRequest{}, Response: []responses.ClassroomSessionResponse{}}, w.attendanceController.GetClassroomSessionsController)
+	route.Register(fiber.MethodPost, "mark-attendance", openapi.Operation{Summary: "Mark the attendance of a session", Tags: attendance, Request: requests.BulkAttendanceRequest{}, Response: responses.BulkAttendanceResponse{}}, w.attendanceController.MarkAttendanceController)
+	route.Register(fiber.MethodPost, "session-attendance", openapi.Operation{Summary: "Get the attendance of a session", Tags: attendance, Request: requests.ClassroomSessionIDRequest{}, Response: responses.SessionAttendanceResponse{}}, w.attendanceController.GetSessionAttendanceController)
+	route.Register(fiber.MethodPost, "student-attendance-summary", openapi.Operation{Summary: "Summarize the attendance of a student", Tags: attendance, Request: requests.StudentAttendanceRequest{}, Response: responses.StudentAttendanceSummaryResponse{}}, w.attendanceController.GetStudentAttendanceSummaryController)
+	route.Register(fiber.MethodPost, "classroom-attendance-summary", openapi.Operation{Summary: "Summarize the attendance of a classroom", Tags: attendance, Request: requests.ClassroomIDRequest{}, Response: responses.ClassroomAttendanceSummaryResponse{}}, w.attendanceController.GetClassroomAttendanceSummaryController)
+	route.Register(fiber.MethodPost, "attendance-alerts", openapi.Operation{Summary: "List students below an attendance threshold", Tags: attendance, Request: requests.AttendanceAlertRequest{}, Response: []responses.AttendanceAlertResponse{}}, w.attendanceController.GetAttendanceAlertsController)
+	route.Register(fiber.MethodPost, "check-in-code", openapi.Operation{Summary: "Generate the check-in code of a session for its teacher", Tags: attendance, Request: requests.CheckInCodeRequest{}, Response: responses.CheckInCodeResponse{}}, w.attendanceController.GenerateCheckInCodeController)
+	route.Register(fiber.MethodPost, "check-in", openapi.Operation{Summary: "Check in to a session with its code", Tags: attendance, Request: requests.CheckInRequest{}, Response: responses.CheckInResponse{}}, w.attendanceController.CheckInController)
+
+	//gradebook
+	route.Register(fiber.MethodPost, "create-term", openapi.Operation{Summary: "Create a term", Tags: gradebook, Request: requests.TermRequest{}, Response: responses.TermResponse{}}, w.gradeController.CreateTermController)
+	route.Register(fiber.MethodPost, "finalize-term", openapi.Operation{Summary: "Finalize the grades of a term", Tags: gradebook, Request: requests.TermIDRequest{}, Response: responses.TermResponse{}}, reportTimeout, w.gradeController.FinalizeTermController)
+	route.Register(fiber.MethodPost, "create-grading-scale", openapi.Operation{Summary: "Create a grading scale", Tags: gradebook, Request: requests.GradingScaleRequest{}, Response: responses.GradingScaleResponse{}}, w.gradeController.CreateGradingScaleController)
+	route.Register(fiber.MethodGet, "grading-scales", openapi.Operation{Summary: "List grading scales", Tags: gradebook, Response: []responses.GradingScaleResponse{}}, w.gradeController.GetGradingScalesController)
+	route.Register(fiber.MethodPost, "create-assessment", openapi.Operation{Summary: "Create an assessment", Tags: gradebook, Request: requests.AssessmentRequest{}, Response: responses.AssessmentResponse{}}, w.gradeController.CreateAssessmentController)
+	route.Register(fiber.MethodPost, "classroom-assessments", openapi.Operation{Summary: "List the assessments of a classroom", Tags: gradebook, Request: requests.ClassroomTermRequest{}, Response: []responses.AssessmentResponse{}}, w.gradeController.GetClassroomAssessmentsController)
+	route.Register(fiber.MethodPost, "enter-scores", openapi.Operation{Summary: "Enter the scores of an assessment", Tags: gradebook, Request: requests.BulkScoreRequest{}, Response: responses.MessageResponse{}}, w.gradeController.EnterScoresController)
+	route.Register(fiber.MethodPost, "classroom-grades", openapi.Operation{Summary: "Get the grades of a classroom", Tags: gradebook, Request: requests.ClassroomTermRequest{}, Response: responses.ClassroomGradesResponse{}}, w.gradeController.GetClassroomGradesController)
+	route.Register(fiber.MethodPost, "student-grades", openapi.Operation{Summary: "Get the grades of a student", Tags: gradebook, Request: requests.StudentGradeRequest{}, Response: responses.StudentGradesResponse{}}, w.gradeController.GetStudentGradesController)
+
+	//transcript
+	route.Register(fiber.MethodPost, "transcript", openapi.Operation{Summary: "Issue the transcript of a student", Tags: transcripts, Request: requests.TranscriptRequest{}, Produces: []string{openapi.PDF}}, reportTimeout, w.transcriptController.GenerateTranscriptController)
+	route.Register(fiber.MethodGet, "verify-transcript/:serial", openapi.Operation{Summary: "Verify a transcript by its serial number", Tags: transcripts, Request: requests.TranscriptSerialRequest{}, Response: responses.TranscriptVerificationResponse{}}, w.transcriptController.VerifyTranscriptBySerialController)
+	route.Register(fiber.MethodPost, "verify-transcript", openapi.Operation{Summary: "Verify a transcript document", Tags: transcripts, Request: requests.TranscriptDocumentRequest{}, Consumes: openapi.Multipart, Response: responses.TranscriptVerificationResponse{}}, w.transcriptController.VerifyTranscriptDocumentController)
+
+	//id card
+	route.Register(fiber.MethodPost, "id-card", openapi.Operation{Summary: "Render the ID card of a student", Tags: idCards, Request: requests.IDCardRequest{}, Produces: []string{openapi.PNG, openapi.PDF}}, reportTimeout, w.idCardController.GenerateIDCardController)
+	route.Register(fiber.MethodPost, "classroom-id-cards", openapi.Operation{Summary: "Render the ID cards of a classroom", Tags: idCards, Request: requests.ClassroomIDCardRequest{}, Produces: []string{openapi.PDF}}, reportTimeout, w.idCardController.GenerateClassroomIDCardsController)
+
+	//student documents
+	route.Register(fiber.MethodGet, "student-document-types", openapi.Operation{Summary: "List the document types", Tags: documents, Response: []responses.StudentDocumentTypeResponse{}}, w.documentController.GetStudentDocumentTypesController)
+	route.Register(fiber.MethodPost, "student/:id/documents", openapi.Operation{Summary: "Upload a document of a student, a file or a completed upload", Tags: documents, Request: requests.StudentDocumentRequest{}, Consumes: openapi.Multipart, Files: []string{"document"}, Response: responses.StudentDocumentResponse{}}, w.documentController.UploadStudentDocumentController)
+	route.Register(fiber.MethodGet, "student/:id/documents", openapi.Operation{Summary: "List the documents of a student", Tags: documents, Params: openapi.IDParam{}, Response: []responses.StudentDocumentResponse{}}, w.documentController.GetStudentDocumentsController)
+	route.Register(fiber.MethodGet, "student/:id/documents/:document_id", openapi.Operation{Summary: "Download a document", Tags: documents, Request: requests.StudentDocumentIDRequest{}, Produces: []string{openapi.Binary}}, w.documentController.DownloadStudentDocumentController)
+	route.Register(fiber.MethodDelete, "student/:id/documents/:document_id", openapi.Operation{Summary: "Delete a document", Tags: documents, Request: requests.StudentDocumentIDRequest{}, Response: openapi.Message{}}, w.documentController.DeleteStudentDocumentController)
+
+	//resumable uploads
+	route.Register(fiber.MethodPost, "uploads", openapi.Operation{Summary: "Start a resumable upload", Tags: uploads, Request: requests.UploadSessionRequest{}, Response: responses.UploadSessionResponse{}}, w.uploadController.CreateUploadSessionController)
+	route.Register(fiber.MethodHead, "uploads/:id", openapi.Operation{Summary: "Get the offset to resume an upload from, in Upload-Offset", Tags: uploads, Request: requests.UploadIDRequest{}}, w.uploadController.GetUploadOffsetController)
+	route.Register(fiber.MethodGet, "uploads/:id", openapi.Operation{Summary: "Get an upload", Tags: uploads, Request: requests.UploadIDRequest{}, Response: responses.UploadSessionResponse{}}, w.uploadController.GetUploadSessionController)
+	route.Register(fiber.MethodPatch, "uploads/:id", openapi.Operation{Summary: "Append the chunk starting at Upload-Offset", Tags: uploads, Request: requests.UploadChunkRequest{}, Consumes: "application/offset+octet-stream", Response: responses.UploadSessionResponse{}}, w.uploadController.AppendUploadChunkController)
+	route.Register(fiber.MethodDelete, "uploads/:id", openapi.Operation{Summary: "Cancel an upload", Tags: uploads, Request: requests.UploadIDRequest{}, Response: openapi.Message{}}, w.uploadController.DeleteUploadSessionController)
 
 }
 
@@ -53,12 +139,26 @@ func NewWebRoutes(
 	controller web.Controller,
 	studentController controllers.StudentController,
 	userController controllers.UserController,
+	attendanceController controllers.AttendanceController,
+	gradeController controllers.GradeController,
+	transcriptController controllers.TranscriptController,
+	idCardController controllers.IDCardController,
+	documentController controllers.StudentDocumentController,
+	uploadController controllers.UploadController,
+	photoController controllers.PhotoController,
 	// controller
 ) routes.Routes {
 	return &webRoutes{
-		controller:        controller,
-		studentController: studentController,
-		userController:    userController,
+		controller:           controller,
+		studentController:    studentController,
+		userController:       userController,
+		attendanceController: attendanceController,
+		gradeController:      gradeController,
+		transcriptController: transcriptController,
+		idCardController:     idCardController,
+		documentController:   documentController,
+		uploadController:     uploadController,
+		photoController:      photoController,
 		//controller
 	}
 }
@@ -0,0 +1,36 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"go_starter/config"
+)
+
+var ErrInfected = errors.New("file is infected")
+
+// Scanner checks an uploaded file for malware before it is stored
+type Scanner interface {
+	Scan(fileName string, data []byte) error
+}
+
+// NewScanner builds the scanner selected by documents.virus_scanner
+func NewScanner() (Scanner, error) {
+	driver := config.GetEnv("documents.virus_scanner", "none")
+	switch driver {
+	case "none":
+		return NewNoopScanner(), nil
+	default:
+		return nil, fmt.Errorf("unknown virus scanner %q", driver)
+	}
+}
+
+type noopScanner struct{}
+
+// Scan accepts every file, for local development and deployments without a scanner
+func (n noopScanner) Scan(fileName string, data []byte) error {
+	return nil
+}
+
+func NewNoopScanner() Scanner {
+	return &noopScanner{}
+}
@@ -0,0 +1,72 @@
+package security
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const checkInAudience = "attendance-check-in"
+
+// ErrCheckInCodeExpired and ErrInvalidCheckInCode are returned by ParseCheckInToken
+var (
+	ErrCheckInCodeExpired = errors.New("check-in code has expired")
+	ErrInvalidCheckInCode = errors.New("invalid check-in code")
+)
+
+// JwtCheckInSecret signs check-in codes, read from security.check_in_secret by LoadSecrets
+var JwtCheckInSecret []byte
+
+type CheckInClaims struct {
+	SessionID uint `json:"sid"`
+	jwt.StandardClaims
+}
+
+// NewCheckInToken mints a short-lived code a student scans to check in to a classroom session.
+func NewCheckInToken(sessionID uint, ttl time.Duration) (string, time.Time, error) {
+	nonce := make([]byte, 12)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", time.Time{}, err
+	}
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	claims := CheckInClaims{
+		SessionID: sessionID,
+		StandardClaims: jwt.StandardClaims{
+			Id:        hex.EncodeToString(nonce),
+			Audience:  checkInAudience,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: expiresAt.Unix(),
+		},
+	}
+	withClaims := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	checkInToken, err := withClaims.SignedString(JwtCheckInSecret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return checkInToken, expiresAt, nil
+}
+
+func ParseCheckInToken(tokenString string) (*CheckInClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &CheckInClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return JwtCheckInSecret, nil
+	})
+	if err != nil {
+		var validationErr *jwt.ValidationError
+		if errors.As(err, &validationErr) && validationErr.Errors&jwt.ValidationErrorExpired != 0 {
+			return nil, ErrCheckInCodeExpired
+		}
+		return nil, ErrInvalidCheckInCode
+	}
+	claims, ok := token.Claims.(*CheckInClaims)
+	if !ok || !token.Valid || !claims.VerifyAudience(checkInAudience, true) {
+		return nil, ErrInvalidCheckInCode
+	}
+	return claims, nil
+}
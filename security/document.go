@@ -0,0 +1,90 @@
+package security
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"os"
+
+	"github.com/pkg/errors"
+	"go_starter/config"
+)
+
+// documentKey signs issued documents such as transcripts, read by LoadSecrets
+var documentKey ed25519.PrivateKey
+
+// publishedDocumentSeed derived the key of earlier builds from a string in this repo,
+// documents signed with it prove nothing
+var publishedDocumentSeed = sha256.Sum256([]byte("ceit_document_signing"))
+
+// loadDocumentKey reads the ed25519 key from security.document_signing_key_file, a
+// PKCS#8 PEM file as `openssl genpkey -algorithm ed25519` writes it, or else from
+// security.document_signing_key, the base64 of a 32 byte seed
+func loadDocumentKey() (ed25519.PrivateKey, error) {
+	var privateKey ed25519.PrivateKey
+	if path := config.Env("security.document_signing_key_file"); path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "security.document_signing_key_file")
+		}
+		block, _ := pem.Decode(content)
+		if block == nil {
+			return nil, errors.New("security.document_signing_key_file holds no PEM key")
+		}
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "security.document_signing_key_file")
+		}
+		key, ok := parsed.(ed25519.PrivateKey)
+		if !ok {
+			return nil, errors.New("security.document_signing_key_file is not an ed25519 key")
+		}
+		privateKey = key
+	} else {
+		seed, err := base64.StdEncoding.DecodeString(config.Env("security.document_signing_key"))
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, errors.Errorf("security.document_signing_key_file or security.document_signing_key, the base64 of a %d byte seed, must be set", ed25519.SeedSize)
+		}
+		privateKey = ed25519.NewKeyFromSeed(seed)
+	}
+	if subtle.ConstantTimeCompare(privateKey.Seed(), publishedDocumentSeed[:]) == 1 {
+		return nil, errors.New("the document signing key is a published one, generate a key of this deployment")
+	}
+	return privateKey, nil
+}
+
+// DocumentPublicKey is published so anyone can check a document signature offline
+func DocumentPublicKey() string {
+	publicKey := documentKey.Public().(ed25519.PublicKey)
+	return base64.StdEncoding.EncodeToString(publicKey)
+}
+
+// SignDocument returns the hex SHA-256 digest of the document and a base64 signature over it
+func SignDocument(document []byte) (string, string) {
+	digest := DocumentDigest(document)
+	signature := ed25519.Sign(documentKey, []byte(digest))
+	return digest, base64.StdEncoding.EncodeToString(signature)
+}
+
+// VerifyDocument checks signature against the document itself
+func VerifyDocument(document []byte, signature string) bool {
+	return VerifyDocumentSignature(DocumentDigest(document), signature)
+}
+
+func VerifyDocumentSignature(digest, signature string) bool {
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	publicKey := documentKey.Public().(ed25519.PublicKey)
+	return ed25519.Verify(publicKey, []byte(digest), decoded)
+}
+
+func DocumentDigest(document []byte) string {
+	sum := sha256.Sum256(document)
+	return hex.EncodeToString(sum[:])
+}
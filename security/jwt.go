@@ -81,6 +81,21 @@ func CheckToken(tokenString string) (bool, error) {
 }
 
 
+// ParseAccessToken returns the claims of a valid, unexpired access token.
+// The token Id holds the phone or email it was issued for.
+func ParseAccessToken(tokenString string) (*jwt.StandardClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &jwt.StandardClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return JwtSecretKey, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(*jwt.StandardClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
 
 
 
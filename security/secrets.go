@@ -0,0 +1,39 @@
+package security
+
+import (
+	"github.com/pkg/errors"
+	"go_starter/config"
+)
+
+// minSecretLength is the least a secret may have, the 32 bytes of an HMAC-SHA256 key
+const minSecretLength = 32
+
+// LoadSecrets reads the keys codes, URLs and documents are signed with. They are set
+// per deployment, the server refuses to start while one is unset, short or still a
+// value published with this repo.
+func LoadSecrets() error {
+	var err error
+	if JwtCheckInSecret, err = secret("security.check_in_secret", "ceit_check_in"); err != nil {
+		return err
+	}
+	if URLSigningSecret, err = secret("security.signed_url_secret", "ceit_signed_url"); err != nil {
+		return err
+	}
+	if documentKey, err = loadDocumentKey(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func secret(key string, published ...string) ([]byte, error) {
+	value := config.Env(key)
+	for _, publishedValue := range published {
+		if value == publishedValue {
+			return nil, errors.Errorf("%s is a published value, set a random one of this deployment", key)
+		}
+	}
+	if len(value) < minSecretLength {
+		return nil, errors.Errorf("%s must be set to a random value of at least %d characters", key, minSecretLength)
+	}
+	return []byte(value), nil
+}
@@ -0,0 +1,51 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ErrSignedURLExpired and ErrInvalidSignature are returned by VerifyURLPath
+var (
+	ErrSignedURLExpired = errors.New("signed url has expired")
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// URLSigningSecret signs file URLs, LoadSecrets reads it from security.signed_url_secret
+var URLSigningSecret []byte
+
+// SignURLPath returns the query string that grants access to urlPath until expiresAt.
+func SignURLPath(urlPath string, expiresAt time.Time) string {
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", urlSignature(urlPath, expires))
+	return query.Encode()
+}
+
+// VerifyURLPath checks a signature produced by SignURLPath and returns its expiry.
+func VerifyURLPath(urlPath, expires, signature string) (time.Time, error) {
+	expiresUnix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || signature == "" {
+		return time.Time{}, ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(urlSignature(urlPath, expires)), []byte(signature)) {
+		return time.Time{}, ErrInvalidSignature
+	}
+	expiresAt := time.Unix(expiresUnix, 0)
+	if time.Now().After(expiresAt) {
+		return time.Time{}, ErrSignedURLExpired
+	}
+	return expiresAt, nil
+}
+
+func urlSignature(urlPath, expires string) string {
+	mac := hmac.New(sha256.New, URLSigningSecret)
+	mac.Write([]byte(urlPath + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,507 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/skip2/go-qrcode"
+	"go.uber.org/zap"
+	"go_starter/config"
+	"go_starter/errs"
+	"go_starter/logs"
+	"go_starter/models"
+	"go_starter/repositories"
+	"go_starter/requests"
+	"go_starter/responses"
+	"go_starter/security"
+	"math"
+	"strconv"
+	"time"
+)
+
+type AttendanceService interface {
+	CreateClassroomSessionService(ctx context.Context, request requests.ClassroomSessionRequest) (*responses.ClassroomSessionResponse, error)
+	GetClassroomSessionsService(ctx context.Context, request requests.ClassroomIDRequest) ([]responses.ClassroomSessionResponse, error)
+
+	MarkAttendanceService(ctx context.Context, request requests.BulkAttendanceRequest) (*responses.BulkAttendanceResponse, error)
+	GetSessionAttendanceService(ctx context.Context, request requests.ClassroomSessionIDRequest) (*responses.SessionAttendanceResponse, error)
+
+	GetStudentAttendanceSummaryService(ctx context.Context, request requests.StudentAttendanceRequest) (*responses.StudentAttendanceSummaryResponse, error)
+	GetClassroomAttendanceSummaryService(ctx context.Context, request requests.ClassroomIDRequest) (*responses.ClassroomAttendanceSummaryResponse, error)
+	GetAttendanceAlertsService(ctx context.Context, request requests.AttendanceAlertRequest) ([]responses.AttendanceAlertResponse, error)
+
+	//check-in
+	GenerateCheckInCodeService(ctx context.Context, request requests.CheckInCodeRequest) (*responses.CheckInCodeResponse, error)
+	CheckInService(ctx context.Context, request requests.CheckInRequest) (*responses.CheckInResponse, error)
+}
+
+type attendanceService struct {
+	repositoryAttendance repositories.AttendanceRepository
+	repositoryStudent    repositories.StudentRepository
+}
+
+func (a attendanceService) CreateClassroomSessionService(ctx context.Context, request requests.ClassroomSessionRequest) (*responses.ClassroomSessionResponse, error) {
+	sessionDate, err := time.Parse("02-01-2006", request.SessionDate)
+	if err != nil {
+		return nil, errs.New(errs.CodeInvalidDate).WithField("session_date", "validation.date")
+	}
+	model := models.ClassroomSession{
+		ClassroomID: request.ClassroomID,
+		SessionDate: sessionDate,
+		Topic:       request.Topic,
+	}
+	if err = a.repositoryAttendance.CreateClassroomSessionRepository(ctx, &model); err != nil {
+		return nil, err
+	}
+	return newClassroomSessionResponse(model), nil
+}
+
+func (a attendanceService) GetClassroomSessionsService(ctx context.Context, request requests.ClassroomIDRequest) ([]responses.ClassroomSessionResponse, error) {
+	sessions, err := a.repositoryAttendance.GetClassroomSessionsByClassroomIDRepository(ctx, uint(request.ClassroomID))
+	if err != nil {
+		return nil, err
+	}
+	response := []responses.ClassroomSessionResponse{}
+	for _, session := range sessions {
+		response = append(response, *newClassroomSessionResponse(session))
+	}
+	return response, nil
+}
+
+func (a attendanceService) MarkAttendanceService(ctx context.Context, request requests.BulkAttendanceRequest) (*responses.BulkAttendanceResponse, error) {
+	teacher, err := accessTokenTeacher(ctx, a.repositoryStudent, request.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	session, err := a.repositoryAttendance.GetClassroomSessionByIdRepository(ctx, request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only students enrolled in the session's classroom can be marked
+	enrolled, err := a.repositoryAttendance.GetEnrolledStudentsRepository(ctx, session.ClassroomID)
+	if err != nil {
+		return nil, err
+	}
+	enrolledIDs := map[uint]bool{}
+	for _, student := range enrolled {
+		enrolledIDs[student.ID] = true
+	}
+
+	marked := map[uint]bool{}
+	var attendances []models.Attendance
+	for _, record := range request.Records {
+		if !enrolledIDs[record.StudentID] {
+			return nil, errs.New(errs.CodeStudentNotInClassroom).
+				WithParam("student_id", strconv.FormatUint(uint64(record.StudentID), 10)).
+				WithParam("classroom_id", strconv.FormatUint(uint64(session.ClassroomID), 10))
+		}
+		if marked[record.StudentID] {
+			return nil, errs.New(errs.CodeStudentListedTwice).WithParam("student_id", strconv.FormatUint(uint64(record.StudentID), 10))
+		}
+		marked[record.StudentID] = true
+		attendances = append(attendances, models.Attendance{
+			ClassroomSessionID: session.ID,
+			StudentID:          record.StudentID,
+			Status:             record.Status,
+			Note:               record.Note,
+			MarkedBy:           teacher.ID,
+		})
+	}
+
+	if request.DefaultStatus != "" {
+		for _, student := range enrolled {
+			if marked[student.ID] {
+				continue
+			}
+			attendances = append(attendances, models.Attendance{
+				ClassroomSessionID: session.ID,
+				StudentID:          student.ID,
+				Status:             request.DefaultStatus,
+				MarkedBy:           teacher.ID,
+			})
+		}
+	}
+
+	if len(attendances) == 0 {
+		return nil, errs.New(errs.CodeNoAttendanceRecords)
+	}
+	if err = a.repositoryAttendance.SaveAttendancesRepository(ctx, attendances); err != nil {
+		return nil, err
+	}
+
+	// Re-check absence rates of the students just marked absent
+	classroomSummary, err := a.GetClassroomAttendanceSummaryService(ctx, requests.ClassroomIDRequest{ClassroomID: int(session.ClassroomID)})
+	if err != nil {
+		return nil, err
+	}
+	threshold := absenceThreshold()
+	alerts := []responses.AttendanceAlertResponse{}
+	for _, student := range classroomSummary.Students {
+		if !student.Alert {
+			continue
+		}
+		for _, attendance := range attendances {
+			if attendance.StudentID == student.StudentID && attendance.Status == models.AttendanceAbsent {
+				alert := newAttendanceAlertResponse(student, session.ClassroomID, threshold)
+				notifyAttendanceAlert(alert)
+				alerts = append(alerts, alert)
+				break
+			}
+		}
+	}
+
+	response := &responses.BulkAttendanceResponse{
+		Message: "success",
+		Marked:  len(attendances),
+		Alerts:  alerts,
+	}
+	return response, nil
+}
+
+func (a attendanceService) GetSessionAttendanceService(ctx context.Context, request requests.ClassroomSessionIDRequest) (*responses.SessionAttendanceResponse, error) {
+	session, err := a.repositoryAttendance.GetClassroomSessionByIdRepository(ctx, request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	attendances, err := a.repositoryAttendance.GetAttendancesBySessionIDRepository(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	response := &responses.SessionAttendanceResponse{
+		Session:    *newClassroomSessionResponse(*session),
+		Attendance: []responses.AttendanceResponse{},
+	}
+	for _, attendance := range attendances {
+		response.Attendance = append(response.Attendance, responses.AttendanceResponse{
+			StudentID: attendance.StudentID,
+			StudentNo: attendance.Student.StudentID,
+			Firstname: attendance.Student.Firstname,
+			Lastname:  attendance.Student.Lastname,
+			Status:    attendance.Status,
+			Note:      attendance.Note,
+		})
+	}
+	return response, nil
+}
+
+func (a attendanceService) GetStudentAttendanceSummaryService(ctx context.Context, request requests.StudentAttendanceRequest) (*responses.StudentAttendanceSummaryResponse, error) {
+	attendances, err := a.repositoryAttendance.GetAttendancesByStudentIDRepository(ctx, request.StudentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Group by classroom while keeping the order classrooms were first seen
+	var classroomIDs []uint
+	byClassroom := map[uint][]models.Attendance{}
+	for _, attendance := range attendances {
+		classroomID := attendance.ClassroomSession.ClassroomID
+		if _, ok := byClassroom[classroomID]; !ok {
+			classroomIDs = append(classroomIDs, classroomID)
+		}
+		byClassroom[classroomID] = append(byClassroom[classroomID], attendance)
+	}
+
+	response := &responses.StudentAttendanceSummaryResponse{
+		StudentID:  request.StudentID,
+		Summary:    summarizeAttendance(attendances),
+		Classrooms: []responses.ClassroomAttendance{},
+	}
+	for _, classroomID := range classroomIDs {
+		records := byClassroom[classroomID]
+		response.Classrooms = append(response.Classrooms, responses.ClassroomAttendance{
+			ClassroomID: classroomID,
+			ClassName:   records[0].ClassroomSession.Classroom.ClassName,
+			Summary:     summarizeAttendance(records),
+		})
+	}
+	return response, nil
+}
+
+func (a attendanceService) GetClassroomAttendanceSummaryService(ctx context.Context, request requests.ClassroomIDRequest) (*responses.ClassroomAttendanceSummaryResponse, error) {
+	classroomID := uint(request.ClassroomID)
+	sessions, err := a.repositoryAttendance.GetClassroomSessionsByClassroomIDRepository(ctx, classroomID)
+	if err != nil {
+		return nil, err
+	}
+	enrolled, err := a.repositoryAttendance.GetEnrolledStudentsRepository(ctx, classroomID)
+	if err != nil {
+		return nil, err
+	}
+	attendances, err := a.repositoryAttendance.GetAttendancesByClassroomIDRepository(ctx, classroomID)
+	if err != nil {
+		return nil, err
+	}
+
+	byStudent := map[uint][]models.Attendance{}
+	for _, attendance := range attendances {
+		byStudent[attendance.StudentID] = append(byStudent[attendance.StudentID], attendance)
+	}
+
+	threshold := absenceThreshold()
+	response := &responses.ClassroomAttendanceSummaryResponse{
+		ClassroomID:   classroomID,
+		TotalSessions: len(sessions),
+		Summary:       summarizeAttendance(attendances),
+		Students:      []responses.StudentAttendance{},
+	}
+	for _, student := range enrolled {
+		summary := summarizeAttendance(byStudent[student.ID])
+		response.Students = append(response.Students, responses.StudentAttendance{
+			StudentID: student.ID,
+			StudentNo: student.StudentID,
+			Firstname: student.Firstname,
+			Lastname:  student.Lastname,
+			Summary:   summary,
+			Alert:     summary.TotalSessions > 0 && summary.AbsenceRate >= threshold,
+		})
+	}
+	return response, nil
+}
+
+func (a attendanceService) GetAttendanceAlertsService(ctx context.Context, request requests.AttendanceAlertRequest) ([]responses.AttendanceAlertResponse, error) {
+	classroomSummary, err := a.GetClassroomAttendanceSummaryService(ctx, requests.ClassroomIDRequest{ClassroomID: int(request.ClassroomID)})
+	if err != nil {
+		return nil, err
+	}
+	threshold := request.Threshold
+	if threshold == 0 {
+		threshold = absenceThreshold()
+	}
+	response := []responses.AttendanceAlertResponse{}
+	for _, student := range classroomSummary.Students {
+		if student.Summary.TotalSessions > 0 && student.Summary.AbsenceRate >= threshold {
+			response = append(response, newAttendanceAlertResponse(student, request.ClassroomID, threshold))
+		}
+	}
+	return response, nil
+}
+
+func (a attendanceService) GenerateCheckInCodeService(ctx context.Context, request requests.CheckInCodeRequest) (*responses.CheckInCodeResponse, error) {
+	// A code admits whoever scans it, only the teacher of the classroom shows one
+	teacher, err := accessTokenTeacher(ctx, a.repositoryStudent, request.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	session, err := a.repositoryAttendance.GetClassroomSessionByIdRepository(ctx, request.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	classroom, err := a.repositoryStudent.GetClassroomByIDRepository(ctx, session.ClassroomID)
+	if err != nil {
+		return nil, err
+	}
+	if classroom.TeacherID == nil || *classroom.TeacherID != teacher.ID {
+		return nil, errs.New(errs.CodeNotClassroomTeacher)
+	}
+
+	ttl := checkInTTL()
+	code, expiresAt, err := security.NewCheckInToken(session.ID, ttl)
+	if err != nil {
+		return nil, err
+	}
+	png, err := qrcode.Encode(code, qrcode.Medium, 320)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render check-in QR code: %v", err)
+	}
+
+	response := &responses.CheckInCodeResponse{
+		SessionID: session.ID,
+		Code:      code,
+		QRCode:    "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+		ExpiresAt: expiresAt.Format("02-01-2006 15:04:05"),
+		RefreshIn: checkInRefreshSeconds(),
+	}
+	return response, nil
+}
+
+func (a attendanceService) CheckInService(ctx context.Context, request requests.CheckInRequest) (*responses.CheckInResponse, error) {
+	// The scanning student is identified by their own access token, never by the code
+	accessClaims, err := security.ParseAccessToken(request.AccessToken)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeInvalidAccessToken, err)
+	}
+	student, err := a.repositoryStudent.GetStudentByPhoneRepository(ctx, accessClaims.Id)
+	if err != nil {
+		return nil, err
+	}
+	if student == nil {
+		return nil, errs.New(errs.CodeForbidden)
+	}
+
+	codeClaims, err := security.ParseCheckInToken(request.Code)
+	if errors.Is(err, security.ErrCheckInCodeExpired) {
+		return nil, errs.New(errs.CodeCheckInCodeExpired)
+	}
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeInvalidCheckInCode, err)
+	}
+	session, err := a.repositoryAttendance.GetClassroomSessionByIdRepository(ctx, codeClaims.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	enrolled, err := a.repositoryAttendance.CheckStudentEnrolledRepository(ctx, session.ClassroomID, student.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !enrolled {
+		return nil, errs.New(errs.CodeNotEnrolled)
+	}
+
+	// A student checks in once per session, and a code only as often as
+	// attendance.check_in_max_uses allows, so a replayed or forwarded code is rejected
+	checkedIn, err := a.repositoryAttendance.CheckStudentCheckedInRepository(ctx, session.ID, student.ID)
+	if err != nil {
+		return nil, err
+	}
+	if checkedIn {
+		return nil, errs.New(errs.CodeAlreadyCheckedIn)
+	}
+	checkIn := models.AttendanceCheckIn{
+		ClassroomSessionID: session.ID,
+		StudentID:          student.ID,
+		TokenID:            codeClaims.Id,
+	}
+	// A concurrent check-in of the same student fails on the unique index as
+	// errs.CodeAlreadyCheckedIn
+	attendance, err := a.repositoryAttendance.CheckInRepository(ctx, &checkIn, checkInMaxUses())
+	if err != nil {
+		return nil, err
+	}
+	if attendance == nil {
+		return nil, errs.New(errs.CodeCheckInCodeUsed)
+	}
+
+	response := &responses.CheckInResponse{
+		SessionID:   session.ID,
+		StudentID:   student.ID,
+		Status:      attendance.Status,
+		CheckedInAt: checkIn.CreatedAt.Format("02-01-2006 15:04:05"),
+	}
+	return response, nil
+}
+
+// accessTokenTeacher returns the teacher accessToken was issued to, marks and scores are
+// recorded as them
+func accessTokenTeacher(ctx context.Context, repositoryStudent repositories.StudentRepository, accessToken string) (*models.Teacher, error) {
+	claims, err := security.ParseAccessToken(accessToken)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeUnauthorized, err)
+	}
+	teacher, err := repositoryStudent.GetTeacherByPhoneRepository(ctx, claims.Id)
+	if err != nil {
+		return nil, err
+	}
+	if teacher == nil {
+		return nil, errs.New(errs.CodeForbidden)
+	}
+	return teacher, nil
+}
+
+// checkInTTL is how long a check-in code stays valid
+func checkInTTL() time.Duration {
+	seconds, err := strconv.Atoi(config.GetEnv("attendance.check_in_ttl_seconds", "30"))
+	if err != nil || seconds <= 0 {
+		logs.Error("invalid attendance.check_in_ttl_seconds")
+		return 30 * time.Second
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// checkInRefreshSeconds is how often the teacher's screen shows a new code
+func checkInRefreshSeconds() int {
+	seconds, err := strconv.Atoi(config.GetEnv("attendance.check_in_refresh_seconds", "3"))
+	if err != nil || seconds <= 0 {
+		logs.Error("invalid attendance.check_in_refresh_seconds")
+		return 3
+	}
+	return seconds
+}
+
+// checkInMaxUses is how many check-ins one code accepts, 0 for no limit
+func checkInMaxUses() int {
+	uses, err := strconv.Atoi(config.GetEnv("attendance.check_in_max_uses", "0"))
+	if err != nil || uses < 0 {
+		logs.Error("invalid attendance.check_in_max_uses")
+		return 0
+	}
+	return uses
+}
+
+// absenceThreshold reads the absence rate (in percent) at which a student is flagged
+func absenceThreshold() float64 {
+	threshold, err := strconv.ParseFloat(config.GetEnv("attendance.absence_threshold", "20"), 64)
+	if err != nil {
+		logs.Error(errors.Wrap(err, "invalid attendance.absence_threshold"))
+		return 20
+	}
+	return threshold
+}
+
+func notifyAttendanceAlert(alert responses.AttendanceAlertResponse) {
+	logs.Info("ATTENDANCE_ABSENCE_THRESHOLD_EXCEEDED",
+		zap.Uint("student_id", alert.StudentID),
+		zap.Uint("classroom_id", alert.ClassroomID),
+		zap.Float64("absence_rate", alert.AbsenceRate),
+		zap.Float64("threshold", alert.Threshold),
+	)
+}
+
+func summarizeAttendance(attendances []models.Attendance) responses.AttendanceSummary {
+	summary := responses.AttendanceSummary{TotalSessions: len(attendances)}
+	for _, attendance := range attendances {
+		switch attendance.Status {
+		case models.AttendancePresent:
+			summary.Present++
+		case models.AttendanceAbsent:
+			summary.Absent++
+		case models.AttendanceLate:
+			summary.Late++
+		case models.AttendanceExcused:
+			summary.Excused++
+		}
+	}
+	if summary.TotalSessions > 0 {
+		total := float64(summary.TotalSessions)
+		summary.AttendanceRate = percentage(float64(summary.Present+summary.Late), total)
+		summary.AbsenceRate = percentage(float64(summary.Absent), total)
+	}
+	return summary
+}
+
+func percentage(value, total float64) float64 {
+	return math.Round(value/total*10000) / 100
+}
+
+func newClassroomSessionResponse(session models.ClassroomSession) *responses.ClassroomSessionResponse {
+	return &responses.ClassroomSessionResponse{
+		ID:          session.ID,
+		ClassroomID: session.ClassroomID,
+		SessionDate: session.SessionDate.Format("02-01-2006"),
+		Topic:       session.Topic,
+	}
+}
+
+func newAttendanceAlertResponse(student responses.StudentAttendance, classroomID uint, threshold float64) responses.AttendanceAlertResponse {
+	return responses.AttendanceAlertResponse{
+		StudentID:   student.StudentID,
+		StudentNo:   student.StudentNo,
+		Firstname:   student.Firstname,
+		Lastname:    student.Lastname,
+		ClassroomID: classroomID,
+		AbsenceRate: student.Summary.AbsenceRate,
+		Threshold:   threshold,
+	}
+}
+
+func NewAttendanceService(
+	repositoryAttendance repositories.AttendanceRepository,
+	repositoryStudent repositories.StudentRepository,
+) AttendanceService {
+	return &attendanceService{
+		repositoryAttendance: repositoryAttendance,
+		repositoryStudent:    repositoryStudent,
+	}
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"go_starter/errs"
+	"go_starter/models"
+	"go_starter/repositories"
+	"go_starter/requests"
+	"go_starter/security"
+	"testing"
+	"time"
+)
+
+func TestGenerateCheckInCodeService(t *testing.T) {
+	db := openTestDatabase(t)
+	security.JwtCheckInSecret = []byte("0123456789abcdef0123456789abcdef-test")
+	teachers := []models.Teacher{{Phone: "2055559998"}, {Phone: "2055559999"}}
+	create(t, db, &teachers, &models.Student{StudentID: "STU001", Phone: "2055550001"})
+	classroom := models.Classroom{ClassName: "CE1", ClassYear: 2024, SubjectName: "Networks", TeacherID: &teachers[1].ID}
+	create(t, db, &classroom)
+	session := models.ClassroomSession{ClassroomID: classroom.ID, SessionDate: time.Now(), Topic: "Routing"}
+	create(t, db, &session)
+	service := NewAttendanceService(repositories.NewAttendanceRepository(db), repositories.NewStudentRepository(db))
+
+	token := func(phone string) string {
+		accessToken, err := security.NewAccessToken(phone)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return accessToken
+	}
+	for _, test := range []struct {
+		name        string
+		accessToken string
+		code        errs.Code
+	}{
+		{"no access token", "", errs.CodeUnauthorized},
+		{"forged access token", "not-a-token", errs.CodeUnauthorized},
+		{"student", token("2055550001"), errs.CodeForbidden},
+		{"teacher of another classroom", token("2055559998"), errs.CodeNotClassroomTeacher},
+	} {
+		_, err := service.GenerateCheckInCodeService(context.Background(), requests.CheckInCodeRequest{SessionID: session.ID, AccessToken: test.accessToken})
+		if errs.From(err).Code != test.code {
+			t.Errorf("%s: mint = %v, want %s", test.name, err, test.code)
+		}
+	}
+
+	response, err := service.GenerateCheckInCodeService(context.Background(), requests.CheckInCodeRequest{SessionID: session.ID, AccessToken: token("2055559999")})
+	if err != nil {
+		t.Fatalf("mint by the teacher of the classroom: %v", err)
+	}
+	if claims, err := security.ParseCheckInToken(response.Code); err != nil || claims.SessionID != session.ID {
+		t.Fatalf("check-in code = %+v, %v", claims, err)
+	}
+}
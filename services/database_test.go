@@ -0,0 +1,41 @@
+package services
+
+import (
+	"go_starter/database"
+	"go_starter/migrations"
+	"gorm.io/gorm"
+	"testing"
+)
+
+// openTestDatabase opens an in-memory sqlite database migrated to the current schema,
+// it lives as long as the test
+func openTestDatabase(t *testing.T) *gorm.DB {
+	t.Helper()
+	t.Setenv("SQLITE_PATH", ":memory:")
+	db, err := database.Open(database.DriverSqlite)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err = migrator.Up(0); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	})
+	return db
+}
+
+// create inserts fixture rows and fails the test on any error
+func create(t *testing.T, db *gorm.DB, values ...interface{}) {
+	t.Helper()
+	for _, value := range values {
+		if err := db.Create(value).Error; err != nil {
+			t.Fatalf("create %T: %v", value, err)
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"go_starter/config"
+	"go_starter/errs"
+	"go_starter/logs"
+	"go_starter/repositories"
+	"go_starter/requests"
+	"go_starter/responses"
+	"go_starter/security"
+	"go_starter/storage"
+	"go_starter/trails"
+	"mime"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type FileService interface {
+	GetFileService(ctx context.Context, key string) (*responses.FileResponse, error)
+	GetImageService(ctx context.Context, request requests.ImageRequest) (*responses.FileResponse, error)
+	CollectStorageGarbageService(ctx context.Context, request requests.StorageGCRequest) (*responses.StorageGCResponse, error)
+}
+
+type fileService struct {
+	repositoryFile    repositories.FileRepository
+	repositoryStudent repositories.StudentRepository
+	repositoryUser    repositories.UserRepository
+	storage           storage.Storage
+}
+
+func (f fileService) GetFileService(ctx context.Context, key string) (*responses.FileResponse, error) {
+	content, err := f.storage.Get(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, errs.New(errs.CodeFileNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = http.DetectContentType(content)
+	}
+	response := &responses.FileResponse{
+		FileName:    path.Base(key),
+		ContentType: contentType,
+		Content:     content,
+	}
+	return response, nil
+}
+
+// GetImageService serves an image or one of its variants, a width resizes on the fly.
+// Variants are rendered from the original on first request and cached in storage.
+func (f fileService) GetImageService(ctx context.Context, request requests.ImageRequest) (*responses.FileResponse, error) {
+	cacheControl, err := f.authorizeImage(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	key, width := request.Key, request.Width
+	if width < 0 {
+		return nil, errs.New(errs.CodeInvalidImageWidth)
+	}
+	if width > 0 {
+		if _, _, isVariant := trails.ParseImageVariantKey(key); isVariant {
+			return nil, errs.New(errs.CodeImageVariantResize)
+		}
+		key = trails.ImageVariantKey(key, trails.ResizeVariant(width))
+	}
+
+	content, err := f.storage.Get(ctx, key)
+	original, variant, isVariant := trails.ParseImageVariantKey(key)
+	if errors.Is(err, storage.ErrNotFound) && isVariant {
+		content, err = f.renderImageVariant(ctx, key, original, variant)
+	}
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, errs.New(errs.CodeFileNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Variants of legacy photos keep their extension, so sniff the rendered content
+	response := &responses.FileResponse{
+		FileName:     path.Base(key),
+		ContentType:  http.DetectContentType(content),
+		CacheControl: cacheControl,
+		Content:      content,
+	}
+	return response, nil
+}
+
+// authorizeImage accepts a valid signed URL or an access token. Teachers and staff users
+// may view every photo, students their own photo and teacher and user avatars. It
+// returns the Cache-Control for the response.
+func (f fileService) authorizeImage(ctx context.Context, request requests.ImageRequest) (string, error) {
+	if !strings.HasPrefix(request.Key, studentImageDirectory+"/") && !strings.HasPrefix(request.Key, avatarDirectory+"/") {
+		return "", errs.New(errs.CodeFileNotFound)
+	}
+	if request.Signature != "" {
+		expiresAt, err := security.VerifyURLPath("/"+request.Key, request.Expires, request.Signature)
+		if errors.Is(err, security.ErrSignedURLExpired) {
+			return "", errs.New(errs.CodeSignedURLExpired)
+		}
+		if err != nil {
+			return "", errs.Wrap(errs.CodeInvalidSignedURL, err)
+		}
+		// Browsers may keep the photo for as long as the URL stays valid
+		return fmt.Sprintf("private, max-age=%d", int(time.Until(expiresAt).Seconds())), nil
+	}
+	if request.AccessToken == "" {
+		return "", errs.New(errs.CodeCredentialsRequired)
+	}
+
+	claims, err := security.ParseAccessToken(request.AccessToken)
+	if err != nil {
+		return "", errs.Wrap(errs.CodeInvalidAccessToken, err)
+	}
+	// Access depends on the caller, so shared caches must not keep the photo
+	cacheControl := "private, no-cache"
+
+	if teacher, err := f.repositoryStudent.GetTeacherByPhoneRepository(ctx, claims.Id); err != nil {
+		return "", err
+	} else if teacher != nil {
+		return cacheControl, nil
+	}
+	if user, err := f.repositoryUser.GetByEmailRepository(ctx, claims.Id); err != nil {
+		return "", err
+	} else if user != nil {
+		return cacheControl, nil
+	}
+
+	student, err := f.repositoryStudent.GetStudentByPhoneRepository(ctx, claims.Id)
+	if err != nil {
+		return "", err
+	}
+	if student != nil && strings.HasPrefix(request.Key, avatarDirectory+"/") {
+		return cacheControl, nil
+	}
+	key := request.Key
+	if original, _, isVariant := trails.ParseImageVariantKey(key); isVariant {
+		key = original
+	}
+	if student == nil || student.Image == "" || storage.KeyFromPath(student.Image) != key {
+		return "", errs.New(errs.CodePhotoForbidden)
+	}
+	return cacheControl, nil
+}
+
+// collectedDirectories are the storage prefixes whose files are tracked by the database
+var collectedDirectories = []string{studentImageDirectory, avatarDirectory, studentDocumentDirectory}
+
+func isCollected(key string) bool {
+	for _, prefix := range collectedDirectories {
+		if strings.HasPrefix(key, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// CollectStorageGarbageService deletes unreferenced file rows, stored files no row points
+// at and leftover temp files, and reports rows whose file is missing from storage
+func (f fileService) CollectStorageGarbageService(ctx context.Context, request requests.StorageGCRequest) (*responses.StorageGCResponse, error) {
+	cutoff := time.Now().Add(-request.GracePeriod)
+	response := &responses.StorageGCResponse{
+		DryRun:         request.DryRun,
+		DeletedObjects: []string{},
+		DeletedFiles:   []string{},
+		MissingFiles:   []string{},
+	}
+
+	// Rows nothing references anymore
+	unreferenced, err := f.repositoryFile.GetUnreferencedFileObjectsRepository(ctx, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	// A dry run only reports what it would release, the files of those rows are listed
+	// below as a real run deletes them while releasing
+	released := map[string]bool{}
+	for _, object := range unreferenced {
+		if request.DryRun {
+			released[object.StorageKey] = true
+			response.DeletedObjects = append(response.DeletedObjects, object.StorageKey)
+			continue
+		}
+		var deleted []string
+		release, err := f.repositoryFile.ReleaseFileObjectRepository(ctx, object.StorageKey, func() (err error) {
+			deleted, err = deleteStoredFiles(ctx, f.storage, append(imageVariantKeys(object.StorageKey), object.StorageKey))
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		if release {
+			response.DeletedObjects = append(response.DeletedObjects, object.StorageKey)
+			response.DeletedFiles = append(response.DeletedFiles, deleted...)
+		}
+	}
+
+	// Everything still referenced, student rows written before reference tracking included
+	referenced := map[string]bool{}
+	objects, err := f.repositoryFile.GetFileObjectsRepository(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, object := range objects {
+		if !released[object.StorageKey] {
+			referenced[object.StorageKey] = true
+		}
+	}
+	images, err := f.repositoryFile.GetStudentImageKeysRepository(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, image := range images {
+		referenced[storage.KeyFromPath(image)] = true
+	}
+
+	var stored []storage.Object
+	for _, prefix := range collectedDirectories {
+		objects, err := f.storage.List(ctx, prefix+"/")
+		if err != nil {
+			return nil, err
+		}
+		stored = append(stored, objects...)
+	}
+	response.Scanned = len(stored)
+	present := map[string]bool{}
+	for _, object := range stored {
+		present[object.Key] = true
+		key := object.Key
+		if original, _, isVariant := trails.ParseImageVariantKey(key); isVariant {
+			key = original
+		}
+		isTemp := strings.HasPrefix(path.Base(key), storage.TempFilePrefix)
+		if !isTemp && referenced[key] {
+			continue
+		}
+		// Files of released rows go regardless of age, the rest only after the grace period
+		if !released[key] && !object.ModTime.Before(cutoff) {
+			continue
+		}
+		if !request.DryRun {
+			if err = f.storage.Delete(ctx, object.Key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+				return nil, err
+			}
+		}
+		response.DeletedFiles = append(response.DeletedFiles, object.Key)
+	}
+
+	for key := range referenced {
+		if isCollected(key) && !present[key] {
+			response.MissingFiles = append(response.MissingFiles, key)
+		}
+	}
+	sort.Strings(response.MissingFiles)
+	return response, nil
+}
+
+// releaseStoredFile deletes a stored file and the files derived from it once no row
+// references it, failures are logged and left for the storage gc
+func releaseStoredFile(ctx context.Context, repositoryFile repositories.FileRepository, fileStorage storage.Storage, key string, derived ...string) {
+	// The change it cleans up after is committed, a client hanging up now must not
+	// leave the file behind
+	ctx = context.WithoutCancel(ctx)
+	_, err := repositoryFile.ReleaseFileObjectRepository(ctx, key, func() error {
+		_, err := deleteStoredFiles(ctx, fileStorage, append(derived, key))
+		return err
+	})
+	if err != nil {
+		logs.Error(err)
+	}
+}
+
+// deleteStoredFiles deletes keys, skipping those already gone, and returns the ones it deleted
+func deleteStoredFiles(ctx context.Context, fileStorage storage.Storage, keys []string) ([]string, error) {
+	var deleted []string
+	for _, key := range keys {
+		err := fileStorage.Delete(ctx, key)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, key)
+	}
+	return deleted, nil
+}
+
+// signedImageURL returns an absolute URL of a stored image that stays valid for at least
+// storage.signed_url_ttl_seconds. Expiry is aligned to that window so the URL, and
+// the browser cache entry behind it, stays the same across requests within it.
+func signedImageURL(key string) string {
+	ttl := time.Duration(signedURLTTLSeconds()) * time.Second
+	expiresAt := time.Now().Truncate(ttl).Add(2 * ttl)
+	urlPath := "/" + key
+	baseURL := config.GetEnv("app.public_url", "http://localhost:"+config.Env("app.port"))
+	return strings.TrimRight(baseURL, "/") + urlPath + "?" + security.SignURLPath(urlPath, expiresAt)
+}
+
+func signedURLTTLSeconds() int {
+	ttl, err := strconv.Atoi(config.GetEnv("storage.signed_url_ttl_seconds", "3600"))
+	if err != nil || ttl <= 0 {
+		return 3600
+	}
+	return ttl
+}
+
+func (f fileService) renderImageVariant(ctx context.Context, key, original, variant string) ([]byte, error) {
+	originalData, err := f.storage.Get(ctx, original)
+	if err != nil {
+		return nil, err
+	}
+	content, err := trails.RenderImageVariant(originalData, variant)
+	if err != nil {
+		return nil, err
+	}
+	if err = f.storage.Put(ctx, key, content, trails.NormalizedImageContentType); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+func NewFileService(
+	repositoryFile repositories.FileRepository,
+	repositoryStudent repositories.StudentRepository,
+	repositoryUser repositories.UserRepository,
+	storage storage.Storage,
+) FileService {
+	return &fileService{
+		repositoryFile:    repositoryFile,
+		repositoryStudent: repositoryStudent,
+		repositoryUser:    repositoryUser,
+		storage:           storage,
+	}
+}
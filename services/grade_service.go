@@ -0,0 +1,497 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"go_starter/errs"
+	"go_starter/models"
+	"go_starter/repositories"
+	"go_starter/requests"
+	"go_starter/responses"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type GradeService interface {
+	CreateTermService(ctx context.Context, request requests.TermRequest) (*responses.TermResponse, error)
+	FinalizeTermService(ctx context.Context, request requests.TermIDRequest) (*responses.TermResponse, error)
+
+	CreateGradingScaleService(ctx context.Context, request requests.GradingScaleRequest) (*responses.GradingScaleResponse, error)
+	GetGradingScalesService(ctx context.Context) ([]responses.GradingScaleResponse, error)
+
+	CreateAssessmentService(ctx context.Context, request requests.AssessmentRequest) (*responses.AssessmentResponse, error)
+	GetClassroomAssessmentsService(ctx context.Context, request requests.ClassroomTermRequest) ([]responses.AssessmentResponse, error)
+	EnterScoresService(ctx context.Context, request requests.BulkScoreRequest) (*responses.MessageResponse, error)
+
+	GetClassroomGradesService(ctx context.Context, request requests.ClassroomTermRequest) (*responses.ClassroomGradesResponse, error)
+	GetStudentGradesService(ctx context.Context, request requests.StudentGradeRequest) (*responses.StudentGradesResponse, error)
+}
+
+type gradeService struct {
+	repositoryGrade   repositories.GradeRepository
+	repositoryStudent repositories.StudentRepository
+	unitOfWork        repositories.UnitOfWork
+}
+
+// defaultGradeBoundaries is used when no grading scale has been configured
+var defaultGradeBoundaries = []models.GradeBoundary{
+	{Letter: "A", MinPercentage: 80, GradePoint: 4.0},
+	{Letter: "B+", MinPercentage: 75, GradePoint: 3.5},
+	{Letter: "B", MinPercentage: 70, GradePoint: 3.0},
+	{Letter: "C+", MinPercentage: 65, GradePoint: 2.5},
+	{Letter: "C", MinPercentage: 60, GradePoint: 2.0},
+	{Letter: "D+", MinPercentage: 55, GradePoint: 1.5},
+	{Letter: "D", MinPercentage: 50, GradePoint: 1.0},
+	{Letter: "F", MinPercentage: 0, GradePoint: 0},
+}
+
+func (g gradeService) CreateTermService(ctx context.Context, request requests.TermRequest) (*responses.TermResponse, error) {
+	startDate, err := time.Parse("02-01-2006", request.StartDate)
+	if err != nil {
+		return nil, errs.New(errs.CodeInvalidDate).WithField("start_date", "validation.date")
+	}
+	endDate, err := time.Parse("02-01-2006", request.EndDate)
+	if err != nil {
+		return nil, errs.New(errs.CodeInvalidDate).WithField("end_date", "validation.date")
+	}
+	if endDate.Before(startDate) {
+		return nil, errs.New(errs.CodeInvalidDateRange)
+	}
+	if request.GradingScaleID != 0 {
+		if _, err = g.repositoryGrade.GetGradingScaleByIdRepository(ctx, request.GradingScaleID); err != nil {
+			return nil, err
+		}
+	}
+	model := models.Term{
+		Name:           request.Name,
+		StartDate:      startDate,
+		EndDate:        endDate,
+		GradingScaleID: request.GradingScaleID,
+	}
+	if err = g.repositoryGrade.CreateTermRepository(ctx, &model); err != nil {
+		return nil, err
+	}
+	return newTermResponse(model), nil
+}
+
+func (g gradeService) FinalizeTermService(ctx context.Context, request requests.TermIDRequest) (*responses.TermResponse, error) {
+	var term *models.Term
+	err := g.unitOfWork.Do(ctx, func(tx repositories.TransactionRepositories) error {
+		// The grades are computed under the lock of the term, scores entered meanwhile
+		// wait for the finalize and are refused
+		var err error
+		term, err = tx.Grade.LockTermRepository(ctx, request.TermID)
+		if err != nil {
+			return err
+		}
+		if term.FinalizedAt != nil {
+			return errs.New(errs.CodeTermFinalized)
+		}
+		finalGrades, err := gradeService{repositoryGrade: tx.Grade, repositoryStudent: tx.Student}.finalGrades(ctx, term)
+		if err != nil {
+			return err
+		}
+		return tx.Grade.FinalizeTermRepository(ctx, term, finalGrades)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newTermResponse(*term), nil
+}
+
+// finalGrades computes the grade of every student in every classroom with assessments in term
+func (g gradeService) finalGrades(ctx context.Context, term *models.Term) ([]models.FinalGrade, error) {
+	scale, err := g.gradingScale(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+	assessments, err := g.repositoryGrade.GetAssessmentsByTermIDRepository(ctx, term.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var classroomIDs []uint
+	byClassroom := map[uint][]models.Assessment{}
+	for _, assessment := range assessments {
+		if _, ok := byClassroom[assessment.ClassroomID]; !ok {
+			classroomIDs = append(classroomIDs, assessment.ClassroomID)
+		}
+		byClassroom[assessment.ClassroomID] = append(byClassroom[assessment.ClassroomID], assessment)
+	}
+
+	var finalGrades []models.FinalGrade
+	for _, classroomID := range classroomIDs {
+		grades, err := g.computeClassroomGrades(ctx, classroomID, byClassroom[classroomID], scale)
+		if err != nil {
+			return nil, err
+		}
+		for _, grade := range grades {
+			finalGrades = append(finalGrades, models.FinalGrade{
+				StudentID:   grade.StudentID,
+				ClassroomID: classroomID,
+				TermID:      term.ID,
+				Percentage:  grade.Percentage,
+				Letter:      grade.Letter,
+				GradePoint:  grade.GradePoint,
+			})
+		}
+	}
+	return finalGrades, nil
+}
+
+func (g gradeService) CreateGradingScaleService(ctx context.Context, request requests.GradingScaleRequest) (*responses.GradingScaleResponse, error) {
+	letters := map[string]bool{}
+	hasZero := false
+	model := models.GradingScale{
+		Name:      request.Name,
+		IsDefault: request.IsDefault,
+	}
+	for _, grade := range request.Grades {
+		if letters[grade.Letter] {
+			return nil, errs.New(errs.CodeDuplicateGradeLetter).WithParam("letter", grade.Letter)
+		}
+		letters[grade.Letter] = true
+		if grade.MinPercentage == 0 {
+			hasZero = true
+		}
+		model.Grades = append(model.Grades, models.GradeBoundary{
+			Letter:        grade.Letter,
+			MinPercentage: grade.MinPercentage,
+			GradePoint:    grade.GradePoint,
+		})
+	}
+	// Every percentage has to map to some letter
+	if !hasZero {
+		return nil, errs.New(errs.CodeGradingScaleIncomplete)
+	}
+	if err := g.repositoryGrade.CreateGradingScaleRepository(ctx, &model); err != nil {
+		return nil, err
+	}
+	return newGradingScaleResponse(model), nil
+}
+
+func (g gradeService) GetGradingScalesService(ctx context.Context) ([]responses.GradingScaleResponse, error) {
+	scales, err := g.repositoryGrade.GetGradingScalesRepository(ctx)
+	if err != nil {
+		return nil, err
+	}
+	response := []responses.GradingScaleResponse{}
+	for _, scale := range scales {
+		response = append(response, *newGradingScaleResponse(scale))
+	}
+	return response, nil
+}
+
+func (g gradeService) CreateAssessmentService(ctx context.Context, request requests.AssessmentRequest) (*responses.AssessmentResponse, error) {
+	term, err := g.repositoryGrade.GetTermByIdRepository(ctx, request.TermID)
+	if err != nil {
+		return nil, err
+	}
+	if term.FinalizedAt != nil {
+		return nil, errs.New(errs.CodeTermFinalized)
+	}
+	model := models.Assessment{
+		ClassroomID: request.ClassroomID,
+		TermID:      request.TermID,
+		Title:       request.Title,
+		Type:        request.Type,
+		Weight:      request.Weight,
+		MaxScore:    request.MaxScore,
+	}
+	if err = g.repositoryGrade.CreateAssessmentRepository(ctx, &model); err != nil {
+		return nil, err
+	}
+	return newAssessmentResponse(model), nil
+}
+
+func (g gradeService) GetClassroomAssessmentsService(ctx context.Context, request requests.ClassroomTermRequest) ([]responses.AssessmentResponse, error) {
+	assessments, err := g.repositoryGrade.GetAssessmentsRepository(ctx, request.ClassroomID, request.TermID)
+	if err != nil {
+		return nil, err
+	}
+	response := []responses.AssessmentResponse{}
+	for _, assessment := range assessments {
+		response = append(response, *newAssessmentResponse(assessment))
+	}
+	return response, nil
+}
+
+func (g gradeService) EnterScoresService(ctx context.Context, request requests.BulkScoreRequest) (*responses.MessageResponse, error) {
+	teacher, err := accessTokenTeacher(ctx, g.repositoryStudent, request.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	assessment, err := g.repositoryGrade.GetAssessmentByIdRepository(ctx, request.AssessmentID)
+	if err != nil {
+		return nil, err
+	}
+	if assessment.Term.FinalizedAt != nil {
+		return nil, errs.New(errs.CodeTermFinalized)
+	}
+
+	studentClassrooms, err := g.repositoryStudent.GetStudentClassroomByClassroomIDRepository(ctx, assessment.ClassroomID)
+	if err != nil {
+		return nil, err
+	}
+	enrolled := map[uint]bool{}
+	for _, sc := range studentClassrooms {
+		enrolled[sc.StudentID] = true
+	}
+
+	seen := map[uint]bool{}
+	var scores []models.AssessmentScore
+	for _, score := range request.Scores {
+		if !enrolled[score.StudentID] {
+			return nil, errs.New(errs.CodeStudentNotInClassroom).
+				WithParam("student_id", strconv.FormatUint(uint64(score.StudentID), 10)).
+				WithParam("classroom_id", strconv.FormatUint(uint64(assessment.ClassroomID), 10))
+		}
+		if seen[score.StudentID] {
+			return nil, errs.New(errs.CodeStudentListedTwice).WithParam("student_id", strconv.FormatUint(uint64(score.StudentID), 10))
+		}
+		seen[score.StudentID] = true
+		if score.Score > assessment.MaxScore {
+			return nil, errs.New(errs.CodeScoreAboveMax).
+				WithParam("student_id", strconv.FormatUint(uint64(score.StudentID), 10)).
+				WithParam("max_score", fmt.Sprint(assessment.MaxScore))
+		}
+		scores = append(scores, models.AssessmentScore{
+			AssessmentID: assessment.ID,
+			StudentID:    score.StudentID,
+			Score:        score.Score,
+			GradedBy:     teacher.ID,
+		})
+	}
+	// The term is checked again under its lock, a finalize may have started meanwhile
+	if err = g.repositoryGrade.SaveAssessmentScoresRepository(ctx, assessment.TermID, scores); err != nil {
+		return nil, err
+	}
+	response := &responses.MessageResponse{Message: "success"}
+	return response, nil
+}
+
+func (g gradeService) GetClassroomGradesService(ctx context.Context, request requests.ClassroomTermRequest) (*responses.ClassroomGradesResponse, error) {
+	term, err := g.repositoryGrade.GetTermByIdRepository(ctx, request.TermID)
+	if err != nil {
+		return nil, err
+	}
+	response := &responses.ClassroomGradesResponse{
+		ClassroomID: request.ClassroomID,
+		TermID:      term.ID,
+		Locked:      term.FinalizedAt != nil,
+		Grades:      []responses.StudentGrade{},
+	}
+
+	// Once a term is finalized the stored grades are the record, not a recomputation
+	if term.FinalizedAt != nil {
+		finalGrades, err := g.repositoryGrade.GetFinalGradesRepository(ctx, request.ClassroomID, term.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, grade := range finalGrades {
+			response.Grades = append(response.Grades, responses.StudentGrade{
+				StudentID:  grade.StudentID,
+				StudentNo:  grade.Student.StudentID,
+				Firstname:  grade.Student.Firstname,
+				Lastname:   grade.Student.Lastname,
+				Percentage: grade.Percentage,
+				Letter:     grade.Letter,
+				GradePoint: grade.GradePoint,
+			})
+		}
+		return response, nil
+	}
+
+	scale, err := g.gradingScale(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+	assessments, err := g.repositoryGrade.GetAssessmentsRepository(ctx, request.ClassroomID, term.ID)
+	if err != nil {
+		return nil, err
+	}
+	grades, err := g.computeClassroomGrades(ctx, request.ClassroomID, assessments, scale)
+	if err != nil {
+		return nil, err
+	}
+	response.Grades = append(response.Grades, grades...)
+	return response, nil
+}
+
+func (g gradeService) GetStudentGradesService(ctx context.Context, request requests.StudentGradeRequest) (*responses.StudentGradesResponse, error) {
+	finalGrades, err := g.repositoryGrade.GetFinalGradesByStudentIDRepository(ctx, request.StudentID)
+	if err != nil {
+		return nil, err
+	}
+	response := &responses.StudentGradesResponse{
+		StudentID: request.StudentID,
+		Grades:    []responses.FinalGradeResponse{},
+	}
+	for _, grade := range finalGrades {
+		response.Grades = append(response.Grades, responses.FinalGradeResponse{
+			ClassroomID: grade.ClassroomID,
+			ClassName:   grade.Classroom.ClassName,
+			SubjectName: grade.Classroom.SubjectName,
+			TermID:      grade.TermID,
+			TermName:    grade.Term.Name,
+			Percentage:  grade.Percentage,
+			Letter:      grade.Letter,
+			GradePoint:  grade.GradePoint,
+		})
+	}
+	return response, nil
+}
+
+// computeClassroomGrades weights each assessment's percentage by its weight.
+// An assessment without a score for a student counts as zero.
+func (g gradeService) computeClassroomGrades(ctx context.Context, classroomID uint, assessments []models.Assessment, boundaries []models.GradeBoundary) ([]responses.StudentGrade, error) {
+	studentClassrooms, err := g.repositoryStudent.GetStudentClassroomByClassroomIDRepository(ctx, classroomID)
+	if err != nil {
+		return nil, err
+	}
+	var assessmentIDs []uint
+	for _, assessment := range assessments {
+		assessmentIDs = append(assessmentIDs, assessment.ID)
+	}
+	scores, err := g.repositoryGrade.GetScoresByAssessmentIDsRepository(ctx, assessmentIDs)
+	if err != nil {
+		return nil, err
+	}
+	scoreOf := map[uint]map[uint]float64{}
+	for _, score := range scores {
+		if scoreOf[score.StudentID] == nil {
+			scoreOf[score.StudentID] = map[uint]float64{}
+		}
+		scoreOf[score.StudentID][score.AssessmentID] = score.Score
+	}
+
+	var grades []responses.StudentGrade
+	for _, sc := range studentClassrooms {
+		grade := responses.StudentGrade{
+			StudentID: sc.StudentID,
+			StudentNo: sc.Student.StudentID,
+			Firstname: sc.Student.Firstname,
+			Lastname:  sc.Student.Lastname,
+			Scores:    []responses.AssessmentScore{},
+		}
+		for _, assessment := range assessments {
+			score, graded := scoreOf[sc.StudentID][assessment.ID]
+			grade.Scores = append(grade.Scores, responses.AssessmentScore{
+				AssessmentID: assessment.ID,
+				Score:        score,
+				MaxScore:     assessment.MaxScore,
+				Weight:       assessment.Weight,
+				Graded:       graded,
+			})
+		}
+		grade.Percentage = weightedPercentage(assessments, scoreOf[sc.StudentID])
+		letter := letterGrade(boundaries, grade.Percentage)
+		grade.Letter = letter.Letter
+		grade.GradePoint = letter.GradePoint
+		grades = append(grades, grade)
+	}
+	return grades, nil
+}
+
+// weightedPercentage is the percentage of scores, the score of each assessment by its id
+// weighted by the share of its weight in the total. Weights need not add up to 100, an
+// assessment without a score counts as zero.
+func weightedPercentage(assessments []models.Assessment, scores map[uint]float64) float64 {
+	var weighted, totalWeight float64
+	for _, assessment := range assessments {
+		totalWeight += assessment.Weight
+		weighted += assessment.Weight * scores[assessment.ID] / assessment.MaxScore
+	}
+	if totalWeight <= 0 {
+		return 0
+	}
+	return percentage(weighted, totalWeight)
+}
+
+// gradingScale returns the term's scale, falling back to the default one, highest boundary first
+func (g gradeService) gradingScale(ctx context.Context, term *models.Term) ([]models.GradeBoundary, error) {
+	var scale *models.GradingScale
+	var err error
+	if term.GradingScaleID != 0 {
+		scale, err = g.repositoryGrade.GetGradingScaleByIdRepository(ctx, term.GradingScaleID)
+	} else {
+		scale, err = g.repositoryGrade.GetDefaultGradingScaleRepository(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	boundaries := append([]models.GradeBoundary{}, defaultGradeBoundaries...)
+	if scale != nil && len(scale.Grades) > 0 {
+		boundaries = append([]models.GradeBoundary{}, scale.Grades...)
+	}
+	sort.Slice(boundaries, func(i, j int) bool {
+		return boundaries[i].MinPercentage > boundaries[j].MinPercentage
+	})
+	return boundaries, nil
+}
+
+func letterGrade(boundaries []models.GradeBoundary, percentage float64) models.GradeBoundary {
+	for _, boundary := range boundaries {
+		if percentage >= boundary.MinPercentage {
+			return boundary
+		}
+	}
+	return boundaries[len(boundaries)-1]
+}
+
+func newTermResponse(term models.Term) *responses.TermResponse {
+	response := &responses.TermResponse{
+		ID:             term.ID,
+		Name:           term.Name,
+		StartDate:      term.StartDate.Format("02-01-2006"),
+		EndDate:        term.EndDate.Format("02-01-2006"),
+		GradingScaleID: term.GradingScaleID,
+		Finalized:      term.FinalizedAt != nil,
+	}
+	if term.FinalizedAt != nil {
+		response.FinalizedAt = term.FinalizedAt.Format("02-01-2006 15:04:05")
+	}
+	return response
+}
+
+func newGradingScaleResponse(scale models.GradingScale) *responses.GradingScaleResponse {
+	response := &responses.GradingScaleResponse{
+		ID:        scale.ID,
+		Name:      scale.Name,
+		IsDefault: scale.IsDefault,
+		Grades:    []responses.GradeBoundary{},
+	}
+	for _, grade := range scale.Grades {
+		response.Grades = append(response.Grades, responses.GradeBoundary{
+			Letter:        grade.Letter,
+			MinPercentage: grade.MinPercentage,
+			GradePoint:    grade.GradePoint,
+		})
+	}
+	return response
+}
+
+func newAssessmentResponse(assessment models.Assessment) *responses.AssessmentResponse {
+	return &responses.AssessmentResponse{
+		ID:          assessment.ID,
+		ClassroomID: assessment.ClassroomID,
+		TermID:      assessment.TermID,
+		Title:       assessment.Title,
+		Type:        assessment.Type,
+		Weight:      assessment.Weight,
+		MaxScore:    assessment.MaxScore,
+	}
+}
+
+func NewGradeService(
+	repositoryGrade repositories.GradeRepository,
+	repositoryStudent repositories.StudentRepository,
+	unitOfWork repositories.UnitOfWork,
+) GradeService {
+	return &gradeService{
+		repositoryGrade:   repositoryGrade,
+		repositoryStudent: repositoryStudent,
+		unitOfWork:        unitOfWork,
+	}
+}
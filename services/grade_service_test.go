@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"go_starter/errs"
+	"go_starter/models"
+	"go_starter/repositories"
+	"go_starter/requests"
+	"go_starter/security"
+	"testing"
+	"time"
+)
+
+func TestDefaultGradeBoundaries(t *testing.T) {
+	letters := map[string]bool{}
+	for i, boundary := range defaultGradeBoundaries {
+		if letters[boundary.Letter] {
+			t.Errorf("letter %s listed twice", boundary.Letter)
+		}
+		letters[boundary.Letter] = true
+		// letterGrade reads the boundaries highest first
+		if i > 0 && boundary.MinPercentage >= defaultGradeBoundaries[i-1].MinPercentage {
+			t.Errorf("%s at %v is not below %s", boundary.Letter, boundary.MinPercentage, defaultGradeBoundaries[i-1].Letter)
+		}
+		if i > 0 && boundary.GradePoint >= defaultGradeBoundaries[i-1].GradePoint {
+			t.Errorf("%s earns %v, not less than %s", boundary.Letter, boundary.GradePoint, defaultGradeBoundaries[i-1].Letter)
+		}
+	}
+	if last := defaultGradeBoundaries[len(defaultGradeBoundaries)-1]; last.MinPercentage != 0 {
+		t.Errorf("lowest boundary %s starts at %v, every percentage needs a letter", last.Letter, last.MinPercentage)
+	}
+}
+
+func TestLetterGrade(t *testing.T) {
+	tests := []struct {
+		percentage float64
+		letter     string
+		gradePoint float64
+	}{
+		{100, "A", 4.0},
+		{80, "A", 4.0},
+		{79.99, "B+", 3.5},
+		{75, "B+", 3.5},
+		{74.99, "B", 3.0},
+		{70, "B", 3.0},
+		{65, "C+", 2.5},
+		{60, "C", 2.0},
+		{59.99, "D+", 1.5},
+		{55, "D+", 1.5},
+		{50, "D", 1.0},
+		{49.99, "F", 0},
+		{0, "F", 0},
+	}
+	for _, test := range tests {
+		grade := letterGrade(defaultGradeBoundaries, test.percentage)
+		if grade.Letter != test.letter || grade.GradePoint != test.gradePoint {
+			t.Errorf("letterGrade(%v) = %s %v, want %s %v", test.percentage, grade.Letter, grade.GradePoint, test.letter, test.gradePoint)
+		}
+	}
+}
+
+func TestWeightedPercentage(t *testing.T) {
+	midterm := models.Assessment{ID: 1, Weight: 40, MaxScore: 50}
+	final := models.Assessment{ID: 2, Weight: 60, MaxScore: 100}
+	tests := []struct {
+		name        string
+		assessments []models.Assessment
+		scores      map[uint]float64
+		want        float64
+	}{
+		{"full marks", []models.Assessment{midterm, final}, map[uint]float64{1: 50, 2: 100}, 100},
+		{"weights adding up to 100", []models.Assessment{midterm, final}, map[uint]float64{1: 25, 2: 90}, 74},
+		{"weights normalized by their total", []models.Assessment{{ID: 1, Weight: 1, MaxScore: 10}, {ID: 2, Weight: 3, MaxScore: 10}}, map[uint]float64{1: 10, 2: 5}, 62.5},
+		{"missing score counts as zero", []models.Assessment{midterm, final}, map[uint]float64{2: 100}, 60},
+		{"rounded to two places", []models.Assessment{{ID: 1, Weight: 1, MaxScore: 3}}, map[uint]float64{1: 2}, 66.67},
+		{"no weight", []models.Assessment{{ID: 1, Weight: 0, MaxScore: 10}}, map[uint]float64{1: 10}, 0},
+		{"no assessments", nil, nil, 0},
+	}
+	for _, test := range tests {
+		if got := weightedPercentage(test.assessments, test.scores); got != test.want {
+			t.Errorf("%s: weightedPercentage = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestFinalizeTermServiceLocksScores(t *testing.T) {
+	db := openTestDatabase(t)
+	teacher := models.Teacher{Phone: "2055559999"}
+	classroom := models.Classroom{ClassName: "CE1", ClassYear: 2024, SubjectName: "Networks", Credits: 3}
+	student := models.Student{StudentID: "STU001", Phone: "2055550001"}
+	term := models.Term{Name: "2024/1", StartDate: time.Now(), EndDate: time.Now().AddDate(0, 4, 0)}
+	create(t, db, &teacher, &classroom, &student, &term)
+	assessment := models.Assessment{ClassroomID: classroom.ID, TermID: term.ID, Title: "Final", Weight: 100, MaxScore: 100}
+	create(t, db, &assessment, &models.StudentClassroom{StudentID: student.ID, ClassroomID: classroom.ID})
+	service := NewGradeService(repositories.NewGradeRepository(db), repositories.NewStudentRepository(db), repositories.NewUnitOfWork(db))
+	accessToken, err := security.NewAccessToken(teacher.Phone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	enterScore := func(score float64) error {
+		_, err := service.EnterScoresService(ctx, requests.BulkScoreRequest{
+			AssessmentID: assessment.ID,
+			Scores:       []requests.ScoreRequest{{StudentID: student.ID, Score: score}},
+			AccessToken:  accessToken,
+		})
+		return err
+	}
+
+	if err = enterScore(79.99); err != nil {
+		t.Fatalf("enter score: %v", err)
+	}
+	if _, err = service.FinalizeTermService(ctx, requests.TermIDRequest{TermID: term.ID}); err != nil {
+		t.Fatalf("finalize: %v", err)
+	}
+	var grade models.FinalGrade
+	if err = db.First(&grade, "student_id = ? AND term_id = ?", student.ID, term.ID).Error; err != nil || grade.Letter != "B+" || grade.Percentage != 79.99 {
+		t.Fatalf("final grade = %+v, %v; want B+ at 79.99", grade, err)
+	}
+
+	if err = enterScore(100); errs.From(err).Code != errs.CodeTermFinalized {
+		t.Fatalf("enter score after finalize = %v, want %s", err, errs.CodeTermFinalized)
+	}
+	if _, err = service.FinalizeTermService(ctx, requests.TermIDRequest{TermID: term.ID}); errs.From(err).Code != errs.CodeTermFinalized {
+		t.Fatalf("second finalize = %v, want %s", err, errs.CodeTermFinalized)
+	}
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"go_starter/config"
+	"go_starter/errs"
+	"go_starter/logs"
+	"go_starter/models"
+	"go_starter/repositories"
+	"go_starter/requests"
+	"go_starter/responses"
+	"go_starter/storage"
+	"go_starter/trails"
+	"strings"
+)
+
+type IDCardService interface {
+	GenerateIDCardService(ctx context.Context, request requests.IDCardRequest) (*responses.FileResponse, error)
+	GenerateClassroomIDCardsService(ctx context.Context, request requests.ClassroomIDCardRequest) (*responses.FileResponse, error)
+}
+
+type idCardService struct {
+	repositoryStudent repositories.StudentRepository
+	repositoryGrade   repositories.GradeRepository
+	storage           storage.Storage
+}
+
+func (i idCardService) GenerateIDCardService(ctx context.Context, request requests.IDCardRequest) (*responses.FileResponse, error) {
+	student, err := i.repositoryStudent.GetStudentByStudentIdRepository(ctx, strings.ToUpper(request.StudentID))
+	if err != nil {
+		return nil, err
+	}
+	if student.ID == 0 {
+		return nil, errs.New(errs.CodeStudentNotFound)
+	}
+	term, err := i.validityTerm(ctx, request.TermID)
+	if err != nil {
+		return nil, err
+	}
+
+	card, err := trails.RenderIDCardPNG(i.newIDCard(ctx, *student, term, request.Barcode))
+	if err != nil {
+		return nil, fmt.Errorf("failed to render ID card: %v", err)
+	}
+	if request.Format == "pdf" {
+		document, err := trails.RenderIDCardsPDF([][]byte{card})
+		if err != nil {
+			return nil, fmt.Errorf("failed to render ID card: %v", err)
+		}
+		return &responses.FileResponse{
+			FileName:    "id-card-" + student.StudentID + ".pdf",
+			ContentType: "application/pdf",
+			Content:     document,
+		}, nil
+	}
+	return &responses.FileResponse{
+		FileName:    "id-card-" + student.StudentID + ".png",
+		ContentType: "image/png",
+		Content:     card,
+	}, nil
+}
+
+func (i idCardService) GenerateClassroomIDCardsService(ctx context.Context, request requests.ClassroomIDCardRequest) (*responses.FileResponse, error) {
+	studentClassrooms, err := i.repositoryStudent.GetStudentClassroomByClassroomIDRepository(ctx, request.ClassroomID)
+	if err != nil {
+		return nil, err
+	}
+	if len(studentClassrooms) == 0 {
+		return nil, errs.New(errs.CodeNoStudentsEnrolled)
+	}
+	term, err := i.validityTerm(ctx, request.TermID)
+	if err != nil {
+		return nil, err
+	}
+
+	var cards [][]byte
+	for _, sc := range studentClassrooms {
+		card, err := trails.RenderIDCardPNG(i.newIDCard(ctx, sc.Student, term, request.Barcode))
+		if err != nil {
+			return nil, fmt.Errorf("failed to render ID card for %s: %v", sc.Student.StudentID, err)
+		}
+		cards = append(cards, card)
+	}
+	document, err := trails.RenderIDCardsPDF(cards)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render ID card sheet: %v", err)
+	}
+	response := &responses.FileResponse{
+		FileName:    fmt.Sprintf("id-cards-classroom-%d.pdf", request.ClassroomID),
+		ContentType: "application/pdf",
+		Content:     document,
+	}
+	return response, nil
+}
+
+func (i idCardService) validityTerm(ctx context.Context, termID uint) (*models.Term, error) {
+	if termID == 0 {
+		return nil, nil
+	}
+	term, err := i.repositoryGrade.GetTermByIdRepository(ctx, termID)
+	if err != nil {
+		return nil, err
+	}
+	return term, nil
+}
+
+func (i idCardService) newIDCard(ctx context.Context, student models.Student, term *models.Term, barcode string) trails.IDCard {
+	card := trails.IDCard{
+		Institution: config.GetEnv("app.institution_name", "CEIT"),
+		StudentNo:   student.StudentID,
+		Firstname:   student.Firstname,
+		Lastname:    student.Lastname,
+		Barcode:     barcode,
+	}
+	if term != nil {
+		card.ValidTerm = term.Name
+		card.ValidUntil = term.EndDate.Format("02-01-2006")
+	}
+	if student.Image != "" {
+		photo, err := i.storage.Get(ctx, storage.KeyFromPath(student.Image))
+		if err != nil {
+			logs.Error(err)
+		}
+		card.Photo = photo
+	}
+	return card
+}
+
+func NewIDCardService(
+	repositoryStudent repositories.StudentRepository,
+	repositoryGrade repositories.GradeRepository,
+	storage storage.Storage,
+) IDCardService {
+	return &idCardService{
+		repositoryStudent: repositoryStudent,
+		repositoryGrade:   repositoryGrade,
+		storage:           storage,
+	}
+}
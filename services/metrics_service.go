@@ -0,0 +1,52 @@
+package services
+
+import (
+	"fmt"
+	"go_starter/database"
+	"strings"
+)
+
+type MetricsService interface {
+	// GetMetricsService renders the metrics in the Prometheus text format
+	GetMetricsService() (string, error)
+}
+
+type metricsService struct {
+	replicas *database.ReplicaSet
+}
+
+func (m metricsService) GetMetricsService() (string, error) {
+	if m.replicas == nil {
+		return "", nil
+	}
+	var builder strings.Builder
+	status := m.replicas.Status()
+
+	builder.WriteString("# HELP db_replica_up Whether the read replica passed its last health check.\n")
+	builder.WriteString("# TYPE db_replica_up gauge\n")
+	for _, replica := range status {
+		up := 0
+		if replica.Healthy {
+			up = 1
+		}
+		fmt.Fprintf(&builder, "db_replica_up{replica=%q} %d\n", replica.Name, up)
+	}
+	builder.WriteString("# HELP db_replica_lag_seconds Replication lag of the read replica at its last health check.\n")
+	builder.WriteString("# TYPE db_replica_lag_seconds gauge\n")
+	for _, replica := range status {
+		fmt.Fprintf(&builder, "db_replica_lag_seconds{replica=%q} %g\n", replica.Name, replica.LagSeconds)
+	}
+	builder.WriteString("# HELP db_replica_reads_total Reads routed to the read replica.\n")
+	builder.WriteString("# TYPE db_replica_reads_total counter\n")
+	for _, replica := range status {
+		fmt.Fprintf(&builder, "db_replica_reads_total{replica=%q} %d\n", replica.Name, replica.Reads)
+	}
+	builder.WriteString("# HELP db_primary_reads_total Reads kept on the primary by read-your-writes or for lack of a healthy replica.\n")
+	builder.WriteString("# TYPE db_primary_reads_total counter\n")
+	fmt.Fprintf(&builder, "db_primary_reads_total %d\n", m.replicas.PrimaryReads())
+	return builder.String(), nil
+}
+
+func NewMetricsService(replicas *database.ReplicaSet) MetricsService {
+	return &metricsService{replicas: replicas}
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"go_starter/errs"
+	"go_starter/i18n"
+	"go_starter/models"
+	"go_starter/repositories"
+	"go_starter/requests"
+	"go_starter/responses"
+	"go_starter/storage"
+	"go_starter/trails"
+)
+
+type PhotoService interface {
+	UploadPhotoService(ctx context.Context, request requests.PhotoRequest) (*responses.MessageResponse, error)
+}
+
+// studentImageDirectory is the storage prefix of student photos, also served at /ceit/2024/images
+const studentImageDirectory = "ceit/2024/images"
+
+// avatarDirectory holds teacher and user photos, served at /ceit/2024/avatars
+const avatarDirectory = "ceit/2024/avatars"
+
+// photoDirectories maps each photo owner type to its storage prefix
+var photoDirectories = map[string]string{
+	models.FileOwnerStudentImage: studentImageDirectory,
+	models.FileOwnerTeacherPhoto: avatarDirectory + "/teachers",
+	models.FileOwnerUserPhoto:    avatarDirectory + "/users",
+}
+
+// photoOwnerNotFound maps each photo owner type to the error of a missing owner
+var photoOwnerNotFound = map[string]errs.Code{
+	models.FileOwnerStudentImage: errs.CodeStudentNotFound,
+	models.FileOwnerTeacherPhoto: errs.CodeTeacherNotFound,
+	models.FileOwnerUserPhoto:    errs.CodeUserNotFound,
+}
+
+type photoService struct {
+	repositoryFile repositories.FileRepository
+	storage        storage.Storage
+}
+
+func (p photoService) UploadPhotoService(ctx context.Context, request requests.PhotoRequest) (*responses.MessageResponse, error) {
+	err := uploadPhoto(ctx, p.repositoryFile, p.storage, request.OwnerType, request.OwnerID, request.Image)
+	if err != nil {
+		return nil, err
+	}
+	response := &responses.MessageResponse{Message: i18n.MessageUploaded}
+	return response, nil
+}
+
+// uploadPhoto validates and normalizes an image, stores it with its variants under the
+// owner type's prefix and points the owner at it, the previous photo is released
+func uploadPhoto(ctx context.Context, repositoryFile repositories.FileRepository, fileStorage storage.Storage, ownerType, ownerID string, image []byte) error {
+	previous, current, err := savePhoto(ctx, repositoryFile, fileStorage, ownerType, ownerID, image)
+	if err != nil {
+		return err
+	}
+	releaseReplacedPhoto(ctx, repositoryFile, fileStorage, previous, current)
+	return nil
+}
+
+// savePhoto is uploadPhoto without the release, it returns the previous and the new
+// photo so a caller running it in a unit of work can release after commit
+func savePhoto(ctx context.Context, repositoryFile repositories.FileRepository, fileStorage storage.Storage, ownerType, ownerID string, image []byte) (string, string, error) {
+	directory, ok := photoDirectories[ownerType]
+	if !ok {
+		return "", "", errs.Wrap(errs.CodeInternal, errors.Errorf("unknown photo owner type %q", ownerType))
+	}
+
+	// Validate the upload and re-encode it without metadata before touching the old photo
+	imageData, err := trails.NormalizeImage(image)
+	if err != nil {
+		return "", "", err
+	}
+	variants, err := trails.GenerateImageVariants(imageData)
+	if err != nil {
+		return "", "", err
+	}
+
+	previous, found, err := repositoryFile.GetPhotoRepository(ctx, ownerType, ownerID)
+	if err != nil {
+		return "", "", err
+	}
+	if !found {
+		return "", "", errs.New(photoOwnerNotFound[ownerType])
+	}
+
+	// Address the image by its content, re-uploading the same photo reuses the stored file
+	imagePath, hash := storage.ContentKey(directory, imageData, trails.NormalizedImageExtension)
+	object := models.FileObject{
+		StorageKey:  imagePath,
+		Hash:        hash,
+		Size:        int64(len(imageData)),
+		ContentType: trails.NormalizedImageContentType,
+	}
+
+	// Point the owner at the image and write it in one transaction
+	found, err = repositoryFile.SavePhotoRepository(ctx, ownerType, ownerID, object, func() error {
+		if _, err := fileStorage.Stat(ctx, imagePath); err == nil {
+			return nil
+		} else if !errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+		// Variants first, the original marks the object as complete
+		for name, data := range variants {
+			if err := fileStorage.Put(ctx, trails.ImageVariantKey(imagePath, name), data, trails.NormalizedImageContentType); err != nil {
+				return fmt.Errorf("failed to write image variant to storage: %v", err)
+			}
+		}
+		if err := fileStorage.Put(ctx, imagePath, imageData, trails.NormalizedImageContentType); err != nil {
+			return fmt.Errorf("failed to write image to storage: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", "", err
+	}
+	if !found {
+		return "", "", errs.New(photoOwnerNotFound[ownerType])
+	}
+	return previous, imagePath, nil
+}
+
+// releaseReplacedPhoto removes the previous photo once nothing references it, the
+// storage gc catches failures
+func releaseReplacedPhoto(ctx context.Context, repositoryFile repositories.FileRepository, fileStorage storage.Storage, previous, current string) {
+	if previous != "" && storage.KeyFromPath(previous) != current {
+		releasePhoto(ctx, repositoryFile, fileStorage, previous)
+	}
+}
+
+// releasePhoto deletes a stored photo and its variants once nothing references it
+func releasePhoto(ctx context.Context, repositoryFile repositories.FileRepository, fileStorage storage.Storage, image string) {
+	key := storage.KeyFromPath(image)
+	releaseStoredFile(ctx, repositoryFile, fileStorage, key, imageVariantKeys(key)...)
+}
+
+// photoURL returns a signed, expiring URL of a stored photo
+func photoURL(image string) string {
+	if image == "" {
+		return ""
+	}
+	return signedImageURL(storage.KeyFromPath(image))
+}
+
+// photoVariants returns the signed URL of every variant of a stored photo, variants
+// missing for photos uploaded before they existed are rendered on first request
+func photoVariants(image string) map[string]string {
+	if image == "" {
+		return nil
+	}
+	key := storage.KeyFromPath(image)
+	variants := map[string]string{}
+	for _, name := range trails.ImageVariantNames() {
+		variants[name] = signedImageURL(trails.ImageVariantKey(key, name))
+	}
+	return variants
+}
+
+func imageVariantKeys(key string) []string {
+	var keys []string
+	for _, name := range trails.ImageVariantNames() {
+		keys = append(keys, trails.ImageVariantKey(key, name))
+	}
+	return keys
+}
+
+func NewPhotoService(repositoryFile repositories.FileRepository, storage storage.Storage) PhotoService {
+	return &photoService{
+		repositoryFile: repositoryFile,
+		storage:        storage,
+	}
+}
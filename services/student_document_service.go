@@ -0,0 +1,326 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"go_starter/errs"
+	"go_starter/i18n"
+	"go_starter/logs"
+	"go_starter/models"
+	"go_starter/repositories"
+	"go_starter/requests"
+	"go_starter/responses"
+	"go_starter/scanner"
+	"go_starter/storage"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type StudentDocumentService interface {
+	GetStudentDocumentTypesService(ctx context.Context) ([]responses.StudentDocumentTypeResponse, error)
+	UploadStudentDocumentService(ctx context.Context, request requests.StudentDocumentRequest) (*responses.StudentDocumentResponse, error)
+	GetStudentDocumentsService(ctx context.Context, studentID uint) ([]responses.StudentDocumentResponse, error)
+	DownloadStudentDocumentService(ctx context.Context, request requests.StudentDocumentIDRequest) (*responses.FileResponse, error)
+	DeleteStudentDocumentService(ctx context.Context, request requests.StudentDocumentIDRequest) (*responses.MessageResponse, error)
+}
+
+// studentDocumentDirectory is the storage prefix of student documents, they are only
+// served through the download endpoint
+const studentDocumentDirectory = "ceit/2024/documents"
+
+const megabyte = 1024 * 1024
+
+type studentDocumentType struct {
+	Label        string
+	ContentTypes []string
+	MaxSize      int64
+}
+
+// studentDocumentTypes lists the accepted document types with their allowed content
+// types, checked against the file content rather than the uploaded name
+var studentDocumentTypes = map[string]studentDocumentType{
+	models.DocumentBirthCertificate: {
+		Label:        "Birth certificate",
+		ContentTypes: []string{"application/pdf", "image/jpeg", "image/png"},
+		MaxSize:      5 * megabyte,
+	},
+	models.DocumentIDScan: {
+		Label:        "ID scan",
+		ContentTypes: []string{"image/jpeg", "image/png", "application/pdf"},
+		MaxSize:      5 * megabyte,
+	},
+	models.DocumentTranscript: {
+		Label:        "Transcript",
+		ContentTypes: []string{"application/pdf"},
+		MaxSize:      10 * megabyte,
+	},
+	models.DocumentMedicalForm: {
+		Label:        "Medical form",
+		ContentTypes: []string{"application/pdf", "image/jpeg", "image/png"},
+		MaxSize:      10 * megabyte,
+	},
+}
+
+var documentExtensions = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+type studentDocumentService struct {
+	repositoryStudentDocument repositories.StudentDocumentRepository
+	repositoryStudent         repositories.StudentRepository
+	repositoryFile            repositories.FileRepository
+	repositoryUpload          repositories.UploadRepository
+	storage                   storage.Storage
+	scanner                   scanner.Scanner
+}
+
+func (s studentDocumentService) GetStudentDocumentTypesService(ctx context.Context) ([]responses.StudentDocumentTypeResponse, error) {
+	response := []responses.StudentDocumentTypeResponse{}
+	for documentType, rule := range studentDocumentTypes {
+		response = append(response, responses.StudentDocumentTypeResponse{
+			Type:         documentType,
+			Label:        rule.Label,
+			ContentTypes: rule.ContentTypes,
+			MaxSize:      rule.MaxSize,
+		})
+	}
+	sort.Slice(response, func(i, j int) bool {
+		return response[i].Type < response[j].Type
+	})
+	return response, nil
+}
+
+func (s studentDocumentService) UploadStudentDocumentService(ctx context.Context, request requests.StudentDocumentRequest) (*responses.StudentDocumentResponse, error) {
+	rule, ok := studentDocumentTypes[request.DocumentType]
+	if !ok {
+		return nil, errs.New(errs.CodeUnknownDocumentType)
+	}
+	var upload *models.UploadSession
+	if request.UploadID != "" {
+		session, content, err := takeCompletedUpload(ctx, s.repositoryUpload, s.storage, request.UploadID)
+		if err != nil {
+			return nil, err
+		}
+		upload = session
+		request.FileName = session.FileName
+		request.Content = content
+	}
+	if len(request.Content) == 0 {
+		return nil, errs.New(errs.CodeEmptyDocument)
+	}
+	if int64(len(request.Content)) > rule.MaxSize {
+		return nil, errs.New(errs.CodeDocumentTooLarge).
+			WithParam("document_type", request.DocumentType).
+			WithParam("max_mb", strconv.FormatInt(rule.MaxSize/megabyte, 10))
+	}
+	contentType := http.DetectContentType(request.Content)
+	if !containsString(rule.ContentTypes, contentType) {
+		return nil, errs.New(errs.CodeDocumentContentType).
+			WithParam("document_type", request.DocumentType).
+			WithParam("content_types", strings.Join(rule.ContentTypes, ", "))
+	}
+	issuedDate, err := parseOptionalDate(request.IssuedDate)
+	if err != nil {
+		return nil, errs.New(errs.CodeInvalidDate).WithField("issued_date", "validation.date")
+	}
+	expiryDate, err := parseOptionalDate(request.ExpiryDate)
+	if err != nil {
+		return nil, errs.New(errs.CodeInvalidDate).WithField("expiry_date", "validation.date")
+	}
+
+	student, err := s.repositoryStudent.GetStudentByIdRepository(ctx, int(request.StudentID))
+	if err != nil {
+		return nil, err
+	}
+	if student.ID == 0 {
+		return nil, errs.New(errs.CodeStudentNotFound)
+	}
+
+	// Scan before anything is stored
+	err = s.scanner.Scan(request.FileName, request.Content)
+	if errors.Is(err, scanner.ErrInfected) {
+		return nil, errs.New(errs.CodeVirusDetected)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan document: %v", err)
+	}
+
+	key, hash := storage.ContentKey(studentDocumentDirectory, request.Content, documentExtensions[contentType])
+	object := models.FileObject{
+		StorageKey:  key,
+		Hash:        hash,
+		Size:        int64(len(request.Content)),
+		ContentType: contentType,
+	}
+	title := request.Title
+	if title == "" {
+		title = rule.Label
+	}
+	document := &models.StudentDocument{
+		StudentID:      student.ID,
+		DocumentType:   request.DocumentType,
+		Title:          title,
+		Description:    request.Description,
+		DocumentNumber: request.DocumentNumber,
+		IssuedDate:     issuedDate,
+		ExpiryDate:     expiryDate,
+		FileName:       path.Base(strings.ReplaceAll(request.FileName, "\\", "/")),
+		ContentType:    contentType,
+		Size:           int64(len(request.Content)),
+		UploadedBy:     request.UploadedBy,
+	}
+	err = s.repositoryStudentDocument.CreateStudentDocumentRepository(ctx, document, object, func() error {
+		if _, err := s.storage.Stat(ctx, key); err == nil {
+			return nil
+		} else if !errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+		if err := s.storage.Put(ctx, key, request.Content, contentType); err != nil {
+			return fmt.Errorf("failed to write document to storage: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if upload != nil {
+		if err = discardUpload(ctx, s.repositoryUpload, s.storage, *upload); err != nil {
+			logs.Error(err)
+		}
+	}
+
+	response := newStudentDocumentResponse(*document)
+	return &response, nil
+}
+
+func (s studentDocumentService) GetStudentDocumentsService(ctx context.Context, studentID uint) ([]responses.StudentDocumentResponse, error) {
+	documents, err := s.repositoryStudentDocument.GetStudentDocumentsRepository(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	response := []responses.StudentDocumentResponse{}
+	for _, document := range documents {
+		response = append(response, newStudentDocumentResponse(document))
+	}
+	return response, nil
+}
+
+func (s studentDocumentService) DownloadStudentDocumentService(ctx context.Context, request requests.StudentDocumentIDRequest) (*responses.FileResponse, error) {
+	document, err := s.getStudentDocument(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	content, err := s.storage.Get(ctx, document.FileObject.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, errs.New(errs.CodeFileNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	fileName := document.FileName
+	if fileName == "" || fileName == "." || fileName == "/" {
+		fileName = document.DocumentType + documentExtensions[document.ContentType]
+	}
+	response := &responses.FileResponse{
+		FileName:     fileName,
+		ContentType:  document.ContentType,
+		CacheControl: "private, no-store",
+		Content:      content,
+	}
+	return response, nil
+}
+
+func (s studentDocumentService) DeleteStudentDocumentService(ctx context.Context, request requests.StudentDocumentIDRequest) (*responses.MessageResponse, error) {
+	document, err := s.getStudentDocument(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.repositoryStudentDocument.DeleteStudentDocumentRepository(ctx, document); err != nil {
+		return nil, err
+	}
+	// The same file may be attached elsewhere, it goes once the last reference is gone
+	releaseStoredFile(ctx, s.repositoryFile, s.storage, document.FileObject.StorageKey)
+
+	response := &responses.MessageResponse{Message: i18n.MessageDeleted}
+	return response, nil
+}
+
+func (s studentDocumentService) getStudentDocument(ctx context.Context, request requests.StudentDocumentIDRequest) (*models.StudentDocument, error) {
+	document, err := s.repositoryStudentDocument.GetStudentDocumentByIDRepository(ctx, request.StudentID, request.DocumentID)
+	if err != nil {
+		return nil, err
+	}
+	if document == nil {
+		return nil, errs.New(errs.CodeDocumentNotFound)
+	}
+	return document, nil
+}
+
+func newStudentDocumentResponse(document models.StudentDocument) responses.StudentDocumentResponse {
+	response := responses.StudentDocumentResponse{
+		ID:             document.ID,
+		StudentID:      document.StudentID,
+		DocumentType:   document.DocumentType,
+		Title:          document.Title,
+		Description:    document.Description,
+		DocumentNumber: document.DocumentNumber,
+		FileName:       document.FileName,
+		ContentType:    document.ContentType,
+		Size:           document.Size,
+		Hash:           document.FileObject.Hash,
+		UploadedBy:     document.UploadedBy,
+		CreatedAt:      document.CreatedAt.Format("02-01-2006 15:04:05"),
+	}
+	if document.IssuedDate != nil {
+		response.IssuedDate = document.IssuedDate.Format("02-01-2006")
+	}
+	if document.ExpiryDate != nil {
+		response.ExpiryDate = document.ExpiryDate.Format("02-01-2006")
+	}
+	return response
+}
+
+func parseOptionalDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("02-01-2006", value)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func NewStudentDocumentService(
+	repositoryStudentDocument repositories.StudentDocumentRepository,
+	repositoryStudent repositories.StudentRepository,
+	repositoryFile repositories.FileRepository,
+	repositoryUpload repositories.UploadRepository,
+	storage storage.Storage,
+	scanner scanner.Scanner,
+) StudentDocumentService {
+	return &studentDocumentService{
+		repositoryStudentDocument: repositoryStudentDocument,
+		repositoryStudent:         repositoryStudent,
+		repositoryFile:            repositoryFile,
+		repositoryUpload:          repositoryUpload,
+		storage:                   storage,
+		scanner:                   scanner,
+	}
+}
@@ -1,46 +1,55 @@
 package services
 
 import (
-	"fmt"
+	"context"
 	"github.com/pkg/errors"
 	"go_starter/errs"
+	"go_starter/i18n"
 	"go_starter/models"
 	"go_starter/repositories"
 	"go_starter/requests"
 	"go_starter/responses"
 	"go_starter/security"
-	"go_starter/trails"
-	"io/ioutil"
-	"os"
-	"path/filepath"
+	"go_starter/storage"
 	"strings"
 	"time"
 )
 
 type StudentService interface {
-	GetStudentClassroomByClassroomIDService(request requests.ClassroomIDRequest) (*responses.StudentClassroomResponse, error)
-
-	SignInService(request requests.SignInRequest) (*responses.SignInResponse, error)
-	SignUpService(request requests.SigUpRequest) (*responses.SignUpResponse, error)
-
-	GetStudentService() ([]responses.StudentResponse, error)
-	GetStudentByIdService(id uint) (*responses.StudentResponse, error)
-	GetStudentByStudentIdServiceV2(request requests.StudentIdRequest) (*responses.StudentResponse, error)
-	CreateStudentService(request requests.StudentRequest) (*responses.MessageResponse, error)
-	UpdateStudentService(request requests.StudentRequest) (*responses.MessageResponse, error)
-	DeleteStudentByIDService(request requests.StudentIdRequest) (*responses.MessageResponse, error)
+	GetStudentClassroomByClassroomIDService(ctx context.Context, request requests.ClassroomIDRequest) (*responses.StudentClassroomResponse, error)
+
+	GetClassroomsService(ctx context.Context) ([]responses.ClassroomResponse, error)
+	GetClassroomByIDService(ctx context.Context, id uint) (*responses.ClassroomResponse, error)
+	// GetStudentsByClassroomIDsService and GetClassroomsByStudentIDsService answer the
+	// relation of many parents at once keyed by the parent id, a parent without any is missing
+	GetStudentsByClassroomIDsService(ctx context.Context, classroomIDs []uint) (map[uint][]responses.StudentResponse, error)
+	GetClassroomsByStudentIDsService(ctx context.Context, studentIDs []uint) (map[uint][]responses.ClassroomResponse, error)
+
+	SignInService(ctx context.Context, request requests.SignInRequest) (*responses.SignInResponse, error)
+	SignUpService(ctx context.Context, request requests.SigUpRequest) (*responses.SignUpResponse, error)
+
+	GetStudentService(ctx context.Context) ([]responses.StudentResponse, error)
+	GetStudentByIdService(ctx context.Context, id uint) (*responses.StudentResponse, error)
+	GetStudentByStudentIdServiceV2(ctx context.Context, request requests.StudentIdRequest) (*responses.StudentResponse, error)
+	GetTeacherByIDService(ctx context.Context, id uint) (*responses.TeacherResponse, error)
+	CreateStudentService(ctx context.Context, request requests.StudentRequest) (*responses.MessageResponse, error)
+	UpdateStudentService(ctx context.Context, request requests.StudentRequest) (*responses.MessageResponse, error)
+	DeleteStudentByIDService(ctx context.Context, request requests.StudentIdRequest) (*responses.MessageResponse, error)
 
 	//image
 
-	UploadStudentImageService(request requests.StudentImageRequest) (*responses.MessageResponse, error)
+	UploadStudentImageService(ctx context.Context, request requests.StudentImageRequest) (*responses.MessageResponse, error)
 }
 
 type studentService struct {
 	repositoryStudent repositories.StudentRepository
+	repositoryFile    repositories.FileRepository
+	unitOfWork        repositories.UnitOfWork
+	storage           storage.Storage
 }
 
-func (s studentService) GetStudentClassroomByClassroomIDService(request requests.ClassroomIDRequest) (*responses.StudentClassroomResponse, error) {
-	studentClassrooms, err := s.repositoryStudent.GetStudentClassroomByClassroomIDRepository(uint(request.ClassroomID))
+func (s studentService) GetStudentClassroomByClassroomIDService(ctx context.Context, request requests.ClassroomIDRequest) (*responses.StudentClassroomResponse, error) {
+	studentClassrooms, err := s.repositoryStudent.GetStudentClassroomByClassroomIDRepository(ctx, uint(request.ClassroomID))
 	if err != nil {
 		return nil, err
 	}
@@ -73,76 +82,156 @@ func (s studentService) GetStudentClassroomByClassroomIDService(request requests
 	return response, nil
 }
 
-func (s studentService) SignInService(request requests.SignInRequest) (*responses.SignInResponse, error) {
+func (s studentService) GetClassroomsService(ctx context.Context) ([]responses.ClassroomResponse, error) {
+	classrooms, err := s.repositoryStudent.GetClassroomsRepository(ctx)
+	if err != nil {
+		return nil, err
+	}
+	response := make([]responses.ClassroomResponse, 0, len(classrooms))
+	for _, classroom := range classrooms {
+		response = append(response, classroomResponse(classroom))
+	}
+	return response, nil
+}
+
+func (s studentService) GetClassroomByIDService(ctx context.Context, id uint) (*responses.ClassroomResponse, error) {
+	classroom, err := s.repositoryStudent.GetClassroomByIDRepository(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	response := classroomResponse(*classroom)
+	return &response, nil
+}
+
+func (s studentService) GetStudentsByClassroomIDsService(ctx context.Context, classroomIDs []uint) (map[uint][]responses.StudentResponse, error) {
+	studentClassrooms, err := s.repositoryStudent.GetStudentClassroomsByClassroomIDsRepository(ctx, classroomIDs)
+	if err != nil {
+		return nil, err
+	}
+	response := make(map[uint][]responses.StudentResponse, len(classroomIDs))
+	for _, studentClassroom := range studentClassrooms {
+		response[studentClassroom.ClassroomID] = append(response[studentClassroom.ClassroomID], studentResponse(studentClassroom.Student))
+	}
+	return response, nil
+}
+
+func (s studentService) GetClassroomsByStudentIDsService(ctx context.Context, studentIDs []uint) (map[uint][]responses.ClassroomResponse, error) {
+	studentClassrooms, err := s.repositoryStudent.GetStudentClassroomsByStudentIDsRepository(ctx, studentIDs)
+	if err != nil {
+		return nil, err
+	}
+	response := make(map[uint][]responses.ClassroomResponse, len(studentIDs))
+	for _, studentClassroom := range studentClassrooms {
+		response[studentClassroom.StudentID] = append(response[studentClassroom.StudentID], classroomResponse(studentClassroom.Classroom))
+	}
+	return response, nil
+}
+
+func studentResponse(student models.Student) responses.StudentResponse {
+	return responses.StudentResponse{
+		ID:            student.ID,
+		StudentID:     student.StudentID,
+		Firstname:     student.Firstname,
+		Lastname:      student.Lastname,
+		Phone:         student.Phone,
+		Email:         student.Email,
+		Birthday:      student.Birthday.Format("02-01-2006"),
+		Gender:        student.Gender,
+		Status:        student.Status,
+		Image:         student.Image,
+		ImageURL:      photoURL(student.Image),
+		ImageVariants: photoVariants(student.Image),
+		CreatedAt:     student.CreatedAt.Format("02-01-2006 15:01:05"),
+		UpdatedAt:     student.UpdatedAt.Format("02-01-2006 15:01:05"),
+	}
+}
+
+func classroomResponse(classroom models.Classroom) responses.ClassroomResponse {
+	return responses.ClassroomResponse{
+		ID:          classroom.ID,
+		ClassName:   classroom.ClassName,
+		ClassYear:   classroom.ClassYear,
+		SubjectName: classroom.SubjectName,
+		Credits:     classroom.Credits,
+	}
+}
+
+func (s studentService) SignInService(ctx context.Context, request requests.SignInRequest) (*responses.SignInResponse, error) {
 	// Validate phone number
 	if request.Phone == "" {
-		return nil, errs.ErrorBadRequest("PHONE_CANT_BE_EMPTY")
-	}
-	if len(request.Phone) > 10 || len(request.Phone) < 9 {
-		return nil, errs.ErrorBadRequest("PHONE_INVALID")
+		return nil, errs.New(errs.CodeValidationFailed).WithField("phone", "validation.required")
 	}
 	trimSpacePassword := strings.TrimSpace(request.Password)
 	if trimSpacePassword == "" {
-		return nil, errs.ErrorBadRequest("PASSWORD_CANT_BE_EMPTY")
+		return nil, errs.New(errs.CodeValidationFailed).WithField("password", "validation.required")
 	}
 	switch request.UserType {
 	case "teacher":
-		getTeacherData, err := s.repositoryStudent.GetTeacherByPhoneRepository(request.Phone)
+		getTeacherData, err := s.repositoryStudent.GetTeacherByPhoneRepository(ctx, request.Phone)
 		if err != nil {
-			return nil, errs.ErrorBadRequest("not found teacher id")
+			return nil, err
+		}
+		if getTeacherData == nil {
+			return nil, errs.New(errs.CodeInvalidCredentials)
 		}
 		err = security.VerifyPassword(getTeacherData.Password, request.Password)
 		if err != nil {
-			return nil, fmt.Errorf("password doesn't match")
+			return nil, errs.New(errs.CodeInvalidCredentials)
+		}
+		// A fresh token on every sign-in, the one of sign-up expires after a day
+		accessToken, err := security.NewAccessToken(getTeacherData.Phone)
+		if err != nil {
+			return nil, err
 		}
 		response := responses.SignInResponse{
 			Phone:       getTeacherData.Phone,
 			UserType:    "student",
-			AccessToken: "",
+			AccessToken: accessToken,
 		}
-		return &response, err
+		return &response, nil
 
 	case "student":
-		getStudentData, err := s.repositoryStudent.GetStudentByPhoneRepository(request.Phone)
+		getStudentData, err := s.repositoryStudent.GetStudentByPhoneRepository(ctx, request.Phone)
 		if err != nil {
 			return nil, err
 		}
+		if getStudentData == nil {
+			return nil, errs.New(errs.CodeInvalidCredentials)
+		}
 		err = security.VerifyPassword(getStudentData.Password, request.Password)
 		if err != nil {
-			return nil, fmt.Errorf("password doesn't match")
+			return nil, errs.New(errs.CodeInvalidCredentials)
+		}
+		accessToken, err := security.NewAccessToken(getStudentData.Phone)
+		if err != nil {
+			return nil, err
 		}
 		response := responses.SignInResponse{
 			Phone:       getStudentData.Phone,
 			UserType:    "student",
-			AccessToken: "",
+			AccessToken: accessToken,
 		}
-		return &response, err
+		return &response, nil
 	default:
-		return nil, fmt.Errorf("invalid user type")
+		return nil, errs.New(errs.CodeInvalidUserType)
 	}
 }
 
-func (s studentService) SignUpService(request requests.SigUpRequest) (*responses.SignUpResponse, error) {
+func (s studentService) SignUpService(ctx context.Context, request requests.SigUpRequest) (*responses.SignUpResponse, error) {
 	// Validate phone number
 	if request.Phone == "" {
-		return nil, errs.ErrorBadRequest("PHONE_CANT_BE_EMPTY")
+		return nil, errs.New(errs.CodeValidationFailed).WithField("phone", "validation.required")
 	}
 	if len(request.Phone) > 10 || len(request.Phone) < 9 {
-		return nil, errs.ErrorBadRequest("PHONE_INVALID")
+		return nil, errs.New(errs.CodeValidationFailed).WithField("phone", "validation.lao_phone")
 	}
 
 	// Handle user type specific logic
 	switch request.UserType {
 	case "teacher":
-		// Check if student's phone number is already in use
-		if checkTeacherPhone, err := s.repositoryStudent.CheckTeacherPhoneAlreadyHas(request.Phone); err != nil {
-			return nil, err
-		} else if checkTeacherPhone {
-			return nil, errors.New("phone number already in use")
-		}
 		trimSpacePassword := strings.TrimSpace(request.Password)
 		if trimSpacePassword == "" {
-			return nil, errs.ErrorBadRequest("PASSWORD_CANT_BE_EMPTY")
+			return nil, errs.New(errs.CodeValidationFailed).WithField("password", "validation.required")
 		}
 		encryptPassword, err := security.EncryptPassword(request.Password)
 		if err != nil {
@@ -158,7 +247,19 @@ func (s studentService) SignUpService(request requests.SigUpRequest) (*responses
 			Password: encryptPassword,
 			Token:    newAccessToken,
 		}
-		signUpTeacher, err := s.repositoryStudent.SignUpForTeacherRepository(student)
+		// Check the phone and insert in one transaction
+		var signUpTeacher *models.Teacher
+		err = s.unitOfWork.Do(ctx, func(tx repositories.TransactionRepositories) error {
+			// Check if teacher's phone number is already in use
+			if checkTeacherPhone, err := tx.Student.CheckTeacherPhoneAlreadyHas(ctx, request.Phone); err != nil {
+				return err
+			} else if checkTeacherPhone {
+				return errs.New(errs.CodePhoneInUse).WithField("phone", string(errs.CodePhoneInUse))
+			}
+			var err error
+			signUpTeacher, err = tx.Student.SignUpForTeacherRepository(ctx, student)
+			return err
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -170,15 +271,9 @@ func (s studentService) SignUpService(request requests.SigUpRequest) (*responses
 		return &responseStudent, nil
 
 	case "student":
-		// Check if student's phone number is already in use
-		if checkStudentPhone, err := s.repositoryStudent.CheckStudentPhoneAlreadyHas(request.Phone); err != nil {
-			return nil, err
-		} else if checkStudentPhone {
-			return nil, errors.New("phone number already in use")
-		}
 		trimSpacePassword := strings.TrimSpace(request.Password)
 		if trimSpacePassword == "" {
-			return nil, errs.ErrorBadRequest("PASSWORD_CANT_BE_EMPTY")
+			return nil, errs.New(errs.CodeValidationFailed).WithField("password", "validation.required")
 		}
 		encryptPassword, err := security.EncryptPassword(request.Password)
 		if err != nil {
@@ -194,7 +289,19 @@ func (s studentService) SignUpService(request requests.SigUpRequest) (*responses
 			Password: encryptPassword,
 			Token:    newAccessToken,
 		}
-		signUpStudent, err := s.repositoryStudent.SignUpForStudentRepository(student)
+		// Check the phone and insert in one transaction
+		var signUpStudent *models.Student
+		err = s.unitOfWork.Do(ctx, func(tx repositories.TransactionRepositories) error {
+			// Check if student's phone number is already in use
+			if checkStudentPhone, err := tx.Student.CheckStudentPhoneAlreadyHas(ctx, request.Phone); err != nil {
+				return err
+			} else if checkStudentPhone {
+				return errs.New(errs.CodePhoneInUse).WithField("phone", string(errs.CodePhoneInUse))
+			}
+			var err error
+			signUpStudent, err = tx.Student.SignUpForStudentRepository(ctx, student)
+			return err
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -206,14 +313,14 @@ func (s studentService) SignUpService(request requests.SigUpRequest) (*responses
 		return &responseStudent, nil
 
 	default:
-		return nil, fmt.Errorf("invalid user type")
+		return nil, errs.New(errs.CodeInvalidUserType)
 	}
 
 }
 
-func (s studentService) GetStudentService() ([]responses.StudentResponse, error) {
+func (s studentService) GetStudentService(ctx context.Context) ([]responses.StudentResponse, error) {
 	// fetch getStudent data from repository(database)
-	getStudent, err := s.repositoryStudent.GetStudentsRepository()
+	getStudent, err := s.repositoryStudent.GetStudentsRepository(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -227,18 +334,20 @@ func (s studentService) GetStudentService() ([]responses.StudentResponse, error)
 	var response []responses.StudentResponse
 	for _, studentData := range getStudent {
 		studentResponse := responses.StudentResponse{
-			ID:        studentData.ID,
-			StudentID: studentData.StudentID,
-			Firstname: studentData.Firstname,
-			Lastname:  studentData.Lastname,
-			Phone:     studentData.Phone,
-			Email:     studentData.Email,
-			Birthday:  studentData.Birthday.Format("02-01-2006"),
-			Gender:    studentData.Gender,
-			Status:    studentData.Status,
-			Image:     studentData.Image,
-			CreatedAt: studentData.CreatedAt.Format("02-01-2006 15:01:05"),
-			UpdatedAt: studentData.UpdatedAt.Format("02-01-2006 15:01:05"),
+			ID:            studentData.ID,
+			StudentID:     studentData.StudentID,
+			Firstname:     studentData.Firstname,
+			Lastname:      studentData.Lastname,
+			Phone:         studentData.Phone,
+			Email:         studentData.Email,
+			Birthday:      studentData.Birthday.Format("02-01-2006"),
+			Gender:        studentData.Gender,
+			Status:        studentData.Status,
+			Image:         studentData.Image,
+			ImageURL:      photoURL(studentData.Image),
+			ImageVariants: photoVariants(studentData.Image),
+			CreatedAt:     studentData.CreatedAt.Format("02-01-2006 15:01:05"),
+			UpdatedAt:     studentData.UpdatedAt.Format("02-01-2006 15:01:05"),
 		}
 
 		response = append(response, studentResponse)
@@ -246,65 +355,93 @@ func (s studentService) GetStudentService() ([]responses.StudentResponse, error)
 	return response, err
 }
 
-func (s studentService) GetStudentByIdService(id uint) (*responses.StudentResponse, error) {
-	studentData, err := s.repositoryStudent.GetStudentByIdRepository(int(id))
+func (s studentService) GetStudentByIdService(ctx context.Context, id uint) (*responses.StudentResponse, error) {
+	studentData, err := s.repositoryStudent.GetStudentByIdRepository(ctx, int(id))
 	if err != nil {
 		return nil, err
 	}
 	response := &responses.StudentResponse{
-		ID:        studentData.ID,
-		StudentID: studentData.StudentID,
-		Firstname: studentData.Firstname,
-		Lastname:  studentData.Lastname,
-		Phone:     studentData.Phone,
-		Email:     studentData.Email,
-		Birthday:  studentData.Birthday.Format("02-01-2006"),
-		Gender:    studentData.Gender,
-		Status:    studentData.Status,
-		Image:     studentData.Image,
-		CreatedAt: studentData.CreatedAt.Format("02-01-2006 15:01:05"),
-		UpdatedAt: studentData.UpdatedAt.Format("02-01-2006 15:01:05"),
+		ID:            studentData.ID,
+		StudentID:     studentData.StudentID,
+		Firstname:     studentData.Firstname,
+		Lastname:      studentData.Lastname,
+		Phone:         studentData.Phone,
+		Email:         studentData.Email,
+		Birthday:      studentData.Birthday.Format("02-01-2006"),
+		Gender:        studentData.Gender,
+		Status:        studentData.Status,
+		Image:         studentData.Image,
+		ImageURL:      photoURL(studentData.Image),
+		ImageVariants: photoVariants(studentData.Image),
+		CreatedAt:     studentData.CreatedAt.Format("02-01-2006 15:01:05"),
+		UpdatedAt:     studentData.UpdatedAt.Format("02-01-2006 15:01:05"),
 	}
 	return response, err
 }
 
-func (s studentService) GetStudentByStudentIdServiceV2(request requests.StudentIdRequest) (*responses.StudentResponse, error) {
-	studentData, err := s.repositoryStudent.GetStudentByStudentIdRepository(request.StudentID)
+func (s studentService) GetStudentByStudentIdServiceV2(ctx context.Context, request requests.StudentIdRequest) (*responses.StudentResponse, error) {
+	studentData, err := s.repositoryStudent.GetStudentByStudentIdRepository(ctx, strings.ToUpper(request.StudentID))
 	if err != nil {
 		return nil, err
 	}
+	if studentData.ID == 0 {
+		return nil, errs.New(errs.CodeStudentNotFound)
+	}
 	response := &responses.StudentResponse{
-		ID:        studentData.ID,
-		StudentID: studentData.StudentID,
-		Firstname: studentData.Firstname,
-		Lastname:  studentData.Lastname,
-		Phone:     studentData.Phone,
-		Email:     studentData.Email,
-		Birthday:  studentData.Birthday.Format("02-01-2006"),
-		Gender:    studentData.Gender,
-		Status:    studentData.Status,
-		Image:     studentData.Image,
-		CreatedAt: studentData.CreatedAt.Format("02-01-2006 15:01:05"),
-		UpdatedAt: studentData.UpdatedAt.Format("02-01-2006 15:01:05"),
+		ID:            studentData.ID,
+		StudentID:     studentData.StudentID,
+		Firstname:     studentData.Firstname,
+		Lastname:      studentData.Lastname,
+		Phone:         studentData.Phone,
+		Email:         studentData.Email,
+		Birthday:      studentData.Birthday.Format("02-01-2006"),
+		Gender:        studentData.Gender,
+		Status:        studentData.Status,
+		Image:         studentData.Image,
+		ImageURL:      photoURL(studentData.Image),
+		ImageVariants: photoVariants(studentData.Image),
+		CreatedAt:     studentData.CreatedAt.Format("02-01-2006 15:01:05"),
+		UpdatedAt:     studentData.UpdatedAt.Format("02-01-2006 15:01:05"),
 	}
 	return response, err
 }
 
-func (s studentService) CreateStudentService(request requests.StudentRequest) (*responses.MessageResponse, error) {
+func (s studentService) GetTeacherByIDService(ctx context.Context, id uint) (*responses.TeacherResponse, error) {
+	teacher, err := s.repositoryStudent.GetTeacherByIDRepository(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if teacher == nil {
+		return nil, errs.New(errs.CodeTeacherNotFound)
+	}
+	response := &responses.TeacherResponse{
+		ID:            teacher.ID,
+		Phone:         teacher.Phone,
+		Firstname:     teacher.Firstname,
+		Lastname:      teacher.Lastname,
+		ImageURL:      photoURL(teacher.Image),
+		ImageVariants: photoVariants(teacher.Image),
+		CreatedAt:     teacher.CreatedAt.Format("02-01-2006 15:04:05"),
+		UpdatedAt:     teacher.UpdatedAt.Format("02-01-2006 15:04:05"),
+	}
+	return response, nil
+}
+
+func (s studentService) CreateStudentService(ctx context.Context, request requests.StudentRequest) (*responses.MessageResponse, error) {
 	// Convert the student ID to uppercase
 	studentID := strings.ToUpper(request.StudentID)
 
 	// Check if the student ID or phone number is already in use
-	if checkStudentID, err := s.repositoryStudent.CheckStudentIDAlreadyHas(studentID); err != nil {
+	if checkStudentID, err := s.repositoryStudent.CheckStudentIDAlreadyHas(ctx, studentID); err != nil {
 		return nil, err
 	} else if checkStudentID {
-		return nil, errors.New("student ID already in use")
+		return nil, errs.New(errs.CodeStudentIDInUse).WithField("student_id", string(errs.CodeStudentIDInUse))
 	}
 
-	if checkPhone, err := s.repositoryStudent.CheckStudentPhoneAlreadyHas(request.Phone); err != nil {
+	if checkPhone, err := s.repositoryStudent.CheckStudentPhoneAlreadyHas(ctx, request.Phone); err != nil {
 		return nil, err
 	} else if checkPhone {
-		return nil, errors.New("phone number already in use")
+		return nil, errs.New(errs.CodePhoneInUse).WithField("phone", string(errs.CodePhoneInUse))
 	}
 
 	// Initialize the birthday variable
@@ -314,7 +451,7 @@ func (s studentService) CreateStudentService(request requests.StudentRequest) (*
 		// Parse the birthday string
 		parsedBirth, err := time.Parse("02-01-2006", request.Birthday)
 		if err != nil {
-			return nil, fmt.Errorf("invalid birthday format: %v", err)
+			return nil, errs.New(errs.CodeInvalidDate).WithField("birthday", "validation.date")
 		}
 		birth = parsedBirth
 	}
@@ -332,16 +469,16 @@ func (s studentService) CreateStudentService(request requests.StudentRequest) (*
 	}
 
 	// Call the repository method to create the student record
-	if err := s.repositoryStudent.CreateStudentRepository(&model); err != nil {
+	if err := s.repositoryStudent.CreateStudentRepository(ctx, &model); err != nil {
 		return nil, err
 	}
 
 	// If successful, return a success message response
-	response := &responses.MessageResponse{Message: "success"}
+	response := &responses.MessageResponse{Message: i18n.MessageSuccess}
 	return response, nil
 }
 
-func (s studentService) UpdateStudentService(request requests.StudentRequest) (*responses.MessageResponse, error) {
+func (s studentService) UpdateStudentService(ctx context.Context, request requests.StudentRequest) (*responses.MessageResponse, error) {
 	// Convert the student ID to uppercase
 	studentID := strings.ToUpper(request.StudentID)
 
@@ -352,7 +489,7 @@ func (s studentService) UpdateStudentService(request requests.StudentRequest) (*
 		// Parse the birthday string
 		parsedBirth, err := time.Parse("02-01-2006", request.Birthday)
 		if err != nil {
-			return nil, fmt.Errorf("invalid birthday format: %v", err)
+			return nil, errs.New(errs.CodeInvalidDate).WithField("birthday", "validation.date")
 		}
 		birth = parsedBirth
 	}
@@ -368,92 +505,73 @@ func (s studentService) UpdateStudentService(request requests.StudentRequest) (*
 		Gender:    request.Gender}
 
 	// Call the repository method to update the student record
-	if err := s.repositoryStudent.UpdateStudentRepository(&model); err != nil {
+	if err := s.repositoryStudent.UpdateStudentRepository(ctx, &model); err != nil {
 		return nil, err
 	}
 
 	// If successful, return a success message response
-	response := &responses.MessageResponse{Message: "success"}
+	response := &responses.MessageResponse{Message: i18n.MessageSuccess}
 	return response, nil
 }
 
-func (s studentService) DeleteStudentByIDService(request requests.StudentIdRequest) (*responses.MessageResponse, error) {
+func (s studentService) DeleteStudentByIDService(ctx context.Context, request requests.StudentIdRequest) (*responses.MessageResponse, error) {
 	// Check if the student ID is empty
 	if request.StudentID == "" {
-		return nil, errors.New("student ID cannot be empty")
+		return nil, errs.New(errs.CodeValidationFailed).WithField("student_id", "validation.required")
 	}
 
-	// Call the repository method to delete the student record by ID
-	err := s.repositoryStudent.DeleteStudentByStudentIDRepository(request.StudentID)
+	// Delete the student and its photo reference together
+	var image string
+	err := s.unitOfWork.Do(ctx, func(tx repositories.TransactionRepositories) error {
+		var err error
+		image, err = tx.Student.GetStudentImageRepository(ctx, request.StudentID)
+		if err != nil {
+			return err
+		}
+
+		// Call the repository method to delete the student record by ID
+		if err = tx.Student.DeleteStudentByStudentIDRepository(ctx, request.StudentID); err != nil {
+			return err
+		}
+
+		// Drop the photo reference, the file goes once no other student shares it
+		return tx.File.DeleteFileReferenceRepository(ctx, models.FileOwnerStudentImage, request.StudentID)
+	})
 	if err != nil {
 		return nil, err
 	}
+	if image != "" {
+		releasePhoto(ctx, s.repositoryFile, s.storage, image)
+	}
 
 	// If successful, return a success message response
-	response := &responses.MessageResponse{Message: "success"}
+	response := &responses.MessageResponse{Message: i18n.MessageSuccess}
 	return response, nil
 }
 
-func (s studentService) UploadStudentImageService(request requests.StudentImageRequest) (*responses.MessageResponse, error) {
-	// Check student id
-	if checkStudentID, err := s.repositoryStudent.CheckStudentIDAlreadyHas(request.StudentID); err != nil {
-		return nil, err
-	} else if !checkStudentID {
-		return nil, errors.New("student ID not found")
-	}
-
-	// Check if the image exists for the student
-	checkData, err := s.repositoryStudent.GetStudentImageRepository(request.StudentID)
-	if err != nil {
-		return nil, err
-	}
-
-	// If an image already exists, delete it
-	if checkData != "" {
-		err = trails.DeleteImageFile(checkData)
-		if err != nil {
-			return nil, err
-		}
-		err = s.repositoryStudent.DeleteStudentImageRepository(request.StudentID)
-		if err != nil {
-			return nil, err
+func (s studentService) UploadStudentImageService(ctx context.Context, request requests.StudentImageRequest) (*responses.MessageResponse, error) {
+	var previous, current string
+	err := s.unitOfWork.Do(ctx, func(tx repositories.TransactionRepositories) error {
+		// Check student id
+		if checkStudentID, err := tx.Student.CheckStudentIDAlreadyHas(ctx, request.StudentID); err != nil {
+			return err
+		} else if !checkStudentID {
+			return errs.New(errs.CodeStudentNotFound)
 		}
-	}
-
-	// Define the directory structure where the images will be stored
-	//directory := filepath.Join("go-starter", "assets", "ceit", "2024", "images")
-	//
-	directory := "assets/ceit/2024/images"
-
-	// Create the directory if it doesn't exist
-	err = os.MkdirAll(directory, os.ModePerm)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create directory: %v", err)
-	}
-
-	// Generate a random number
-	randomNumber := trails.GenerateRandomNumber()
-
-	// Generate the file path for storing the image
-	imagePath := filepath.Join(directory, request.StudentID+""+randomNumber+".png")
-
-	// Write the image data to a file
-	err = ioutil.WriteFile(imagePath, request.Image, 0644)
-	if err != nil {
-		return nil, fmt.Errorf("failed to write image to file: %v", err)
-	}
 
-	// Update the student image path in the database
-	err = s.repositoryStudent.UpdateStudentImageRepository(&models.Student{
-		StudentID: request.StudentID,
-		Image:     imagePath,
+		// Hand the image to the shared photo pipeline
+		var err error
+		previous, current, err = savePhoto(ctx, tx.File, s.storage, models.FileOwnerStudentImage, request.StudentID, request.Image)
+		return err
 	})
 	if err != nil {
 		return nil, err
 	}
+	// The old photo is only released once the new one is committed
+	releaseReplacedPhoto(ctx, s.repositoryFile, s.storage, previous, current)
 
 	// Return success message
-	response := &responses.MessageResponse{Message: "uploaded success"}
+	response := &responses.MessageResponse{Message: i18n.MessageUploaded}
 	return response, nil
 }
 
@@ -753,8 +871,16 @@ func (s studentService) UploadStudentImageService(request requests.StudentImageR
 //	return response, nil
 //}
 
-func NewStudentServices(repositoryStudent repositories.StudentRepository) StudentService {
+func NewStudentServices(
+	repositoryStudent repositories.StudentRepository,
+	repositoryFile repositories.FileRepository,
+	unitOfWork repositories.UnitOfWork,
+	storage storage.Storage,
+) StudentService {
 	return &studentService{
 		repositoryStudent: repositoryStudent,
+		repositoryFile:    repositoryFile,
+		unitOfWork:        unitOfWork,
+		storage:           storage,
 	}
 }
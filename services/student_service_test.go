@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"go_starter/models"
+	"go_starter/repositories"
+	"go_starter/requests"
+	"go_starter/security"
+	"testing"
+)
+
+func TestSignInServiceIssuesAccessToken(t *testing.T) {
+	db := openTestDatabase(t)
+	password, err := security.EncryptPassword("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	create(t, db,
+		&models.Student{StudentID: "STU001", Phone: "2055550001", Password: password},
+		&models.Teacher{Phone: "2055559999", Password: password},
+	)
+	service := NewStudentServices(repositories.NewStudentRepository(db), repositories.NewFileRepository(db), repositories.NewUnitOfWork(db), nil)
+
+	for _, request := range []requests.SignInRequest{
+		{Phone: "2055550001", Password: "secret", UserType: "student"},
+		{Phone: "2055559999", Password: "secret", UserType: "teacher"},
+	} {
+		response, err := service.SignInService(context.Background(), request)
+		if err != nil {
+			t.Fatalf("sign in %s: %v", request.UserType, err)
+		}
+		claims, err := security.ParseAccessToken(response.AccessToken)
+		if err != nil || claims.Id != request.Phone {
+			t.Fatalf("access token of %s = %q: %+v, %v", request.UserType, response.AccessToken, claims, err)
+		}
+	}
+
+	if _, err = service.SignInService(context.Background(), requests.SignInRequest{Phone: "2055550001", Password: "wrong", UserType: "student"}); err == nil {
+		t.Fatal("sign in with a wrong password succeeded")
+	}
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go_starter/config"
+	"go_starter/errs"
+	"go_starter/i18n"
+	"go_starter/models"
+	"go_starter/repositories"
+	"go_starter/requests"
+	"go_starter/responses"
+	"go_starter/security"
+	"go_starter/trails"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+type TranscriptService interface {
+	GenerateTranscriptService(ctx context.Context, request requests.TranscriptRequest) (*responses.TranscriptFileResponse, error)
+	VerifyTranscriptBySerialService(ctx context.Context, request requests.TranscriptSerialRequest) (*responses.TranscriptVerificationResponse, error)
+	VerifyTranscriptDocumentService(ctx context.Context, request requests.TranscriptDocumentRequest) (*responses.TranscriptVerificationResponse, error)
+}
+
+type transcriptService struct {
+	repositoryTranscript repositories.TranscriptRepository
+	repositoryGrade      repositories.GradeRepository
+	repositoryStudent    repositories.StudentRepository
+}
+
+func (t transcriptService) GenerateTranscriptService(ctx context.Context, request requests.TranscriptRequest) (*responses.TranscriptFileResponse, error) {
+	student, err := t.repositoryStudent.GetStudentByIdRepository(ctx, int(request.StudentID))
+	if err != nil {
+		return nil, err
+	}
+	if student.ID == 0 {
+		return nil, errs.New(errs.CodeStudentNotFound)
+	}
+
+	// Only grades locked by a finalized term belong on an official transcript
+	finalGrades, err := t.repositoryGrade.GetFinalGradesByStudentIDRepository(ctx, student.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(finalGrades) == 0 {
+		return nil, errs.New(errs.CodeNoFinalizedGrades)
+	}
+
+	serialNo, err := newSerialNo()
+	if err != nil {
+		return nil, err
+	}
+	issuedAt := time.Now()
+	document := newTranscriptDocument(*student, finalGrades)
+	document.SerialNo = serialNo
+	document.IssuedAt = issuedAt.Format("02-01-2006 15:04:05")
+	document.VerifyURL = transcriptVerifyURL(serialNo)
+
+	content, err := trails.RenderTranscriptPDF(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render transcript: %v", err)
+	}
+
+	// The signature covers the exact bytes handed out, so any edit to the PDF breaks it
+	digest, signature := security.SignDocument(content)
+	err = t.repositoryTranscript.CreateTranscriptRepository(ctx, &models.Transcript{
+		SerialNo:  serialNo,
+		StudentID: student.ID,
+		Digest:    digest,
+		Signature: signature,
+		IssuedAt:  issuedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &responses.TranscriptFileResponse{
+		SerialNo: serialNo,
+		FileName: fmt.Sprintf("transcript-%s-%s.pdf", student.StudentID, serialNo),
+		Content:  content,
+	}
+	return response, nil
+}
+
+func (t transcriptService) VerifyTranscriptBySerialService(ctx context.Context, request requests.TranscriptSerialRequest) (*responses.TranscriptVerificationResponse, error) {
+	transcript, err := t.repositoryTranscript.GetTranscriptBySerialNoRepository(ctx, strings.TrimSpace(request.SerialNo))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &responses.TranscriptVerificationResponse{
+			Valid:    false,
+			Message:  i18n.MessageTranscriptNotIssued,
+			SerialNo: request.SerialNo,
+		}, nil
+	}
+	if err != nil {
+		// A failed lookup is no verdict, the transcript may well have been issued
+		return nil, errs.From(err)
+	}
+	// Without the document only the record of its issue can be checked
+	valid := security.VerifyDocumentSignature(transcript.Digest, transcript.Signature)
+	response := newTranscriptVerificationResponse(*transcript, valid)
+	if valid {
+		response.Message = i18n.MessageTranscriptIssued
+	}
+	return response, nil
+}
+
+func (t transcriptService) VerifyTranscriptDocumentService(ctx context.Context, request requests.TranscriptDocumentRequest) (*responses.TranscriptVerificationResponse, error) {
+	digest := security.DocumentDigest(request.Document)
+	transcript, err := t.repositoryTranscript.GetTranscriptByDigestRepository(ctx, digest)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &responses.TranscriptVerificationResponse{
+			Valid:   false,
+			Message: i18n.MessageTranscriptNotMatching,
+			Digest:  digest,
+		}, nil
+	}
+	if err != nil {
+		return nil, errs.From(err)
+	}
+	// The signature is checked against the uploaded bytes, not the digest on record
+	return newTranscriptVerificationResponse(*transcript, security.VerifyDocument(request.Document, transcript.Signature)), nil
+}
+
+func newTranscriptVerificationResponse(transcript models.Transcript, valid bool) *responses.TranscriptVerificationResponse {
+	response := &responses.TranscriptVerificationResponse{
+		Valid:     valid,
+		SerialNo:  transcript.SerialNo,
+		StudentNo: transcript.Student.StudentID,
+		Firstname: transcript.Student.Firstname,
+		Lastname:  transcript.Student.Lastname,
+		IssuedAt:  transcript.IssuedAt.Format("02-01-2006 15:04:05"),
+		Digest:    transcript.Digest,
+		Signature: transcript.Signature,
+		PublicKey: security.DocumentPublicKey(),
+	}
+	if response.Valid {
+		response.Message = i18n.MessageTranscriptAuthentic
+	} else {
+		response.Message = i18n.MessageTranscriptSignatureInvalid
+	}
+	return response
+}
+
+// newTranscriptDocument groups final grades by term and computes credit-weighted GPAs
+func newTranscriptDocument(student models.Student, finalGrades []models.FinalGrade) trails.TranscriptDocument {
+	document := trails.TranscriptDocument{
+		StudentNo: student.StudentID,
+		Firstname: student.Firstname,
+		Lastname:  student.Lastname,
+	}
+	if !student.Birthday.IsZero() {
+		document.Birthday = student.Birthday.Format("02-01-2006")
+	}
+
+	var totalPoints float64
+	termIndex := map[uint]int{}
+	termPoints := map[uint]float64{}
+	for _, grade := range finalGrades {
+		index, ok := termIndex[grade.TermID]
+		if !ok {
+			index = len(document.Terms)
+			termIndex[grade.TermID] = index
+			document.Terms = append(document.Terms, trails.TranscriptTerm{Name: grade.Term.Name})
+		}
+		credits := courseCredits(grade.Classroom)
+		term := &document.Terms[index]
+		term.Courses = append(term.Courses, trails.TranscriptCourse{
+			SubjectName: grade.Classroom.SubjectName,
+			ClassName:   grade.Classroom.ClassName,
+			Credits:     credits,
+			Letter:      grade.Letter,
+			GradePoint:  grade.GradePoint,
+		})
+		term.Credits += credits
+		termPoints[grade.TermID] += grade.GradePoint * float64(credits)
+		document.TotalCredits += credits
+		totalPoints += grade.GradePoint * float64(credits)
+	}
+	for termID, index := range termIndex {
+		if document.Terms[index].Credits > 0 {
+			document.Terms[index].GPA = termPoints[termID] / float64(document.Terms[index].Credits)
+		}
+	}
+	if document.TotalCredits > 0 {
+		document.CumulativeGPA = totalPoints / float64(document.TotalCredits)
+	}
+	return document
+}
+
+// courseCredits counts classrooms created before credits were tracked as one credit
+func courseCredits(classroom models.Classroom) int {
+	if classroom.Credits <= 0 {
+		return 1
+	}
+	return classroom.Credits
+}
+
+func transcriptVerifyURL(serialNo string) string {
+	baseURL := config.GetEnv("app.public_url", "http://localhost:"+config.Env("app.port"))
+	return strings.TrimRight(baseURL, "/") + "/web/verify-transcript/" + serialNo
+}
+
+func newSerialNo() (string, error) {
+	serial := make([]byte, 10)
+	if _, err := rand.Read(serial); err != nil {
+		return "", err
+	}
+	return strings.ToUpper(hex.EncodeToString(serial)), nil
+}
+
+func NewTranscriptService(
+	repositoryTranscript repositories.TranscriptRepository,
+	repositoryGrade repositories.GradeRepository,
+	repositoryStudent repositories.StudentRepository,
+) TranscriptService {
+	return &transcriptService{
+		repositoryTranscript: repositoryTranscript,
+		repositoryGrade:      repositoryGrade,
+		repositoryStudent:    repositoryStudent,
+	}
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"go_starter/errs"
+	"go_starter/i18n"
+	"go_starter/models"
+	"go_starter/repositories"
+	"go_starter/requests"
+	"math"
+	"testing"
+)
+
+func TestNewTranscriptDocumentGPA(t *testing.T) {
+	networks := models.Classroom{ID: 1, SubjectName: "Networks", Credits: 3}
+	databases := models.Classroom{ID: 2, SubjectName: "Databases", Credits: 1}
+	legacy := models.Classroom{ID: 3, SubjectName: "Seminar"}
+	first := models.Term{ID: 1, Name: "2024/1"}
+	second := models.Term{ID: 2, Name: "2024/2"}
+	document := newTranscriptDocument(models.Student{StudentID: "STU001"}, []models.FinalGrade{
+		{TermID: first.ID, Term: first, Classroom: networks, Letter: "A", GradePoint: 4},
+		{TermID: first.ID, Term: first, Classroom: databases, Letter: "C", GradePoint: 2},
+		{TermID: second.ID, Term: second, Classroom: legacy, Letter: "B", GradePoint: 3},
+	})
+
+	if len(document.Terms) != 2 {
+		t.Fatalf("terms = %d, want 2", len(document.Terms))
+	}
+	// (4*3 + 2*1) / 4 credits
+	if term := document.Terms[0]; term.Credits != 4 || term.GPA != 3.5 {
+		t.Errorf("first term = %d credits, GPA %v; want 4, 3.5", term.Credits, term.GPA)
+	}
+	// A classroom without credits counts as one
+	if term := document.Terms[1]; term.Credits != 1 || term.GPA != 3 {
+		t.Errorf("second term = %d credits, GPA %v; want 1, 3", term.Credits, term.GPA)
+	}
+	// (12 + 2 + 3) / 5 credits
+	if document.TotalCredits != 5 || math.Abs(document.CumulativeGPA-3.4) > 1e-9 {
+		t.Errorf("cumulative = %d credits, GPA %v; want 5, 3.4", document.TotalCredits, document.CumulativeGPA)
+	}
+}
+
+func TestVerifyTranscriptServiceUnknownTranscript(t *testing.T) {
+	db := openTestDatabase(t)
+	service := NewTranscriptService(repositories.NewTranscriptRepository(db), nil, nil)
+	ctx := context.Background()
+
+	bySerial, err := service.VerifyTranscriptBySerialService(ctx, requests.TranscriptSerialRequest{SerialNo: "UNKNOWN"})
+	if err != nil || bySerial.Valid || bySerial.Message != i18n.MessageTranscriptNotIssued {
+		t.Errorf("unknown serial = %+v, %v; want the not issued verdict", bySerial, err)
+	}
+	byDocument, err := service.VerifyTranscriptDocumentService(ctx, requests.TranscriptDocumentRequest{Document: []byte("%PDF-1.4")})
+	if err != nil || byDocument.Valid || byDocument.Message != i18n.MessageTranscriptNotMatching {
+		t.Errorf("unknown document = %+v, %v; want the not matching verdict", byDocument, err)
+	}
+
+	// A lookup that fails says nothing of the transcript, it is no verdict
+	sqlDB, _ := db.DB()
+	sqlDB.Close()
+	if response, err := service.VerifyTranscriptBySerialService(ctx, requests.TranscriptSerialRequest{SerialNo: "UNKNOWN"}); err == nil {
+		t.Errorf("serial on a closed database = %+v, want an error", response)
+	} else if code := errs.From(err).Code; code != errs.CodeInternal {
+		t.Errorf("serial on a closed database = %s, want %s", code, errs.CodeInternal)
+	}
+	if response, err := service.VerifyTranscriptDocumentService(ctx, requests.TranscriptDocumentRequest{Document: []byte("%PDF-1.4")}); err == nil {
+		t.Errorf("document on a closed database = %+v, want an error", response)
+	}
+}
@@ -0,0 +1,341 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/pkg/errors"
+	"go_starter/config"
+	"go_starter/errs"
+	"go_starter/i18n"
+	"go_starter/logs"
+	"go_starter/models"
+	"go_starter/repositories"
+	"go_starter/requests"
+	"go_starter/responses"
+	"go_starter/storage"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type UploadService interface {
+	CreateUploadSessionService(ctx context.Context, request requests.UploadSessionRequest) (*responses.UploadSessionResponse, error)
+	GetUploadSessionService(ctx context.Context, request requests.UploadIDRequest) (*responses.UploadSessionResponse, error)
+	AppendUploadChunkService(ctx context.Context, request requests.UploadChunkRequest) (*responses.UploadSessionResponse, error)
+	DeleteUploadSessionService(ctx context.Context, request requests.UploadIDRequest) (*responses.MessageResponse, error)
+	ExpireUploadSessionsService(ctx context.Context) (*responses.ExpiredUploadsResponse, error)
+}
+
+// uploadDirectory holds chunks and assembled files of resumable uploads until a
+// feature takes them over
+const uploadDirectory = "uploads"
+
+type uploadService struct {
+	repositoryUpload repositories.UploadRepository
+	storage          storage.Storage
+}
+
+func (u uploadService) CreateUploadSessionService(ctx context.Context, request requests.UploadSessionRequest) (*responses.UploadSessionResponse, error) {
+	maxSize := uploadConfigInt("uploads.max_size_mb", 100) * megabyte
+	if request.Size > maxSize {
+		return nil, errs.New(errs.CodeUploadTooLarge).WithParam("max_mb", strconv.FormatInt(maxSize/megabyte, 10))
+	}
+	checksum := strings.ToLower(request.Checksum)
+	if checksum != "" {
+		if decoded, err := hex.DecodeString(checksum); err != nil || len(decoded) != sha256.Size {
+			return nil, errs.New(errs.CodeInvalidChecksum)
+		}
+	}
+	id, err := newRandomID()
+	if err != nil {
+		return nil, err
+	}
+	session := &models.UploadSession{
+		ID:          id,
+		FileName:    path.Base(strings.ReplaceAll(request.FileName, "\\", "/")),
+		ContentType: request.ContentType,
+		Size:        request.Size,
+		Checksum:    checksum,
+		Status:      models.UploadPending,
+		ExpiresAt:   uploadExpiresAt(),
+	}
+	if err = u.repositoryUpload.CreateUploadSessionRepository(ctx, session); err != nil {
+		return nil, err
+	}
+	return newUploadSessionResponse(*session), nil
+}
+
+func (u uploadService) GetUploadSessionService(ctx context.Context, request requests.UploadIDRequest) (*responses.UploadSessionResponse, error) {
+	session, err := u.getUploadSession(ctx, request.UploadID)
+	if err != nil {
+		return nil, err
+	}
+	// Every chunk arrived but assembly failed, try again
+	if session.Status == models.UploadPending && session.Offset == session.Size {
+		if err = u.assembleUpload(ctx, session); err != nil {
+			return nil, err
+		}
+	}
+	return newUploadSessionResponse(*session), nil
+}
+
+func (u uploadService) AppendUploadChunkService(ctx context.Context, request requests.UploadChunkRequest) (*responses.UploadSessionResponse, error) {
+	session, err := u.getUploadSession(ctx, request.UploadID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status == models.UploadCompleted {
+		return nil, errs.New(errs.CodeUploadCompleted)
+	}
+	if request.Offset != session.Offset {
+		return nil, errs.New(errs.CodeUploadOffsetMismatch).WithParam("offset", strconv.FormatInt(session.Offset, 10))
+	}
+	size := int64(len(request.Content))
+	if session.Offset+size > session.Size {
+		return nil, errs.New(errs.CodeChunkTooLarge)
+	}
+
+	sum := sha256.Sum256(request.Content)
+	if request.Checksum != "" {
+		if err = verifyChunkChecksum(request.Checksum, sum[:]); err != nil {
+			return nil, err
+		}
+	}
+	checksum := hex.EncodeToString(sum[:])
+
+	// Every request writes its own key, so a racing request for the same offset, even
+	// with the same content, can neither overwrite nor delete the chunk that won
+	nonce, err := newRandomID()
+	if err != nil {
+		return nil, err
+	}
+	key := path.Join(uploadDirectory, session.ID, "chunks", fmt.Sprintf("%020d-%s", request.Offset, nonce))
+	if err = u.storage.Put(ctx, key, request.Content, "application/octet-stream"); err != nil {
+		return nil, fmt.Errorf("failed to write chunk to storage: %v", err)
+	}
+	chunk := &models.UploadChunk{
+		UploadSessionID: session.ID,
+		Offset:          request.Offset,
+		Size:            size,
+		Checksum:        checksum,
+		StorageKey:      key,
+	}
+	expiresAt := uploadExpiresAt()
+	appended, err := u.repositoryUpload.AppendUploadChunkRepository(ctx, chunk, expiresAt)
+	if err != nil || !appended {
+		if deleteErr := u.storage.Delete(ctx, key); deleteErr != nil && !errors.Is(deleteErr, storage.ErrNotFound) {
+			logs.Error(deleteErr)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return nil, errs.New(errs.CodeUploadOffsetMoved)
+	}
+
+	session.Offset += size
+	session.ExpiresAt = expiresAt
+	session.Chunks = append(session.Chunks, *chunk)
+	if session.Offset == session.Size {
+		if err = u.assembleUpload(ctx, session); err != nil {
+			return nil, err
+		}
+	}
+	return newUploadSessionResponse(*session), nil
+}
+
+func (u uploadService) DeleteUploadSessionService(ctx context.Context, request requests.UploadIDRequest) (*responses.MessageResponse, error) {
+	session, err := u.repositoryUpload.GetUploadSessionRepository(ctx, request.UploadID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, errs.New(errs.CodeUploadNotFound)
+	}
+	if err = discardUpload(ctx, u.repositoryUpload, u.storage, *session); err != nil {
+		return nil, err
+	}
+	response := &responses.MessageResponse{Message: i18n.MessageDeleted}
+	return response, nil
+}
+
+// ExpireUploadSessionsService removes abandoned uploads and completed ones nobody took over
+func (u uploadService) ExpireUploadSessionsService(ctx context.Context) (*responses.ExpiredUploadsResponse, error) {
+	sessions, err := u.repositoryUpload.GetExpiredUploadSessionsRepository(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	response := &responses.ExpiredUploadsResponse{Expired: []string{}}
+	for _, session := range sessions {
+		if err = discardUpload(ctx, u.repositoryUpload, u.storage, session); err != nil {
+			return nil, err
+		}
+		response.Expired = append(response.Expired, session.ID)
+	}
+	return response, nil
+}
+
+func (u uploadService) getUploadSession(ctx context.Context, id string) (*models.UploadSession, error) {
+	session, err := u.repositoryUpload.GetUploadSessionRepository(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, errs.New(errs.CodeUploadNotFound)
+	}
+	if time.Now().After(session.ExpiresAt) {
+		return nil, errs.New(errs.CodeUploadExpired)
+	}
+	return session, nil
+}
+
+// assembleUpload joins the chunks in offset order into one stored file. A file that
+// does not match the checksum given at creation is discarded and must be uploaded again.
+func (u uploadService) assembleUpload(ctx context.Context, session *models.UploadSession) error {
+	var buffer bytes.Buffer
+	for _, chunk := range session.Chunks {
+		content, err := u.storage.Get(ctx, chunk.StorageKey)
+		if err != nil {
+			return fmt.Errorf("failed to read chunk from storage: %v", err)
+		}
+		sum := sha256.Sum256(content)
+		if hex.EncodeToString(sum[:]) != chunk.Checksum {
+			return errs.Wrap(errs.CodeInternal, errors.New("stored chunk is corrupted"))
+		}
+		buffer.Write(content)
+	}
+	content := buffer.Bytes()
+	if int64(len(content)) != session.Size {
+		return errs.Wrap(errs.CodeInternal, errors.New("assembled upload has the wrong size"))
+	}
+	if session.Checksum != "" {
+		sum := sha256.Sum256(content)
+		if hex.EncodeToString(sum[:]) != session.Checksum {
+			if err := discardUpload(ctx, u.repositoryUpload, u.storage, *session); err != nil {
+				logs.Error(err)
+			}
+			return errs.New(errs.CodeUploadChecksumMismatch)
+		}
+	}
+
+	contentType := session.ContentType
+	if contentType == "" {
+		contentType = http.DetectContentType(content)
+	}
+	key := path.Join(uploadDirectory, session.ID, "file")
+	if err := u.storage.Put(ctx, key, content, contentType); err != nil {
+		return fmt.Errorf("failed to write upload to storage: %v", err)
+	}
+	if err := u.repositoryUpload.CompleteUploadSessionRepository(ctx, session.ID, key); err != nil {
+		return err
+	}
+	for _, chunk := range session.Chunks {
+		if err := u.storage.Delete(ctx, chunk.StorageKey); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			logs.Error(err)
+		}
+	}
+	session.Status = models.UploadCompleted
+	session.StorageKey = key
+	return nil
+}
+
+// takeCompletedUpload reads a completed upload for a feature that stores it elsewhere,
+// the caller discards the upload once it is saved
+func takeCompletedUpload(ctx context.Context, repositoryUpload repositories.UploadRepository, fileStorage storage.Storage, id string) (*models.UploadSession, []byte, error) {
+	session, err := repositoryUpload.GetUploadSessionRepository(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if session == nil || time.Now().After(session.ExpiresAt) {
+		return nil, nil, errs.New(errs.CodeUploadNotFound)
+	}
+	if session.Status != models.UploadCompleted {
+		return nil, nil, errs.New(errs.CodeUploadNotCompleted)
+	}
+	content, err := fileStorage.Get(ctx, session.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return session, content, nil
+}
+
+// discardUpload deletes the stored chunks and file of an upload and then its rows
+func discardUpload(ctx context.Context, repositoryUpload repositories.UploadRepository, fileStorage storage.Storage, session models.UploadSession) error {
+	keys := []string{}
+	for _, chunk := range session.Chunks {
+		keys = append(keys, chunk.StorageKey)
+	}
+	if session.StorageKey != "" {
+		keys = append(keys, session.StorageKey)
+	}
+	for _, key := range keys {
+		if err := fileStorage.Delete(ctx, key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+	}
+	return repositoryUpload.DeleteUploadSessionRepository(ctx, session.ID)
+}
+
+// verifyChunkChecksum checks an Upload-Checksum header such as "sha256 <base64 digest>"
+func verifyChunkChecksum(header string, sum []byte) error {
+	parts := strings.SplitN(strings.TrimSpace(header), " ", 2)
+	if len(parts) != 2 || strings.ToLower(parts[0]) != "sha256" {
+		return errs.New(errs.CodeInvalidChunkChecksum)
+	}
+	expected, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return errs.New(errs.CodeInvalidChunkChecksum)
+	}
+	if !bytes.Equal(expected, sum) {
+		return errs.New(errs.CodeChunkChecksumMismatch)
+	}
+	return nil
+}
+
+func newUploadSessionResponse(session models.UploadSession) *responses.UploadSessionResponse {
+	return &responses.UploadSessionResponse{
+		ID:        session.ID,
+		FileName:  session.FileName,
+		Size:      session.Size,
+		Offset:    session.Offset,
+		ChunkSize: uploadConfigInt("uploads.chunk_size_mb", 5) * megabyte,
+		Completed: session.Status == models.UploadCompleted,
+		ExpiresAt: session.ExpiresAt.Format("02-01-2006 15:04:05"),
+	}
+}
+
+// uploadExpiresAt is refreshed by every chunk, only abandoned uploads expire
+func uploadExpiresAt() time.Time {
+	return time.Now().Add(time.Duration(uploadConfigInt("uploads.expiry_hours", 24)) * time.Hour)
+}
+
+func uploadConfigInt(key string, defaultValue int64) int64 {
+	value, err := strconv.ParseInt(config.GetEnv(key, strconv.FormatInt(defaultValue, 10)), 10, 64)
+	if err != nil || value <= 0 {
+		logs.Error(errors.Errorf("invalid %s", key))
+		return defaultValue
+	}
+	return value
+}
+
+// newRandomID returns 32 random hex digits
+func newRandomID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+func NewUploadService(repositoryUpload repositories.UploadRepository, storage storage.Storage) UploadService {
+	return &uploadService{
+		repositoryUpload: repositoryUpload,
+		storage:          storage,
+	}
+}
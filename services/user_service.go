@@ -1,13 +1,16 @@
 package services
 
 import (
-	"fmt"
+	"context"
 	"go_starter/errs"
+	"go_starter/i18n"
 	"go_starter/models"
 	"go_starter/repositories"
 	"go_starter/requests"
 	"go_starter/responses"
 	"go_starter/security"
+	"go_starter/storage"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -17,29 +20,32 @@ type UserService interface {
 
 	// Login
 	//SignUpUserService(request requests.SignUpUserRequest) (*responses.SignUpUserResponse, error)
-	SignInUserService(request requests.SignInUserRequest) (*responses.SignInUserResponse, error)
-	LoginService(request requests.LoginRequest) (*responses.ResponseLogin, string, error)
+	SignInUserService(ctx context.Context, request requests.SignInUserRequest) (*responses.SignInUserResponse, error)
+	LoginService(ctx context.Context, request requests.LoginRequest) (*responses.ResponseLogin, string, error)
 
 	//CRUD
-	GetAllUserService() ([]responses.UserResponse, error)
-	GetByIdUserService(id uint) (*responses.UserResponse, error)
-	GetByPhoneService(phone string) (*responses.UserResponse, error)
+	GetAllUserService(ctx context.Context) ([]responses.UserResponse, error)
+	GetByIdUserService(ctx context.Context, id uint) (*responses.UserResponse, error)
+	GetByPhoneService(ctx context.Context, phone string) (*responses.UserResponse, error)
 	//CreateUserService(request requests.CreateUserRequest) (*responses.MessageUserResponse, error)
-	UpdateUserService(request requests.UpdateUserRequest) (*responses.MessageUserResponse, error)
-	DeleteUserService(request requests.DeleteUserRequest) (*responses.MessageUserResponse, error)
+	UpdateUserService(ctx context.Context, request requests.UpdateUserRequest) (*responses.MessageUserResponse, error)
+	DeleteUserService(ctx context.Context, request requests.DeleteUserRequest) (*responses.MessageUserResponse, error)
 }
 
 type userService struct {
 	repositoryUserRepository repositories.UserRepository
+	repositoryFile           repositories.FileRepository
+	unitOfWork               repositories.UnitOfWork
+	storage                  storage.Storage
 }
 
 //====================================================================================
 
-func (u *userService) LoginService(request requests.LoginRequest) (*responses.ResponseLogin, string, error) {
+func (u *userService) LoginService(ctx context.Context, request requests.LoginRequest) (*responses.ResponseLogin, string, error) {
 
-	getUserData, err := u.repositoryUserRepository.CheckEmailAlreadyHas(models.User{Email: request.Email})
+	getUserData, err := u.repositoryUserRepository.CheckEmailAlreadyHas(ctx, models.User{Email: request.Email})
 	if err != nil {
-		return nil, "", errors.New("username not found")
+		return nil, "", err
 	}
 	encryptPassword, err := security.EncryptPassword(request.Password)
 	if err != nil {
@@ -57,16 +63,16 @@ func (u *userService) LoginService(request requests.LoginRequest) (*responses.Re
 			Password: encryptPassword,
 			Token:    newAccessToken,
 		}
-		err := u.repositoryUserRepository.CreateUserRepository(&newUser)
+		err := u.repositoryUserRepository.CreateUserRepository(ctx, &newUser)
 		if err != nil {
-			return nil, "", errors.New("Can't Create user")
+			return nil, "", err
 		}
-		return nil, "", errors.New("Create succeeded")
+		return nil, "", errs.New(errs.CodeAccountCreated)
 	}
 
 	err = security.VerifyPassword(getUserData.Password, request.Password)
 	if err != nil {
-		return nil, "", fmt.Errorf("InvalidPassword")
+		return nil, "", errs.New(errs.CodeInvalidCredentials)
 	}
 
 	validToken, err := security.CheckToken(getUserData.Token)
@@ -294,24 +300,41 @@ func (u *userService) LoginService(request requests.LoginRequest) (*responses.Re
 // }
 
 // DeleteUserService implements UserService.
-func (u *userService) DeleteUserService(request requests.DeleteUserRequest) (*responses.MessageUserResponse, error) {
+func (u *userService) DeleteUserService(ctx context.Context, request requests.DeleteUserRequest) (*responses.MessageUserResponse, error) {
 
 	if request.ID == 0 {
-		return nil, errors.New("ID can't be empty")
+		return nil, errs.New(errs.CodeValidationFailed).WithField("id", "validation.required")
 	}
-	err := u.repositoryUserRepository.DeleteUserRepository(request.ID)
+	ownerID := strconv.FormatUint(uint64(request.ID), 10)
+	// Delete the user and its photo reference together
+	var image string
+	err := u.unitOfWork.Do(ctx, func(tx repositories.TransactionRepositories) error {
+		var err error
+		image, _, err = tx.File.GetPhotoRepository(ctx, models.FileOwnerUserPhoto, ownerID)
+		if err != nil {
+			return err
+		}
+		if err = tx.User.DeleteUserRepository(ctx, request.ID); err != nil {
+			return err
+		}
+		// Drop the photo reference, the file goes once nothing else shares it
+		return tx.File.DeleteFileReferenceRepository(ctx, models.FileOwnerUserPhoto, ownerID)
+	})
 	if err != nil {
 		return nil, err
 	}
-	response := &responses.MessageUserResponse{Message: "Success"}
+	if image != "" {
+		releasePhoto(ctx, u.repositoryFile, u.storage, image)
+	}
+	response := &responses.MessageUserResponse{Message: i18n.MessageSuccess}
 
 	return response, nil
 }
 
 // GetAllUserService implements UserService.
-func (u *userService) GetAllUserService() ([]responses.UserResponse, error) {
+func (u *userService) GetAllUserService(ctx context.Context) ([]responses.UserResponse, error) {
 
-	getAllUser, err := u.repositoryUserRepository.GetAllUserRepository()
+	getAllUser, err := u.repositoryUserRepository.GetAllUserRepository(ctx)
 
 	if err != nil {
 		return nil, err
@@ -325,9 +348,11 @@ func (u *userService) GetAllUserService() ([]responses.UserResponse, error) {
 	for _, data := range getAllUser {
 		userResponse := responses.UserResponse{
 
-			ID:        data.ID,
-			Email:     data.Email,
-			CreatedAt: data.CreatedAt.Format("02-01-2006 15:01:05"),
+			ID:            data.ID,
+			Email:         data.Email,
+			ImageURL:      photoURL(data.Image),
+			ImageVariants: photoVariants(data.Image),
+			CreatedAt:     data.CreatedAt.Format("02-01-2006 15:01:05"),
 			UpdatedAt: data.UpdatedAt.Format("02-01-2006 15:01:05"),
 		}
 		response = append(response, userResponse)
@@ -336,9 +361,9 @@ func (u *userService) GetAllUserService() ([]responses.UserResponse, error) {
 }
 
 // GetByIdUserService implements UserService.
-func (u *userService) GetByIdUserService(id uint) (*responses.UserResponse, error) {
+func (u *userService) GetByIdUserService(ctx context.Context, id uint) (*responses.UserResponse, error) {
 
-	data, err := u.repositoryUserRepository.GetByIdUserRepository(uint(id))
+	data, err := u.repositoryUserRepository.GetByIdUserRepository(ctx, uint(id))
 
 	if err != nil {
 		return nil, err
@@ -346,18 +371,20 @@ func (u *userService) GetByIdUserService(id uint) (*responses.UserResponse, erro
 
 	response := &responses.UserResponse{
 
-		ID:        data.ID,
-		Email:     data.Email,
-		CreatedAt: data.CreatedAt.Format("02-01-2006 15:01:05"),
+		ID:            data.ID,
+		Email:         data.Email,
+		ImageURL:      photoURL(data.Image),
+		ImageVariants: photoVariants(data.Image),
+		CreatedAt:     data.CreatedAt.Format("02-01-2006 15:01:05"),
 		UpdatedAt: data.UpdatedAt.Format("02-01-2006 15:01:05"),
 	}
 	return response, nil
 }
 
 // GetByUserNameService implements UserService.
-func (u *userService) GetByPhoneService(phone string) (*responses.UserResponse, error) {
+func (u *userService) GetByPhoneService(ctx context.Context, phone string) (*responses.UserResponse, error) {
 
-	data, err := u.repositoryUserRepository.GetByPhoneRepository(phone)
+	data, err := u.repositoryUserRepository.GetByPhoneRepository(ctx, phone)
 
 	if err != nil {
 		return nil, err
@@ -365,9 +392,11 @@ func (u *userService) GetByPhoneService(phone string) (*responses.UserResponse,
 
 	response := &responses.UserResponse{
 
-		ID:        data.ID,
-		Email:     data.Email,
-		CreatedAt: data.CreatedAt.Format("02-01-2006 15:01:05"),
+		ID:            data.ID,
+		Email:         data.Email,
+		ImageURL:      photoURL(data.Image),
+		ImageVariants: photoVariants(data.Image),
+		CreatedAt:     data.CreatedAt.Format("02-01-2006 15:01:05"),
 		UpdatedAt: data.UpdatedAt.Format("02-01-2006 15:01:05"),
 	}
 	return response, nil
@@ -420,26 +449,29 @@ func (u *userService) GetByPhoneService(phone string) (*responses.UserResponse,
 // }
 
 // SignInUserService implements UserService.
-func (u *userService) SignInUserService(request requests.SignInUserRequest) (*responses.SignInUserResponse, error) {
+func (u *userService) SignInUserService(ctx context.Context, request requests.SignInUserRequest) (*responses.SignInUserResponse, error) {
 
 	if request.Email == "" {
-		return nil, errs.ErrorBadRequest("Email Cant Be Empty")
+		return nil, errs.New(errs.CodeValidationFailed).WithField("email", "validation.required")
 	}
 
 	trimSpaceUser := strings.TrimSpace(request.Password)
 	if trimSpaceUser == "" {
-		return nil, errs.ErrorBadRequest("Password Cant Be Empty")
+		return nil, errs.New(errs.CodeValidationFailed).WithField("password", "validation.required")
 	}
 
-	getUserData, err := u.repositoryUserRepository.GetByEmailRepository(request.Email)
+	getUserData, err := u.repositoryUserRepository.GetByEmailRepository(ctx, request.Email)
 
 	if err != nil {
 		return nil, err
 	}
+	if getUserData == nil {
+		return nil, errs.New(errs.CodeInvalidCredentials)
+	}
 	err = security.VerifyPassword(getUserData.Password, request.Password)
 
 	if err != nil {
-		return nil, fmt.Errorf("password does not match")
+		return nil, errs.New(errs.CodeInvalidCredentials)
 	}
 	response := responses.SignInUserResponse{
 		Email: getUserData.Email,
@@ -450,22 +482,30 @@ func (u *userService) SignInUserService(request requests.SignInUserRequest) (*re
 }
 
 // UpdateUserService implements UserService.
-func (u *userService) UpdateUserService(request requests.UpdateUserRequest) (*responses.MessageUserResponse, error) {
+func (u *userService) UpdateUserService(ctx context.Context, request requests.UpdateUserRequest) (*responses.MessageUserResponse, error) {
 
 	data := models.User{
 		ID:    request.ID,
 		Email: request.Email,
 	}
-	if err := u.repositoryUserRepository.UpdateUserRepository(&data); err != nil {
+	if err := u.repositoryUserRepository.UpdateUserRepository(ctx, &data); err != nil {
 		return nil, err
 	}
-	response := &responses.MessageUserResponse{Message: "Success"}
+	response := &responses.MessageUserResponse{Message: i18n.MessageSuccess}
 
 	return response, nil
 }
 
-func NewUserService(repositoryUserRepository repositories.UserRepository) UserService {
+func NewUserService(
+	repositoryUserRepository repositories.UserRepository,
+	repositoryFile repositories.FileRepository,
+	unitOfWork repositories.UnitOfWork,
+	storage storage.Storage,
+) UserService {
 	return &userService{
 		repositoryUserRepository: repositoryUserRepository,
+		repositoryFile:           repositoryFile,
+		unitOfWork:               unitOfWork,
+		storage:                  storage,
 	}
 }
@@ -159,16 +159,12 @@ func CreateRepositories(filename string) {
 		//arr := strings.Split(pwd, "/")
 		//projectName := arr[len(arr)-1]
 
-		projectName := "go_starter"
-
 		fmt.Fprintf(destination, "package repositories")
 		fmt.Fprintf(destination, "\n\n")
 		fmt.Fprintf(destination, `import (`)
 		fmt.Fprintf(destination, "\n")
 		fmt.Fprintf(destination, `"gorm.io/gorm"`)
 		fmt.Fprintf(destination, "\n")
-		fmt.Fprintf(destination, `"%s/models"`, projectName)
-		fmt.Fprintf(destination, "\n")
 		fmt.Fprintf(destination, ")")
 		fmt.Fprintf(destination, "\n\n")
 		fmt.Fprintf(destination, `type %sRepository interface{`, upperString)
@@ -181,10 +177,6 @@ func CreateRepositories(filename string) {
 		fmt.Fprintf(destination, "\n\n")
 		fmt.Fprintf(destination, `func New%sRepository(db *gorm.DB) %sRepository {`, upperString, upperString)
 		fmt.Fprintf(destination, "\n")
-		fmt.Fprintf(destination, `// db.Migrator().DropTable(models.%s{})`, upperString)
-		fmt.Fprintf(destination, "\n")
-		fmt.Fprintf(destination, `db.AutoMigrate(models.%s{})`, upperString)
-		fmt.Fprintf(destination, "\n")
 		fmt.Fprintf(destination, `	return &%sRepository{db: db}`, lowerString)
 		fmt.Fprintf(destination, "\n")
 		fmt.Fprintf(destination, `}`)
@@ -194,6 +186,7 @@ func CreateRepositories(filename string) {
 	}
 
 	fmt.Println("Created Repository successfully", file)
+	fmt.Println("Add its table with: go run ./cmd/migrate create create_"+filename+"s")
 }
 
 func CreateServices(filename string) {
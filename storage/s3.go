@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"io"
+)
+
+type S3Config struct {
+	Endpoint  string
+	AccessKey string
+	SecretKey string
+	Bucket    string
+	Region    string
+	UseSSL    bool
+}
+
+type s3Storage struct {
+	client *minio.Client
+	bucket string
+}
+
+func (s s3Storage) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.PutObject(ctx, s.bucket, cleaned, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	return err
+}
+
+func (s s3Storage) Get(ctx context.Context, key string) ([]byte, error) {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+	object, err := s.client.GetObject(ctx, s.bucket, cleaned, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, s3Error(err)
+	}
+	defer object.Close()
+	data, err := io.ReadAll(object)
+	if err != nil {
+		return nil, s3Error(err)
+	}
+	return data, nil
+}
+
+func (s s3Storage) Delete(ctx context.Context, key string) error {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	// S3 deletes are idempotent, so check first to report missing objects like the local driver
+	if _, err = s.Stat(ctx, cleaned); err != nil {
+		return err
+	}
+	return s.client.RemoveObject(ctx, s.bucket, cleaned, minio.RemoveObjectOptions{})
+}
+
+func (s s3Storage) Stat(ctx context.Context, key string) (*Object, error) {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+	info, err := s.client.StatObject(ctx, s.bucket, cleaned, minio.StatObjectOptions{})
+	if err != nil {
+		return nil, s3Error(err)
+	}
+	return &Object{
+		Key:         info.Key,
+		Size:        info.Size,
+		ContentType: info.ContentType,
+		ModTime:     info.LastModified,
+	}, nil
+}
+
+func (s s3Storage) List(ctx context.Context, prefix string) ([]Object, error) {
+	objects := []Object{}
+	for info := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+	}) {
+		if info.Err != nil {
+			return nil, s3Error(info.Err)
+		}
+		objects = append(objects, Object{
+			Key:         info.Key,
+			Size:        info.Size,
+			ContentType: info.ContentType,
+			ModTime:     info.LastModified,
+		})
+	}
+	return objects, nil
+}
+
+func s3Error(err error) error {
+	switch minio.ToErrorResponse(err).Code {
+	case "NoSuchKey", "NotFound":
+		return ErrNotFound
+	}
+	return err
+}
+
+// NewS3Storage connects to any S3 compatible endpoint (AWS, MinIO) using path-style
+// bucket lookup and creates the bucket when it does not exist yet.
+func NewS3Storage(s3Config S3Config) (Storage, error) {
+	client, err := minio.New(s3Config.Endpoint, &minio.Options{
+		Creds:        credentials.NewStaticV4(s3Config.AccessKey, s3Config.SecretKey, ""),
+		Secure:       s3Config.UseSSL,
+		Region:       s3Config.Region,
+		BucketLookup: minio.BucketLookupPath,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	exists, err := client.BucketExists(ctx, s3Config.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach storage bucket %s: %v", s3Config.Bucket, err)
+	}
+	if !exists {
+		err = client.MakeBucket(ctx, s3Config.Bucket, minio.MakeBucketOptions{Region: s3Config.Region})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &s3Storage{client: client, bucket: s3Config.Bucket}, nil
+}
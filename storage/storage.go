@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go_starter/config"
+	"path"
+	"strings"
+	"time"
+)
+
+var ErrNotFound = errors.New("object not found")
+
+// TempFilePrefix marks files being written by the local backend, a crash can leave them behind
+const TempFilePrefix = ".upload-"
+
+type Object struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// Storage keeps uploaded files under slash separated keys such as "ceit/2024/images/x.png".
+// ctx bounds the calls to a remote backend, the request they serve going away cancels them.
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+	Stat(ctx context.Context, key string) (*Object, error)
+	List(ctx context.Context, prefix string) ([]Object, error)
+}
+
+// NewStorage builds the backend selected by storage.driver
+func NewStorage() (Storage, error) {
+	driver := config.GetEnv("storage.driver", "local")
+	switch driver {
+	case "local":
+		return NewLocalStorage(config.GetEnv("storage.local.root", "./assets")), nil
+	case "s3":
+		return NewS3Storage(S3Config{
+			Endpoint:  config.Env("storage.s3.endpoint"),
+			AccessKey: config.Env("storage.s3.access_key"),
+			SecretKey: config.Env("storage.s3.secret_key"),
+			Bucket:    config.Env("storage.s3.bucket"),
+			Region:    config.Env("storage.s3.region"),
+			UseSSL:    config.Env("storage.s3.use_ssl") == "true",
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", driver)
+	}
+}
+
+// KeyFromPath turns a stored image path into a storage key. Rows written before the
+// storage backend existed hold paths relative to the working directory, e.g.
+// "assets/ceit/2024/images/x.png".
+func KeyFromPath(filePath string) string {
+	return strings.TrimPrefix(normalizeKey(filePath), "assets/")
+}
+
+// normalizeKey resolves ".." against a virtual root so a key can never point outside it
+func normalizeKey(key string) string {
+	return strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(key, "\\", "/")), "/")
+}
+
+func cleanKey(key string) (string, error) {
+	cleaned := normalizeKey(key)
+	if cleaned == "" {
+		return "", errors.New("empty storage key")
+	}
+	return cleaned, nil
+}
+
+// ContentKey addresses data by its SHA-256 under prefix, fanned out by the first two hex
+// digits, e.g. "ceit/2024/images/ab/ab12...ef.jpg". Identical content always gets the same key.
+func ContentKey(prefix string, data []byte, extension string) (key string, hash string) {
+	sum := sha256.Sum256(data)
+	hash = hex.EncodeToString(sum[:])
+	return path.Join(prefix, hash[:2], hash+extension), hash
+}
@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+)
+
+// backends returns every driver the contract runs against: the local one in a temp dir
+// and the S3 one against an in-process fake, so both answer the same way to the services.
+func backends(t *testing.T) map[string]Storage {
+	fake := gofakes3.New(s3mem.New()).Server()
+	// minio sends "delimiter=" for recursive listings; S3 reads that as no delimiter but the
+	// fake splits on it, so drop the empty parameter before it reaches the fake
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Has("delimiter") && query.Get("delimiter") == "" {
+			query.Del("delimiter")
+			r.URL.RawQuery = query.Encode()
+		}
+		fake.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	s3, err := NewS3Storage(S3Config{
+		Endpoint:  strings.TrimPrefix(server.URL, "http://"),
+		AccessKey: "test",
+		SecretKey: "test",
+		Bucket:    "ceit",
+		Region:    "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("s3 storage: %v", err)
+	}
+	return map[string]Storage{
+		"local": NewLocalStorage(t.TempDir()),
+		"s3":    s3,
+	}
+}
+
+func TestStorageContract(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			data := []byte("\x89PNG fake image")
+			if err := store.Put(ctx, "ceit/2024/images/a.png", data, "image/png"); err != nil {
+				t.Fatalf("put: %v", err)
+			}
+
+			got, err := store.Get(ctx, "ceit/2024/images/a.png")
+			if err != nil || !bytes.Equal(got, data) {
+				t.Fatalf("get = %q, %v; want %q", got, err, data)
+			}
+
+			// Keys are normalized, a leading slash or backslashes address the same object
+			if got, err = store.Get(ctx, "/ceit\\2024\\images\\a.png"); err != nil || !bytes.Equal(got, data) {
+				t.Fatalf("get normalized key = %q, %v", got, err)
+			}
+
+			object, err := store.Stat(ctx, "ceit/2024/images/a.png")
+			if err != nil {
+				t.Fatalf("stat: %v", err)
+			}
+			if object.Key != "ceit/2024/images/a.png" || object.Size != int64(len(data)) || object.ContentType != "image/png" {
+				t.Fatalf("stat = %+v", object)
+			}
+
+			// Put replaces an existing object
+			if err = store.Put(ctx, "ceit/2024/images/a.png", []byte("replaced"), "image/png"); err != nil {
+				t.Fatalf("put again: %v", err)
+			}
+			if got, _ = store.Get(ctx, "ceit/2024/images/a.png"); string(got) != "replaced" {
+				t.Fatalf("get after replace = %q", got)
+			}
+
+			if err = store.Put(ctx, "ceit/2024/files/b.pdf", []byte("%PDF"), "application/pdf"); err != nil {
+				t.Fatalf("put second: %v", err)
+			}
+			if err = store.Put(ctx, "other/c.txt", []byte("c"), "text/plain"); err != nil {
+				t.Fatalf("put third: %v", err)
+			}
+			objects, err := store.List(ctx, "ceit/")
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			keys := []string{}
+			for _, object := range objects {
+				keys = append(keys, object.Key)
+			}
+			sort.Strings(keys)
+			if strings.Join(keys, ",") != "ceit/2024/files/b.pdf,ceit/2024/images/a.png" {
+				t.Fatalf("list = %v", keys)
+			}
+			if objects, err = store.List(ctx, "missing/"); err != nil || len(objects) != 0 {
+				t.Fatalf("list missing prefix = %v, %v", objects, err)
+			}
+
+			if err = store.Delete(ctx, "ceit/2024/images/a.png"); err != nil {
+				t.Fatalf("delete: %v", err)
+			}
+			if _, err = store.Get(ctx, "ceit/2024/images/a.png"); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("get deleted = %v, want ErrNotFound", err)
+			}
+			if _, err = store.Stat(ctx, "ceit/2024/images/a.png"); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("stat deleted = %v, want ErrNotFound", err)
+			}
+			if err = store.Delete(ctx, "ceit/2024/images/a.png"); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("delete deleted = %v, want ErrNotFound", err)
+			}
+
+			if err = store.Put(ctx, "", data, "image/png"); err == nil {
+				t.Fatal("put with an empty key succeeded")
+			}
+		})
+	}
+}
+
+func TestStorageCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for name, store := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			if _, err := store.List(ctx, "ceit/"); !errors.Is(err, context.Canceled) {
+				t.Errorf("list = %v, want %v", err, context.Canceled)
+			}
+			// The local backend has nothing to wait on in a single file call
+			if name == "local" {
+				return
+			}
+			if err := store.Put(ctx, "ceit/a.png", []byte("a"), "image/png"); !errors.Is(err, context.Canceled) {
+				t.Errorf("put = %v, want %v", err, context.Canceled)
+			}
+			if _, err := store.Get(ctx, "ceit/a.png"); !errors.Is(err, context.Canceled) {
+				t.Errorf("get = %v, want %v", err, context.Canceled)
+			}
+			if _, err := store.Stat(ctx, "ceit/a.png"); !errors.Is(err, context.Canceled) {
+				t.Errorf("stat = %v, want %v", err, context.Canceled)
+			}
+		})
+	}
+}
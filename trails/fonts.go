@@ -0,0 +1,19 @@
+package trails
+
+import (
+	"github.com/go-fonts/dejavu/dejavusans"
+	"github.com/go-fonts/dejavu/dejavusansbold"
+	"github.com/go-fonts/dejavu/dejavusansoblique"
+	"github.com/go-pdf/fpdf"
+)
+
+// documentFont is the family printed documents are set in. DejaVu Sans has the Lao
+// block, which the core PDF fonts and the Go fonts lack, so Lao names print as written.
+const documentFont = "DejaVu"
+
+// addDocumentFont embeds documentFont in its regular, bold and italic style
+func addDocumentFont(pdf *fpdf.Fpdf) {
+	pdf.AddUTF8FontFromBytes(documentFont, "", dejavusans.TTF)
+	pdf.AddUTF8FontFromBytes(documentFont, "B", dejavusansbold.TTF)
+	pdf.AddUTF8FontFromBytes(documentFont, "I", dejavusansoblique.TTF)
+}
@@ -2,7 +2,7 @@ package trails
 
 import (
 	"github.com/gofiber/fiber/v2"
-	"github.com/pkg/errors"
+	"go_starter/errs"
 	"io/ioutil"
 )
 
@@ -14,13 +14,14 @@ func HandleMultipartFormData(ctx *fiber.Ctx) ([]byte, error) {
 	// Parse the multipart form data
 	form, err := ctx.MultipartForm()
 	if err != nil {
-		return nil, err
+		// Not a multipart body, or a malformed one
+		return nil, errs.Wrap(errs.CodeBadRequest, err)
 	}
 
 	// Retrieve the file from the form data
 	files := form.File["image"]
 	if len(files) == 0 {
-		return nil, errors.New("no image file uploaded")
+		return nil, errs.New(errs.CodeValidationFailed).WithField("image", "validation.required")
 	}
 
 	// Get the first file from the slice
@@ -28,7 +29,7 @@ func HandleMultipartFormData(ctx *fiber.Ctx) ([]byte, error) {
 
 	// Check if file size exceeds the maximum allowed size
 	if file.Size > MaximumFileSize {
-		return nil, errors.New("file size exceeds the maximum allowed size")
+		return nil, errs.New(errs.CodePayloadTooLarge)
 	}
 
 	// Open the file from the form
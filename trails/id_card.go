@@ -0,0 +1,188 @@
+package trails
+
+import (
+	"bytes"
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/go-fonts/dejavu/dejavusans"
+	"github.com/go-fonts/dejavu/dejavusansbold"
+	"github.com/go-pdf/fpdf"
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	"image/png"
+	"strconv"
+)
+
+const (
+	BarcodeCode128 = "code128"
+	BarcodeQR      = "qr"
+
+	// ID-1 card size (85.6 x 54 mm) at 300 DPI
+	idCardWidth    = 1011
+	idCardHeight   = 638
+	idCardWidthMM  = 85.6
+	idCardHeightMM = 54.0
+)
+
+type IDCard struct {
+	Institution string
+	StudentNo   string
+	Firstname   string
+	Lastname    string
+	ValidTerm   string
+	ValidUntil  string
+	Photo       []byte
+	Barcode     string
+}
+
+// RenderIDCardPNG draws a print-ready 300 DPI card with photo, name, student ID, validity and a barcode
+func RenderIDCardPNG(card IDCard) ([]byte, error) {
+	canvas := image.NewRGBA(image.Rect(0, 0, idCardWidth, idCardHeight))
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	headerColor := color.RGBA{R: 0, G: 62, B: 126, A: 255}
+	draw.Draw(canvas, image.Rect(0, 0, idCardWidth, 120), image.NewUniform(headerColor), image.Point{}, draw.Src)
+
+	titleFace, err := newFontFace(dejavusansbold.TTF, 46)
+	if err != nil {
+		return nil, err
+	}
+	nameFace, err := newFontFace(dejavusansbold.TTF, 40)
+	if err != nil {
+		return nil, err
+	}
+	textFace, err := newFontFace(dejavusans.TTF, 30)
+	if err != nil {
+		return nil, err
+	}
+	drawText(canvas, titleFace, color.White, 40, 78, card.Institution)
+	drawText(canvas, textFace, color.White, 40, 112, "STUDENT IDENTITY CARD")
+
+	// Photo box keeps a 4:5 portrait ratio; a grey placeholder stands in for a missing photo
+	photoRect := image.Rect(40, 150, 280, 450)
+	draw.Draw(canvas, photoRect, image.NewUniform(color.RGBA{R: 220, G: 220, B: 220, A: 255}), image.Point{}, draw.Src)
+	if len(card.Photo) > 0 {
+		photo, _, err := image.Decode(bytes.NewReader(card.Photo))
+		if err == nil {
+			draw.CatmullRom.Scale(canvas, photoRect, photo, coverRect(photo.Bounds(), photoRect), draw.Src, nil)
+		}
+	}
+
+	black := color.RGBA{A: 255}
+	drawText(canvas, nameFace, black, 320, 200, card.Firstname+" "+card.Lastname)
+	drawText(canvas, textFace, black, 320, 260, "Student ID: "+card.StudentNo)
+	if card.ValidTerm != "" {
+		drawText(canvas, textFace, black, 320, 305, "Term: "+card.ValidTerm)
+	}
+	if card.ValidUntil != "" {
+		drawText(canvas, textFace, black, 320, 350, "Valid until: "+card.ValidUntil)
+	}
+
+	code, err := renderBarcode(card.StudentNo, card.Barcode)
+	if err != nil {
+		return nil, err
+	}
+	if card.Barcode == BarcodeQR {
+		draw.Draw(canvas, image.Rect(790, 390, 790+code.Bounds().Dx(), 390+code.Bounds().Dy()), code, code.Bounds().Min, draw.Src)
+	} else {
+		// A long student number encodes wider than the space beside the photo, so shift it left to stay on the card
+		x := 320
+		if x+code.Bounds().Dx() > idCardWidth-40 {
+			x = max(40, idCardWidth-40-code.Bounds().Dx())
+		}
+		draw.Draw(canvas, image.Rect(x, 480, x+code.Bounds().Dx(), 480+code.Bounds().Dy()), code, code.Bounds().Min, draw.Src)
+	}
+
+	var buffer bytes.Buffer
+	if err = png.Encode(&buffer, canvas); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// RenderIDCardsPDF places rendered card PNGs on A4 sheets, two columns by five rows
+func RenderIDCardsPDF(cards [][]byte) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetAutoPageBreak(false, 0)
+	const columns, rows = 2, 5
+	marginX := (210 - columns*idCardWidthMM) / 3
+	marginY := (297 - rows*idCardHeightMM) / 6
+	for i, card := range cards {
+		slot := i % (columns * rows)
+		if slot == 0 {
+			pdf.AddPage()
+		}
+		x := marginX + float64(slot%columns)*(idCardWidthMM+marginX)
+		y := marginY + float64(slot/columns)*(idCardHeightMM+marginY)
+		name := "card-" + strconv.Itoa(i)
+		options := fpdf.ImageOptions{ImageType: "PNG"}
+		pdf.RegisterImageOptionsReader(name, options, bytes.NewReader(card))
+		pdf.ImageOptions(name, x, y, idCardWidthMM, idCardHeightMM, false, options, 0, "")
+		// Thin cut line around each card
+		pdf.SetDrawColor(180, 180, 180)
+		pdf.Rect(x, y, idCardWidthMM, idCardHeightMM, "D")
+	}
+	if len(cards) == 0 {
+		pdf.AddPage()
+	}
+	var buffer bytes.Buffer
+	if err := pdf.Output(&buffer); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func renderBarcode(content, kind string) (image.Image, error) {
+	if kind == BarcodeQR {
+		qr, err := qrcode.New(content, qrcode.Medium)
+		if err != nil {
+			return nil, err
+		}
+		qr.DisableBorder = true
+		return qr.Image(190), nil
+	}
+	code, err := code128.Encode(content)
+	if err != nil {
+		return nil, err
+	}
+	// Scale only stretches whole modules, so a code wider than 640 keeps its own width
+	return barcode.Scale(code, max(640, code.Bounds().Dx()), 120)
+}
+
+func newFontFace(ttf []byte, size float64) (font.Face, error) {
+	parsed, err := opentype.Parse(ttf)
+	if err != nil {
+		return nil, err
+	}
+	return opentype.NewFace(parsed, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+}
+
+func drawText(canvas *image.RGBA, face font.Face, textColor color.Color, x, y int, text string) {
+	drawer := font.Drawer{
+		Dst:  canvas,
+		Src:  image.NewUniform(textColor),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	drawer.DrawString(text)
+}
+
+// coverRect crops the source to the target's aspect ratio around its centre
+func coverRect(source, target image.Rectangle) image.Rectangle {
+	sourceRatio := float64(source.Dx()) / float64(source.Dy())
+	targetRatio := float64(target.Dx()) / float64(target.Dy())
+	if sourceRatio > targetRatio {
+		width := int(float64(source.Dy()) * targetRatio)
+		offset := (source.Dx() - width) / 2
+		return image.Rect(source.Min.X+offset, source.Min.Y, source.Min.X+offset+width, source.Max.Y)
+	}
+	height := int(float64(source.Dx()) / targetRatio)
+	offset := (source.Dy() - height) / 2
+	return image.Rect(source.Min.X, source.Min.Y+offset, source.Max.X, source.Min.Y+offset+height)
+}
@@ -0,0 +1,303 @@
+package trails
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"go_starter/errs"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+	"image"
+	"image/jpeg"
+	_ "image/png"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MinimumImageDimension is the smallest width or height accepted for a photo
+	MinimumImageDimension = 64
+	// MaximumImageDimension rejects images wider or taller than this before decoding
+	MaximumImageDimension = 8192
+	// MaximumImagePixels guards against decompression bombs (small files that decode huge)
+	MaximumImagePixels = 50 * 1000 * 1000
+	// NormalizedImageDimension is the longest side stored after normalization
+	NormalizedImageDimension = 2048
+
+	NormalizedImageExtension   = ".jpg"
+	NormalizedImageContentType = "image/jpeg"
+)
+
+// SniffImageType identifies an image by its magic bytes, ignoring the file name and headers
+func SniffImageType(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return "png"
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8, 0xFF}):
+		return "jpeg"
+	case len(data) >= 12 && bytes.Equal(data[0:4], []byte("RIFF")) && bytes.Equal(data[8:12], []byte("WEBP")):
+		return "webp"
+	}
+	return ""
+}
+
+// NormalizeImage validates an uploaded image and re-encodes it as a JPEG. The EXIF
+// orientation is applied to the pixels and all metadata is dropped by the re-encode.
+func NormalizeImage(data []byte) ([]byte, error) {
+	format := SniffImageType(data)
+	if format == "" {
+		return nil, errs.New(errs.CodeUnsupportedImage)
+	}
+
+	// Check the declared size before allocating any pixels
+	imageConfig, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errs.New(errs.CodeCorruptedImage)
+	}
+	width, height := imageConfig.Width, imageConfig.Height
+	if width < MinimumImageDimension || height < MinimumImageDimension {
+		return nil, errs.New(errs.CodeImageTooSmall).WithParam("pixels", strconv.Itoa(MinimumImageDimension))
+	}
+	if width > MaximumImageDimension || height > MaximumImageDimension || width*height > MaximumImagePixels {
+		return nil, errs.New(errs.CodeImageTooLarge).WithParam("pixels", strconv.Itoa(MaximumImageDimension))
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errs.New(errs.CodeCorruptedImage)
+	}
+	if format == "jpeg" {
+		decoded = applyOrientation(decoded, jpegOrientation(data))
+	}
+	return encodeJPEG(fitImage(decoded, NormalizedImageDimension))
+}
+
+// fitImage scales the image down so its longest side is at most maxDimension
+func fitImage(source image.Image, maxDimension int) image.Image {
+	bounds := source.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxDimension && height <= maxDimension {
+		return source
+	}
+	if width >= height {
+		height = height * maxDimension / width
+		width = maxDimension
+	} else {
+		width = width * maxDimension / height
+		height = maxDimension
+	}
+	target := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(target, target.Bounds(), source, bounds, draw.Src, nil)
+	return target
+}
+
+// jpegOrientation reads the EXIF orientation tag (1-8) from a JPEG, defaulting to 1
+func jpegOrientation(data []byte) int {
+	offset := 2
+	for offset+4 <= len(data) {
+		if data[offset] != 0xFF {
+			return 1
+		}
+		marker := data[offset+1]
+		length := int(binary.BigEndian.Uint16(data[offset+2 : offset+4]))
+		// Start of scan: no more metadata segments follow
+		if marker == 0xDA || length < 2 || offset+2+length > len(data) {
+			return 1
+		}
+		segment := data[offset+4 : offset+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		offset += 2 + length
+	}
+	return 1
+}
+
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[0:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd : ifd+2]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry+8 : entry+10]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+	return 1
+}
+
+// applyOrientation rotates and mirrors the pixels so the image displays upright without EXIF
+func applyOrientation(source image.Image, orientation int) image.Image {
+	if orientation <= 1 {
+		return source
+	}
+	bounds := source.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	// Orientations 5-8 swap width and height
+	targetWidth, targetHeight := width, height
+	if orientation >= 5 {
+		targetWidth, targetHeight = height, width
+	}
+	target := image.NewRGBA(image.Rect(0, 0, targetWidth, targetHeight))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var tx, ty int
+			switch orientation {
+			case 2:
+				tx, ty = width-1-x, y
+			case 3:
+				tx, ty = width-1-x, height-1-y
+			case 4:
+				tx, ty = x, height-1-y
+			case 5:
+				tx, ty = y, x
+			case 6:
+				tx, ty = height-1-y, x
+			case 7:
+				tx, ty = height-1-y, width-1-x
+			case 8:
+				tx, ty = y, width-1-x
+			}
+			target.Set(tx, ty, source.At(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+	return target
+}
+
+const (
+	ThumbnailVariant   = "thumb"
+	ThumbnailDimension = 150
+	// ResizeStep rounds on-the-fly widths up so the resize cache stays small
+	ResizeStep = 32
+)
+
+// ImageVariantWidths are pre-generated for every upload, named "w<width>"
+var ImageVariantWidths = []int{320, 640, 1024}
+
+// ImageVariantNames lists every variant generated next to the original
+func ImageVariantNames() []string {
+	names := []string{ThumbnailVariant}
+	for _, width := range ImageVariantWidths {
+		names = append(names, fmt.Sprintf("w%d", width))
+	}
+	return names
+}
+
+// ImageVariantKey stores a variant next to its original, e.g. "x.jpg" becomes "x_thumb.jpg"
+func ImageVariantKey(key, variant string) string {
+	extension := path.Ext(key)
+	return strings.TrimSuffix(key, extension) + "_" + variant + extension
+}
+
+// ParseImageVariantKey splits a variant key back into its original key and variant name
+func ParseImageVariantKey(key string) (string, string, bool) {
+	extension := path.Ext(key)
+	base := strings.TrimSuffix(key, extension)
+	index := strings.LastIndex(base, "_")
+	if index < 0 {
+		return "", "", false
+	}
+	variant := base[index+1:]
+	if _, ok := variantWidth(variant); !ok && variant != ThumbnailVariant {
+		return "", "", false
+	}
+	return base[:index] + extension, variant, true
+}
+
+// ResizeVariant names the cached variant of an on-the-fly resize, rounding the width up to ResizeStep
+func ResizeVariant(width int) string {
+	width = (width + ResizeStep - 1) / ResizeStep * ResizeStep
+	if width > NormalizedImageDimension {
+		width = NormalizedImageDimension
+	}
+	return fmt.Sprintf("w%d", width)
+}
+
+// GenerateImageVariants renders the thumbnail and width variants of a normalized image
+func GenerateImageVariants(data []byte) (map[string][]byte, error) {
+	variants := map[string][]byte{}
+	for _, name := range ImageVariantNames() {
+		variant, err := RenderImageVariant(data, name)
+		if err != nil {
+			return nil, err
+		}
+		variants[name] = variant
+	}
+	return variants, nil
+}
+
+// RenderImageVariant renders a single variant, the thumbnail is a centred square crop
+// and "w<width>" scales to that width without enlarging the image
+func RenderImageVariant(data []byte, variant string) ([]byte, error) {
+	source, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if variant == ThumbnailVariant {
+		thumbnail := image.NewRGBA(image.Rect(0, 0, ThumbnailDimension, ThumbnailDimension))
+		draw.CatmullRom.Scale(thumbnail, thumbnail.Bounds(), source, coverRect(source.Bounds(), thumbnail.Bounds()), draw.Src, nil)
+		return encodeJPEG(thumbnail)
+	}
+	width, ok := variantWidth(variant)
+	if !ok {
+		return nil, fmt.Errorf("unknown image variant %q", variant)
+	}
+	return encodeJPEG(resizeWidth(source, width))
+}
+
+func variantWidth(variant string) (int, bool) {
+	width, err := strconv.Atoi(strings.TrimPrefix(variant, "w"))
+	if !strings.HasPrefix(variant, "w") || err != nil || width < ResizeStep || width > NormalizedImageDimension || width%ResizeStep != 0 {
+		return 0, false
+	}
+	return width, true
+}
+
+func resizeWidth(source image.Image, width int) image.Image {
+	bounds := source.Bounds()
+	if width >= bounds.Dx() {
+		return source
+	}
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+	target := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(target, target.Bounds(), source, bounds, draw.Src, nil)
+	return target
+}
+
+// encodeJPEG composites the image onto white first; JPEG has no alpha and would otherwise
+// turn transparent PNG and WebP pixels black
+func encodeJPEG(source image.Image) ([]byte, error) {
+	flattened := image.NewRGBA(source.Bounds())
+	draw.Draw(flattened, flattened.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(flattened, flattened.Bounds(), source, source.Bounds().Min, draw.Over)
+	var buffer bytes.Buffer
+	if err := jpeg.Encode(&buffer, flattened, &jpeg.Options{Quality: 90}); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
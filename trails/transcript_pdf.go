@@ -0,0 +1,109 @@
+package trails
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/go-pdf/fpdf"
+	"github.com/skip2/go-qrcode"
+)
+
+type TranscriptCourse struct {
+	SubjectName string
+	ClassName   string
+	Credits     int
+	Letter      string
+	GradePoint  float64
+}
+
+type TranscriptTerm struct {
+	Name    string
+	Courses []TranscriptCourse
+	Credits int
+	GPA     float64
+}
+
+type TranscriptDocument struct {
+	SerialNo      string
+	StudentNo     string
+	Firstname     string
+	Lastname      string
+	Birthday      string
+	IssuedAt      string
+	Terms         []TranscriptTerm
+	TotalCredits  int
+	CumulativeGPA float64
+	VerifyURL     string
+}
+
+// RenderTranscriptPDF lays out an A4 transcript with a QR code pointing at VerifyURL
+func RenderTranscriptPDF(document TranscriptDocument) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	addDocumentFont(pdf)
+	pdf.SetTitle("Official Transcript "+document.SerialNo, true)
+	pdf.SetSubject(document.SerialNo, true)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont(documentFont, "I", 8)
+		pdf.CellFormat(0, 10, fmt.Sprintf("Serial %s - verify at %s - page %d", document.SerialNo, document.VerifyURL, pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+
+	pdf.SetFont(documentFont, "B", 16)
+	pdf.CellFormat(0, 10, "OFFICIAL TRANSCRIPT", "", 1, "C", false, 0, "")
+	pdf.Ln(4)
+
+	qrPNG, err := qrcode.Encode(document.VerifyURL, qrcode.Medium, 256)
+	if err != nil {
+		return nil, err
+	}
+	pdf.RegisterImageOptionsReader("verify-qr", fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(qrPNG))
+	pdf.ImageOptions("verify-qr", 165, 12, 30, 30, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+
+	pdf.SetFont(documentFont, "", 11)
+	studentRows := [][2]string{
+		{"Student ID", document.StudentNo},
+		{"Name", document.Firstname + " " + document.Lastname},
+		{"Date of birth", document.Birthday},
+		{"Issued at", document.IssuedAt},
+		{"Serial no.", document.SerialNo},
+	}
+	for _, row := range studentRows {
+		pdf.CellFormat(35, 7, row[0]+":", "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 7, row[1], "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(6)
+
+	widths := []float64{70, 45, 20, 25, 25}
+	headers := []string{"Subject", "Class", "Credits", "Grade", "Points"}
+	for _, term := range document.Terms {
+		pdf.SetFont(documentFont, "B", 12)
+		pdf.CellFormat(0, 8, "Term "+term.Name, "", 1, "L", false, 0, "")
+		pdf.SetFont(documentFont, "B", 10)
+		pdf.SetFillColor(230, 230, 230)
+		for i, header := range headers {
+			pdf.CellFormat(widths[i], 7, header, "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont(documentFont, "", 10)
+		for _, course := range term.Courses {
+			pdf.CellFormat(widths[0], 7, course.SubjectName, "1", 0, "L", false, 0, "")
+			pdf.CellFormat(widths[1], 7, course.ClassName, "1", 0, "L", false, 0, "")
+			pdf.CellFormat(widths[2], 7, fmt.Sprintf("%d", course.Credits), "1", 0, "C", false, 0, "")
+			pdf.CellFormat(widths[3], 7, course.Letter, "1", 0, "C", false, 0, "")
+			pdf.CellFormat(widths[4], 7, fmt.Sprintf("%.2f", course.GradePoint), "1", 1, "C", false, 0, "")
+		}
+		pdf.SetFont(documentFont, "I", 10)
+		pdf.CellFormat(0, 7, fmt.Sprintf("Term credits: %d    Term GPA: %.2f", term.Credits, term.GPA), "", 1, "R", false, 0, "")
+		pdf.Ln(3)
+	}
+
+	pdf.SetFont(documentFont, "B", 11)
+	pdf.CellFormat(0, 8, fmt.Sprintf("Total credits: %d    Cumulative GPA: %.2f", document.TotalCredits, document.CumulativeGPA), "T", 1, "R", false, 0, "")
+
+	var buffer bytes.Buffer
+	if err = pdf.Output(&buffer); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
@@ -1,29 +1,112 @@
 package validation
 
-import "github.com/go-playground/validator/v10"
+import (
+	"github.com/go-playground/validator/v10"
+	"go_starter/i18n"
+	"reflect"
+	"strings"
+)
 
 //import "github.com/go-playground/validator/v10"
 
-type ErrorResp struct {
-	Error string
+// validate is shared by every request, the validator caches the rules of each struct
+// it has seen
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(fieldName)
+	registerRules(v)
+	return v
+}
+
+// fieldName is the name a client knows a field by, its json key, or the form or query
+// key of fields read from elsewhere
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form", "query", "params"} {
+		name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return toSnakeCase(field.Name)
+}
+
+// FieldError is one failed rule of a request field
+type FieldError struct {
+	// Field is the path of the field, e.g. grades[1].letter
+	Field string
+	Tag   string
+	Param string
+	// Kind is the kind of the field value, min and max read as a length for strings
+	// and slices
+	Kind reflect.Kind
+}
+
+// Errors are all failed rules of a request
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fieldError := range e {
+		messages = append(messages, fieldError.Field+" "+fieldError.Message(i18n.DefaultLanguage))
+	}
+	return strings.Join(messages, ", ")
+}
+
+// Messages returns the messages of each field in language
+func (e Errors) Messages(language string) map[string][]string {
+	messages := make(map[string][]string, len(e))
+	for _, fieldError := range e {
+		messages[fieldError.Field] = append(messages[fieldError.Field], fieldError.Message(language))
+	}
+	return messages
 }
 
-func Validate(request interface{}) []*ErrorResp {
-	var errors []*ErrorResp
-	var validate = validator.New()
+// Validate checks request against its validate tags and returns every failure, nil
+// when the request is valid
+func Validate(request interface{}) Errors {
 	err := validate.Struct(request)
-	if err != nil {
-		for _, err := range err.(validator.ValidationErrors) {
-			var element ErrorResp
-			param := ""
-			if err.Param() != "" {
-				param = " " + err.Param()
+	if err == nil {
+		return nil
+	}
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		// not a struct, a programming error rather than a bad request
+		panic(err)
+	}
+	errors := make(Errors, 0, len(validationErrors))
+	for _, err := range validationErrors {
+		errors = append(errors, FieldError{
+			Field: fieldPath(err.Namespace()),
+			Tag:   err.Tag(),
+			Param: err.Param(),
+			Kind:  err.Kind(),
+		})
+	}
+	return errors
+}
+
+// fieldPath drops the struct name the namespace starts with
+func fieldPath(namespace string) string {
+	if index := strings.Index(namespace, "."); index >= 0 {
+		return namespace[index+1:]
+	}
+	return namespace
+}
+
+func toSnakeCase(name string) string {
+	var builder strings.Builder
+	for i, r := range name {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 && !(name[i-1] >= 'A' && name[i-1] <= 'Z') {
+				builder.WriteByte('_')
 			}
-			element.Error = err.StructField() + " " + err.Tag() + param
-			errors = append(errors, &element)
+			r += 'a' - 'A'
 		}
+		builder.WriteRune(r)
 	}
-	return errors
+	return builder.String()
 }
 
 type ErrorResponse struct {
@@ -34,7 +117,6 @@ type ErrorResponse struct {
 
 func ValidateStruct(myStruct interface{}) string {
 	var errorX []*ErrorResponse
-	validate := validator.New()
 	err := validate.Struct(myStruct)
 	if err != nil {
 		for _, err := range err.(validator.ValidationErrors) {
@@ -0,0 +1,29 @@
+package validation
+
+import (
+	"go_starter/i18n"
+	"reflect"
+	"strings"
+)
+
+// Message returns the message of the failed rule in language, see the validation keys
+// of the i18n catalog
+func (f FieldError) Message(language string) string {
+	key := "validation." + f.Tag + kindSuffix(f.Kind)
+	if !i18n.Has(key) {
+		if key = "validation." + f.Tag; !i18n.Has(key) {
+			key = "validation.invalid"
+		}
+	}
+	return i18n.Translate(language, key, map[string]string{"param": strings.ReplaceAll(f.Param, " ", ", ")})
+}
+
+func kindSuffix(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return ":length"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return ":items"
+	}
+	return ""
+}
@@ -0,0 +1,58 @@
+package validation
+
+import (
+	"github.com/go-playground/validator/v10"
+	"go_starter/config"
+	"regexp"
+	"time"
+)
+
+// DateLayout is the DD-MM-YYYY layout of every date a request carries
+const DateLayout = "02-01-2006"
+
+// laoPhone matches a Lao number as dialled inside the country, a mobile number
+// 20XXXXXXXX or a number with its area or operator prefix, e.g. 021XXXXXX or 030XXXXXXX
+var laoPhone = regexp.MustCompile(`^(20\d{8}|0[2-9]\d{7,8})$`)
+
+// datePattern is the shape of DateLayout, the rule itself also checks the date exists
+const datePattern = `^\d{2}-\d{2}-\d{4}$`
+
+func studentIDPattern() string {
+	return config.GetEnv("validation.student_id_pattern", `^[A-Za-z0-9][A-Za-z0-9/-]{2,19}$`)
+}
+
+// Pattern returns the regular expression a value of the rule tag must match, for the
+// rules of this package that are one
+func Pattern(tag string) (string, bool) {
+	switch tag {
+	case "lao_phone":
+		return laoPhone.String(), true
+	case "student_id":
+		return studentIDPattern(), true
+	case "date":
+		return datePattern, true
+	}
+	return "", false
+}
+
+func registerRules(v *validator.Validate) {
+	studentID := regexp.MustCompile(studentIDPattern())
+
+	rules := map[string]validator.Func{
+		"lao_phone": func(field validator.FieldLevel) bool {
+			return laoPhone.MatchString(field.Field().String())
+		},
+		"student_id": func(field validator.FieldLevel) bool {
+			return studentID.MatchString(field.Field().String())
+		},
+		"date": func(field validator.FieldLevel) bool {
+			_, err := time.Parse(DateLayout, field.Field().String())
+			return err == nil
+		},
+	}
+	for tag, rule := range rules {
+		if err := v.RegisterValidation(tag, rule); err != nil {
+			panic(err)
+		}
+	}
+}